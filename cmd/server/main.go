@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	_ "time/tzdata"
 
 	"be-golang/internal/app"
 	"be-golang/internal/config"
@@ -15,22 +16,92 @@ import (
 func main() {
 	loadEnvFile(".env")
 	cfg := config.Config{
-		PostgresDSN:    firstNonEmpty(os.Getenv("POSTGRES_DSN"), os.Getenv("DATABASE_URL")),
-		JWTSecret:      os.Getenv("JWT_SECRET"),
-		TursoURL:       os.Getenv("TURSO_URL"),
-		TursoToken:     os.Getenv("TURSO_TOKEN"),
-		N8NWebhookURL:  os.Getenv("N8N_WEBHOOK_URL"),
-		ServerAddr:     envString("SERVER_ADDR", ":8080"),
-		TokenTTL:       envDuration("TOKEN_TTL", time.Hour*24),
-		AdminOnlyPaths: []string{"/admin", "/services"},
+		PostgresDSN:       firstNonEmpty(os.Getenv("POSTGRES_DSN"), os.Getenv("DATABASE_URL")),
+		JWTSecret:         os.Getenv("JWT_SECRET"),
+		TursoURL:          os.Getenv("TURSO_URL"),
+		TursoToken:        os.Getenv("TURSO_TOKEN"),
+		N8NWebhookURL:     os.Getenv("N8N_WEBHOOK_URL"),
+		N8NWebhookSecrets: envList("N8N_WEBHOOK_SECRETS"),
+		InboundSecrets:    envList("INBOUND_WEBHOOK_SECRETS"),
+		InboundAPIKeys:    envList("INBOUND_API_KEYS"),
+		SMTPHost:          os.Getenv("SMTP_HOST"),
+		SMTPPort:          envInt("SMTP_PORT", 587),
+		SMTPUsername:      os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:      os.Getenv("SMTP_PASSWORD"),
+		EmailFrom:         os.Getenv("EMAIL_FROM"),
+		EmailTo:           envList("EMAIL_TO"),
+		EmailLanguage:     envString("EMAIL_LANGUAGE", "id"),
+		ServerAddr:        envString("SERVER_ADDR", ":8080"),
+		TokenTTL:          envDuration("TOKEN_TTL", time.Hour*24),
+		SlotCapacity:      envInt("SLOT_CAPACITY", 1),
+		WaitlistOfferTTL:  envDuration("WAITLIST_OFFER_TTL", 30*time.Minute),
+		ReminderOffsets:   envDurationList("REMINDER_OFFSETS", []time.Duration{24 * time.Hour, 2 * time.Hour}),
+		AdminOnlyPaths:    []string{"/admin", "/services"},
+
+		MessagingProvider:       os.Getenv("MESSAGING_PROVIDER"),
+		MessagingChannel:        envString("MESSAGING_CHANNEL", "whatsapp"),
+		MessagingLanguage:       envString("MESSAGING_LANGUAGE", "id"),
+		MessagingTemplateDir:    os.Getenv("MESSAGING_TEMPLATE_DIR"),
+		GatewayURL:              os.Getenv("MESSAGING_GATEWAY_URL"),
+		GatewayToken:            os.Getenv("MESSAGING_GATEWAY_TOKEN"),
+		GatewayAuthHeader:       os.Getenv("MESSAGING_GATEWAY_AUTH_HEADER"),
+		GatewayBody:             os.Getenv("MESSAGING_GATEWAY_BODY"),
+		GatewayContentType:      os.Getenv("MESSAGING_GATEWAY_CONTENT_TYPE"),
+		GatewayIDField:          envString("MESSAGING_GATEWAY_ID_FIELD", "id"),
+		MessagingCallbackTokens: envList("MESSAGING_CALLBACK_TOKENS"),
+
+		IdempotencyTTL: envDuration("IDEMPOTENCY_TTL_SECONDS", 24*time.Hour),
+
+		UploadDir:     envString("UPLOAD_DIR", "uploads"),
+		UploadBaseURL: envString("UPLOAD_BASE_URL", "/uploads"),
+
+		PaymentProvider:           os.Getenv("PAYMENT_PROVIDER"),
+		PaymentMethod:             envString("PAYMENT_METHOD", "qris"),
+		DepositPercent:            envInt("PAYMENT_DEPOSIT_PERCENT", 50),
+		PaymentTTL:                envDuration("PAYMENT_TTL", 30*time.Minute),
+		PaymentGatewayName:        os.Getenv("PAYMENT_GATEWAY_NAME"),
+		PaymentGatewayURL:         os.Getenv("PAYMENT_GATEWAY_URL"),
+		PaymentGatewayRefundURL:   os.Getenv("PAYMENT_GATEWAY_REFUND_URL"),
+		PaymentGatewayToken:       os.Getenv("PAYMENT_GATEWAY_TOKEN"),
+		PaymentGatewayAuthHeader:  os.Getenv("PAYMENT_GATEWAY_AUTH_HEADER"),
+		PaymentGatewayBody:        os.Getenv("PAYMENT_GATEWAY_BODY"),
+		PaymentGatewayRefundBody:  os.Getenv("PAYMENT_GATEWAY_REFUND_BODY"),
+		PaymentGatewayContentType: os.Getenv("PAYMENT_GATEWAY_CONTENT_TYPE"),
+		PaymentGatewayIDField:     envString("PAYMENT_GATEWAY_ID_FIELD", "id"),
+		PaymentGatewayQRField:     envString("PAYMENT_GATEWAY_QR_FIELD", "qr_string"),
+		PaymentGatewayVAField:     envString("PAYMENT_GATEWAY_VA_FIELD", "va_number"),
+		PaymentGatewayURLField:    envString("PAYMENT_GATEWAY_URL_FIELD", "payment_url"),
+		PaymentCallbackSecrets:    envList("PAYMENT_CALLBACK_SECRETS"),
+		PaymentSignatureHeader:    os.Getenv("PAYMENT_CALLBACK_SIGNATURE_HEADER"),
+		PaymentCallbackIDField:    os.Getenv("PAYMENT_CALLBACK_ID_FIELD"),
+		PaymentCallbackStatus:     os.Getenv("PAYMENT_CALLBACK_STATUS_FIELD"),
+		PaymentCallbackAmount:     os.Getenv("PAYMENT_CALLBACK_AMOUNT_FIELD"),
+		PaymentPaidStatuses:       envList("PAYMENT_PAID_STATUSES"),
+		PaymentExpiredStatuses:    envList("PAYMENT_EXPIRED_STATUSES"),
+
+		InvoicePrefix:          envString("INVOICE_PREFIX", "INV"),
+		InvoiceTaxPercent:      envInt("INVOICE_TAX_PERCENT", 0),
+		InvoiceLanguage:        envString("INVOICE_LANGUAGE", "id"),
+		InvoiceBusinessName:    os.Getenv("INVOICE_BUSINESS_NAME"),
+		InvoiceBusinessAddress: strings.ReplaceAll(os.Getenv("INVOICE_BUSINESS_ADDRESS"), `\n`, "\n"),
+		InvoiceBusinessPhone:   os.Getenv("INVOICE_BUSINESS_PHONE"),
+		InvoiceTaxID:           os.Getenv("INVOICE_TAX_ID"),
+		InvoiceLinkBaseURL:     os.Getenv("INVOICE_LINK_BASE_URL"),
+		InvoiceLinkSecret:      firstNonEmpty(os.Getenv("INVOICE_LINK_SECRET"), os.Getenv("JWT_SECRET")),
+		InvoiceLinkTTL:         envDuration("INVOICE_LINK_TTL", 30*24*time.Hour),
+	}
+	loc, err := time.LoadLocation(envString("BUSINESS_TIMEZONE", "Asia/Jakarta"))
+	if err != nil {
+		log.Fatal("invalid BUSINESS_TIMEZONE: ", err)
 	}
+	cfg.Location = loc
 	if p := os.Getenv("PORT"); p != "" {
 		cfg.ServerAddr = ":" + p
 	}
 	if cfg.PostgresDSN == "" || cfg.JWTSecret == "" {
 		log.Fatal("missing required environment variables")
 	}
-	err := app.Run(cfg)
+	err = app.Run(cfg)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -44,6 +115,18 @@ func envString(key, def string) string {
 	return v
 }
 
+func envInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
 func envDuration(key string, def time.Duration) time.Duration {
 	v := os.Getenv(key)
 	if v == "" {
@@ -56,6 +139,32 @@ func envDuration(key string, def time.Duration) time.Duration {
 	return time.Duration(d) * time.Second
 }
 
+func envList(key string) []string {
+	var out []string
+	for _, part := range strings.Split(os.Getenv(key), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func envDurationList(key string, def []time.Duration) []time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	var out []time.Duration
+	for _, part := range strings.Split(v, ",") {
+		d, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil || d <= 0 {
+			return def
+		}
+		out = append(out, d)
+	}
+	return out
+}
+
 func loadEnvFile(path string) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -0,0 +1,201 @@
+// Package invoicepdf renders invoices as A4 PDF documents with pkg/pdf.
+package invoicepdf
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/util"
+	"be-golang/pkg/pdf"
+)
+
+const defaultLanguage = "id"
+
+type labels struct {
+	Title, Number, Date, Booking, BillTo, Description, Qty, Price, Amount,
+	Subtotal, Discount, Total, TaxIncluded, TaxBase, Deposit, PaidAtVenue, Paid, TaxID, Thanks string
+}
+
+var languages = map[string]labels{
+	"id": {
+		Title: "INVOICE", Number: "No.", Date: "Tanggal", Booking: "Booking", BillTo: "Ditagihkan kepada",
+		Description: "Deskripsi", Qty: "Jml", Price: "Harga", Amount: "Jumlah",
+		Subtotal: "Subtotal", Discount: "Diskon", Total: "Total", TaxIncluded: "Termasuk PPN", TaxBase: "DPP",
+		Deposit: "Deposit dibayar", PaidAtVenue: "Dibayar di tempat", Paid: "LUNAS", TaxID: "NPWP",
+		Thanks: "Terima kasih atas kunjungan Anda.",
+	},
+	"en": {
+		Title: "INVOICE", Number: "No.", Date: "Date", Booking: "Booking", BillTo: "Bill to",
+		Description: "Description", Qty: "Qty", Price: "Price", Amount: "Amount",
+		Subtotal: "Subtotal", Discount: "Discount", Total: "Total", TaxIncluded: "Including tax", TaxBase: "Taxable amount",
+		Deposit: "Deposit paid", PaidAtVenue: "Paid at venue", Paid: "PAID", TaxID: "Tax ID",
+		Thanks: "Thank you for your visit.",
+	},
+}
+
+type Config struct {
+	BusinessName string
+	// Address may span several lines separated by "\n".
+	Address  string
+	Phone    string
+	TaxID    string
+	Language string
+}
+
+type Renderer struct {
+	cfg    Config
+	labels labels
+}
+
+func New(cfg Config) (*Renderer, error) {
+	if cfg.Language == "" {
+		cfg.Language = defaultLanguage
+	}
+	l, ok := languages[cfg.Language]
+	if !ok {
+		return nil, errors.New("invoicepdf: unsupported language " + cfg.Language)
+	}
+	return &Renderer{cfg: cfg, labels: l}, nil
+}
+
+// Page layout in points.
+const (
+	left       = 50.0
+	right      = pdf.PageWidth - 50
+	bottom     = pdf.PageHeight - 80
+	qtyRight   = 330.0
+	priceRight = 440.0
+	rowHeight  = 18.0
+)
+
+// Render expects inv to be localized already.
+func (r *Renderer) Render(inv domain.Invoice) ([]byte, error) {
+	l := r.labels
+	doc := pdf.New(l.Title + " " + inv.Number)
+	doc.Author = r.cfg.BusinessName
+	doc.Created = inv.IssuedAt
+	p := doc.AddPage()
+
+	y := 70.0
+	p.Text(left, y, pdf.HelveticaBold, 18, r.cfg.BusinessName)
+	p.TextRight(right, y, pdf.HelveticaBold, 18, l.Title)
+	y += 18
+	var contact []string
+	for _, line := range strings.Split(r.cfg.Address, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			contact = append(contact, line)
+		}
+	}
+	if r.cfg.Phone != "" {
+		contact = append(contact, r.cfg.Phone)
+	}
+	if r.cfg.TaxID != "" {
+		contact = append(contact, l.TaxID+": "+r.cfg.TaxID)
+	}
+	meta := [][2]string{
+		{l.Number, inv.Number},
+		{l.Date, inv.IssuedAt.Format("02/01/2006")},
+		{l.Booking, "#" + strconv.FormatInt(inv.BookingID, 10)},
+	}
+	for i := 0; i < len(contact) || i < len(meta); i++ {
+		if i < len(contact) {
+			p.Text(left, y, pdf.Helvetica, 9, contact[i])
+		}
+		if i < len(meta) {
+			p.Text(priceRight-40, y, pdf.Helvetica, 9, meta[i][0])
+			p.TextRight(right, y, pdf.HelveticaBold, 9, meta[i][1])
+		}
+		y += 13
+	}
+
+	y += 20
+	p.Text(left, y, pdf.Helvetica, 9, l.BillTo)
+	y += 15
+	p.Text(left, y, pdf.HelveticaBold, 11, inv.CustomerName)
+	y += 14
+	p.Text(left, y, pdf.Helvetica, 10, inv.CustomerPhone)
+
+	y += 30
+	header := func() {
+		p.FillRect(left, y-13, right-left, rowHeight, 0.9)
+		p.Text(left+5, y, pdf.HelveticaBold, 10, l.Description)
+		p.TextRight(qtyRight, y, pdf.HelveticaBold, 10, l.Qty)
+		p.TextRight(priceRight, y, pdf.HelveticaBold, 10, l.Price)
+		p.TextRight(right-5, y, pdf.HelveticaBold, 10, l.Amount)
+		y += rowHeight + 4
+	}
+	header()
+	for _, it := range inv.Items {
+		if y > bottom {
+			p = doc.AddPage()
+			y = 70
+			header()
+		}
+		p.Text(left+5, y, pdf.Helvetica, 10, fit(it.Name, qtyRight-left-40, 10))
+		p.TextRight(qtyRight, y, pdf.Helvetica, 10, strconv.Itoa(it.Quantity))
+		p.TextRight(priceRight, y, pdf.Helvetica, 10, util.FormatIDR(it.UnitPrice))
+		p.TextRight(right-5, y, pdf.Helvetica, 10, util.FormatIDR(it.Amount()))
+		y += rowHeight
+	}
+	p.Line(left, y-10, right, y-10, 0.5)
+
+	type row struct {
+		label, value string
+		bold         bool
+	}
+	rows := []row{{l.Subtotal, util.FormatIDR(inv.Subtotal), false}}
+	if inv.Discount > 0 {
+		label := l.Discount
+		if inv.PromoCode != "" {
+			label += " (" + inv.PromoCode + ")"
+		}
+		rows = append(rows, row{label, "-" + util.FormatIDR(inv.Discount), false})
+	}
+	rows = append(rows, row{l.Total, util.FormatIDR(inv.Total), true})
+	if inv.TaxPercent > 0 {
+		rows = append(rows,
+			row{l.TaxBase, util.FormatIDR(inv.Total - inv.Tax), false},
+			row{l.TaxIncluded + " " + strconv.Itoa(inv.TaxPercent) + "%", util.FormatIDR(inv.Tax), false},
+		)
+	}
+	if inv.Deposit > 0 {
+		rows = append(rows,
+			row{l.Deposit, util.FormatIDR(inv.Deposit), false},
+			row{l.PaidAtVenue, util.FormatIDR(inv.Balance()), false},
+		)
+	}
+	y += 8
+	if y+float64(len(rows))*rowHeight+60 > pdf.PageHeight-40 {
+		p = doc.AddPage()
+		y = 70
+	}
+	for _, rw := range rows {
+		font, size := pdf.Helvetica, 10.0
+		if rw.bold {
+			font, size = pdf.HelveticaBold, 12
+		}
+		p.Text(priceRight-40, y, font, size, rw.label)
+		p.TextRight(right-5, y, font, size, rw.value)
+		y += rowHeight
+	}
+
+	y += 20
+	p.Text(left, y, pdf.HelveticaBold, 14, l.Paid)
+	y += 30
+	p.Text(left, y, pdf.Helvetica, 9, l.Thanks)
+	return doc.Bytes()
+}
+
+// fit shortens s with "..." until it is at most width points wide.
+func fit(s string, width, size float64) string {
+	if pdf.TextWidth(pdf.Helvetica, size, s) <= width {
+		return s
+	}
+	r := []rune(s)
+	for len(r) > 0 && pdf.TextWidth(pdf.Helvetica, size, string(r)+"...") > width {
+		r = r[:len(r)-1]
+	}
+	return string(r) + "..."
+}
@@ -0,0 +1,84 @@
+package fiber
+
+import (
+	"bytes"
+	"strconv"
+
+	"be-golang/internal/usecase"
+	"be-golang/internal/util"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CalendarHandlers struct {
+	feed   *usecase.CalendarFeed
+	create *usecase.CalendarTokenCreate
+	list   *usecase.CalendarTokenList
+	revoke *usecase.CalendarTokenRevoke
+}
+
+func NewCalendarHandlers(cf *usecase.CalendarFeed, cc *usecase.CalendarTokenCreate, cl *usecase.CalendarTokenList, cr *usecase.CalendarTokenRevoke) *CalendarHandlers {
+	return &CalendarHandlers{feed: cf, create: cc, list: cl, revoke: cr}
+}
+
+func (h *CalendarHandlers) Register(app *fiber.App, auth fiber.Handler) {
+	app.Get("/calendar/:token.ics", h.getFeed)
+	app.Get("/admin/calendar/tokens", auth, h.listTokens)
+	app.Post("/admin/calendar/tokens", auth, h.createToken)
+	app.Delete("/admin/calendar/tokens/:id", auth, h.revokeToken)
+}
+
+func (h *CalendarHandlers) getFeed(c *fiber.Ctx) error {
+	serviceID, _ := strconv.ParseInt(c.Query("service_id"), 10, 64)
+	cal, err := h.feed.Exec(c.Params("token"), serviceID)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := util.WriteICalendar(&buf, *cal); err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderCacheControl, "private, max-age=300")
+	return c.Send(buf.Bytes())
+}
+
+func (h *CalendarHandlers) listTokens(c *fiber.Ctx) error {
+	items, err := h.list.Exec(currentUserID(c))
+	if err != nil {
+		return err
+	}
+	return c.JSON(items)
+}
+
+func (h *CalendarHandlers) createToken(c *fiber.Ctx) error {
+	var body struct {
+		Name      string `json:"name"`
+		ServiceID *int64 `json:"service_id"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	token, t, err := h.create.Exec(currentUserID(c), body.Name, body.ServiceID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{
+		"id":         t.ID,
+		"name":       t.Name,
+		"service_id": t.ServiceID,
+		"token":      token,
+		"url":        c.BaseURL() + "/calendar/" + token + ".ics",
+	})
+}
+
+func (h *CalendarHandlers) revokeToken(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	if err := h.revoke.Exec(currentUserID(c), id); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
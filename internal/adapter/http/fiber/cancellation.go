@@ -0,0 +1,159 @@
+package fiber
+
+import (
+	"strconv"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/usecase"
+	"be-golang/internal/util"
+	"be-golang/internal/validation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/etag"
+)
+
+type CancellationHandlers struct {
+	get     *usecase.CancellationPolicyGet
+	list    *usecase.CancellationPolicyList
+	save    *usecase.CancellationPolicySave
+	delete  *usecase.CancellationPolicyDelete
+	resolve *usecase.CancellationPolicyResolve
+}
+
+func NewCancellationHandlers(cg *usecase.CancellationPolicyGet, cl *usecase.CancellationPolicyList, cs *usecase.CancellationPolicySave, cd *usecase.CancellationPolicyDelete, cr *usecase.CancellationPolicyResolve) *CancellationHandlers {
+	return &CancellationHandlers{get: cg, list: cl, save: cs, delete: cd, resolve: cr}
+}
+
+func (h *CancellationHandlers) Register(app *fiber.App, auth fiber.Handler) {
+	app.Get("/admin/cancellation-policy", auth, h.getBusiness)
+	app.Put("/admin/cancellation-policy", auth, h.saveBusiness)
+	app.Get("/admin/cancellation-policy/services", auth, h.listServices)
+	app.Get("/admin/cancellation-policy/services/:id", auth, h.getService)
+	app.Put("/admin/cancellation-policy/services/:id", auth, h.saveService)
+	app.Delete("/admin/cancellation-policy/services/:id", auth, h.deleteService)
+	app.Get("/catalog/services/:id/cancellation-policy", etag.New(), h.catalogPolicy)
+}
+
+// cancellationPolicyResponse is the policy as shown to customers. The fee
+// amounts are only filled when a price is known, e.g. in a quote.
+type cancellationPolicyResponse struct {
+	FreeCancelMinutes       int    `json:"free_cancel_minutes"`
+	LateCancelFeePercent    int    `json:"late_cancel_fee_percent"`
+	NoShowFeePercent        int    `json:"no_show_fee_percent"`
+	LateCancelFee           *int64 `json:"late_cancel_fee,omitempty"`
+	LateCancelFeeFormatted  string `json:"late_cancel_fee_formatted,omitempty"`
+	NoShowFee               *int64 `json:"no_show_fee,omitempty"`
+	NoShowFeeFormatted      string `json:"no_show_fee_formatted,omitempty"`
+	FreeCancellationAnytime bool   `json:"free_cancellation_anytime"`
+}
+
+func newCancellationPolicyResponse(p domain.CancellationPolicy) cancellationPolicyResponse {
+	return cancellationPolicyResponse{
+		FreeCancelMinutes:       p.FreeCancelMinutes,
+		LateCancelFeePercent:    p.LateCancelFeePercent,
+		NoShowFeePercent:        p.NoShowFeePercent,
+		FreeCancellationAnytime: p.LateCancelFeePercent == 0,
+	}
+}
+
+// withFees fills the fee amounts for a booking total.
+func (r cancellationPolicyResponse) withFees(p domain.CancellationPolicy, total int64) cancellationPolicyResponse {
+	late, noShow := p.LateCancelFee(total), p.NoShowFee(total)
+	r.LateCancelFee, r.LateCancelFeeFormatted = &late, util.FormatIDR(late)
+	r.NoShowFee, r.NoShowFeeFormatted = &noShow, util.FormatIDR(noShow)
+	return r
+}
+
+func (h *CancellationHandlers) getBusiness(c *fiber.Ctx) error {
+	p, err := h.get.Exec(0)
+	if err != nil {
+		return err
+	}
+	return c.JSON(p)
+}
+
+func (h *CancellationHandlers) saveBusiness(c *fiber.Ctx) error {
+	return h.savePolicy(c, 0)
+}
+
+func (h *CancellationHandlers) listServices(c *fiber.Ctx) error {
+	items, err := h.list.Exec()
+	if err != nil {
+		return err
+	}
+	return c.JSON(items)
+}
+
+func (h *CancellationHandlers) getService(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	p, err := h.get.Exec(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(p)
+}
+
+func (h *CancellationHandlers) saveService(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	return h.savePolicy(c, id)
+}
+
+func (h *CancellationHandlers) deleteService(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	if err := h.delete.Exec(id); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *CancellationHandlers) savePolicy(c *fiber.Ctx, serviceID int64) error {
+	var body struct {
+		FreeCancelMinutes    int `json:"free_cancel_minutes"`
+		LateCancelFeePercent int `json:"late_cancel_fee_percent"`
+		NoShowFeePercent     int `json:"no_show_fee_percent"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	var v validation.Validator
+	v.Range("free_cancel_minutes", int64(body.FreeCancelMinutes), 0, domain.MaxFreeCancelMinutes)
+	v.Range("late_cancel_fee_percent", int64(body.LateCancelFeePercent), 0, 100)
+	v.Range("no_show_fee_percent", int64(body.NoShowFeePercent), 0, 100)
+	if err := v.Err(); err != nil {
+		return err
+	}
+	p, err := h.save.Exec(domain.CancellationPolicy{
+		ServiceID:            serviceID,
+		FreeCancelMinutes:    body.FreeCancelMinutes,
+		LateCancelFeePercent: body.LateCancelFeePercent,
+		NoShowFeePercent:     body.NoShowFeePercent,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(p)
+}
+
+// catalogPolicy shows customers the policy that applies to a service
+// before they book.
+func (h *CancellationHandlers) catalogPolicy(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	p, err := h.resolve.Exec(id)
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderCacheControl, "public, max-age="+strconv.Itoa(catalogMaxAge))
+	return c.JSON(newCancellationPolicyResponse(p))
+}
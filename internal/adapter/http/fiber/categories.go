@@ -0,0 +1,132 @@
+package fiber
+
+import (
+	"strconv"
+	"strings"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/usecase"
+	"be-golang/internal/validation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/etag"
+)
+
+type CategoryHandlers struct {
+	create  *usecase.CategoryCreate
+	update  *usecase.CategoryUpdate
+	delete  *usecase.CategoryDelete
+	list    *usecase.CategoryList
+	reorder *usecase.CategoryReorder
+}
+
+func NewCategoryHandlers(cc *usecase.CategoryCreate, cu *usecase.CategoryUpdate, cd *usecase.CategoryDelete, cl *usecase.CategoryList, co *usecase.CategoryReorder) *CategoryHandlers {
+	return &CategoryHandlers{create: cc, update: cu, delete: cd, list: cl, reorder: co}
+}
+
+func (h *CategoryHandlers) Register(app *fiber.App, auth fiber.Handler) {
+	app.Get("/admin/categories", auth, h.listCategories)
+	app.Post("/admin/categories", auth, h.createCategory)
+	app.Put("/admin/categories/order", auth, h.reorderCategories)
+	app.Patch("/admin/categories/:id", auth, h.updateCategory)
+	app.Delete("/admin/categories/:id", auth, h.deleteCategory)
+	app.Get("/catalog/categories", etag.New(), h.catalogCategories)
+}
+
+func (h *CategoryHandlers) listCategories(c *fiber.Ctx) error {
+	items, err := h.list.Exec()
+	if err != nil {
+		return err
+	}
+	return c.JSON(items)
+}
+
+func (h *CategoryHandlers) createCategory(c *fiber.Ctx) error {
+	var body struct {
+		Name      string `json:"name"`
+		SortOrder int    `json:"sort_order"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	var v validation.Validator
+	v.Name("name", body.Name)
+	if err := v.Err(); err != nil {
+		return err
+	}
+	id, err := h.create.Exec(domain.Category{Name: strings.TrimSpace(body.Name), SortOrder: body.SortOrder})
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"id": id})
+}
+
+func (h *CategoryHandlers) updateCategory(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	var body struct {
+		Name      *string `json:"name"`
+		SortOrder *int    `json:"sort_order"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	if body.Name != nil {
+		var v validation.Validator
+		v.Name("name", *body.Name)
+		if err := v.Err(); err != nil {
+			return err
+		}
+		name := strings.TrimSpace(*body.Name)
+		body.Name = &name
+	}
+	cat, err := h.update.Exec(id, usecase.CategoryUpdateInput{Name: body.Name, SortOrder: body.SortOrder})
+	if err != nil {
+		return err
+	}
+	return c.JSON(cat)
+}
+
+func (h *CategoryHandlers) deleteCategory(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	if err := h.delete.Exec(id); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *CategoryHandlers) reorderCategories(c *fiber.Ctx) error {
+	var body struct {
+		IDs []int64 `json:"ids"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	if len(body.IDs) == 0 {
+		return domain.Invalid("ids", "required")
+	}
+	if err := h.reorder.Exec(body.IDs); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// catalogCategories lists categories in display order for the public
+// booking form, cached like the service catalog.
+func (h *CategoryHandlers) catalogCategories(c *fiber.Ctx) error {
+	items, err := h.list.Exec()
+	if err != nil {
+		return err
+	}
+	out := make([]catalogCategory, 0, len(items))
+	for _, cat := range items {
+		out = append(out, catalogCategory{ID: cat.ID, Name: cat.Name})
+	}
+	c.Set(fiber.HeaderCacheControl, "public, max-age="+strconv.Itoa(catalogMaxAge))
+	return c.JSON(fiber.Map{"categories": out})
+}
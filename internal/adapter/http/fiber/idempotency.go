@@ -0,0 +1,103 @@
+package fiber
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	errIdempotencyKey        = domain.NewError(domain.KindInvalid, "invalid_idempotency_key")
+	errIdempotencyInProgress = domain.NewError(domain.KindConflict, "idempotency_in_progress")
+	errIdempotencyKeyReused  = domain.NewError(domain.KindValidation, "idempotency_key_reused")
+)
+
+const (
+	idempotencyHeader = "Idempotency-Key"
+	maxIdempotencyKey = 255
+	// idempotencyStaleAfter is how long an unfinished request holds its key
+	// before a retry may take it over, e.g. after a crash mid-request.
+	idempotencyStaleAfter = time.Minute
+)
+
+// Idempotency replays the stored response when a POST, PUT, PATCH or DELETE
+// request repeats an Idempotency-Key. Keys are scoped to the method, path
+// and Authorization header. Reusing a key with a different body or query
+// returns 422; a repeat while the first request is still running returns
+// 409. Responses to failed authentication and 5xx responses are not stored,
+// so those requests can be retried with the same key. Requests without the
+// header pass through untouched.
+func Idempotency(store ports.IdempotencyStore, ttl time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(idempotencyHeader)
+		if key == "" {
+			return c.Next()
+		}
+		switch c.Method() {
+		case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		default:
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKey {
+			return errIdempotencyKey
+		}
+
+		now := time.Now().UTC()
+		rec := domain.IdempotencyRecord{
+			Key:         key,
+			Scope:       c.Method() + " " + c.Path() + " " + digest([]byte(c.Get(fiber.HeaderAuthorization)))[:16],
+			Fingerprint: digest(append([]byte(c.Request().URI().QueryArgs().String()+"\n"), c.Body()...)),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		}
+		existing, err := store.Begin(rec, now.Add(-idempotencyStaleAfter))
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return err
+		}
+		if err != nil || (existing != nil && !existing.Completed() && existing.Fingerprint == rec.Fingerprint) {
+			return errIdempotencyInProgress
+		}
+		if existing != nil {
+			if existing.Fingerprint != rec.Fingerprint {
+				return errIdempotencyKeyReused
+			}
+			c.Set("Idempotent-Replayed", "true")
+			if existing.ContentType != "" {
+				c.Set(fiber.HeaderContentType, existing.ContentType)
+			}
+			return c.Status(existing.StatusCode).Send(existing.Body)
+		}
+
+		// Render handler errors here rather than in the app's ErrorHandler
+		// so that error responses such as 409 slot_full are stored too.
+		if err := c.Next(); err != nil {
+			if err := c.App().Config().ErrorHandler(c, err); err != nil {
+				_ = store.Release(rec.Key, rec.Scope)
+				return err
+			}
+		}
+		status := c.Response().StatusCode()
+		if status >= 500 || status == fiber.StatusUnauthorized || status == fiber.StatusForbidden {
+			_ = store.Release(rec.Key, rec.Scope)
+			return nil
+		}
+		rec.StatusCode = status
+		rec.ContentType = string(c.Response().Header.ContentType())
+		rec.Body = append([]byte(nil), c.Response().Body()...)
+		if err := store.Complete(rec); err != nil {
+			_ = store.Release(rec.Key, rec.Scope)
+		}
+		return nil
+	}
+}
+
+func digest(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,246 @@
+package fiber
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"be-golang/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// memIdempotency is an in-memory ports.IdempotencyStore with the same claim
+// rules as the Postgres store.
+type memIdempotency struct {
+	mu   sync.Mutex
+	recs map[string]domain.IdempotencyRecord
+}
+
+func (s *memIdempotency) Begin(r domain.IdempotencyRecord, staleBefore time.Time) (*domain.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := r.Key + "\x00" + r.Scope
+	if old, ok := s.recs[id]; ok {
+		expired := !old.ExpiresAt.After(r.CreatedAt)
+		stale := !old.Completed() && old.CreatedAt.Before(staleBefore)
+		if !expired && !stale {
+			return &old, nil
+		}
+	}
+	s.recs[id] = r
+	return nil, nil
+}
+
+func (s *memIdempotency) Complete(r domain.IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recs[r.Key+"\x00"+r.Scope] = r
+	return nil
+}
+
+func (s *memIdempotency) Release(key, scope string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.recs, key+"\x00"+scope)
+	return nil
+}
+
+func (s *memIdempotency) DeleteExpired(now time.Time) (int64, error) { return 0, nil }
+
+type idempotencyApp struct {
+	app   *fiber.App
+	store *memIdempotency
+	mu    sync.Mutex
+	count int
+	// started and release, when set, hold /slow until the test lets go.
+	started, release chan struct{}
+}
+
+func newIdempotencyApp() *idempotencyApp {
+	a := &idempotencyApp{store: &memIdempotency{recs: map[string]domain.IdempotencyRecord{}}}
+	a.app = fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	a.app.Use(Idempotency(a.store, time.Hour))
+	count := func() int {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.count++
+		return a.count
+	}
+	a.app.Post("/bookings", func(c *fiber.Ctx) error {
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"call": count()})
+	})
+	a.app.Get("/bookings", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"call": count()})
+	})
+	a.app.Post("/full", func(c *fiber.Ctx) error {
+		count()
+		return domain.NewError(domain.KindConflict, "slot_full")
+	})
+	a.app.Post("/broken", func(c *fiber.Ctx) error {
+		count()
+		return errors.New("database is down")
+	})
+	a.app.Post("/slow", func(c *fiber.Ctx) error {
+		count()
+		close(a.started)
+		<-a.release
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	return a
+}
+
+// calls reports how many times a handler ran.
+func (a *idempotencyApp) calls() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.count
+}
+
+type idempotencyResponse struct {
+	status   int
+	replayed bool
+	body     map[string]any
+}
+
+func (a *idempotencyApp) do(t *testing.T, method, path, key, auth, body string) idempotencyResponse {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	if key != "" {
+		req.Header.Set(idempotencyHeader, key)
+	}
+	if auth != "" {
+		req.Header.Set(fiber.HeaderAuthorization, auth)
+	}
+	res, err := a.app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := idempotencyResponse{status: res.StatusCode, replayed: res.Header.Get("Idempotent-Replayed") == "true"}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &out.body); err != nil {
+			t.Fatalf("%s %s: %v: %s", method, path, err, raw)
+		}
+	}
+	return out
+}
+
+func TestIdempotencyReplaysResponse(t *testing.T) {
+	a := newIdempotencyApp()
+	first := a.do(t, http.MethodPost, "/bookings", "k1", "Bearer a", `{"service_id":1}`)
+	again := a.do(t, http.MethodPost, "/bookings", "k1", "Bearer a", `{"service_id":1}`)
+	if first.status != fiber.StatusCreated || first.replayed {
+		t.Fatalf("first = %+v", first)
+	}
+	if again.status != fiber.StatusCreated || !again.replayed || again.body["call"] != first.body["call"] {
+		t.Errorf("repeat = %+v, want a replay of %+v", again, first)
+	}
+	if a.calls() != 1 {
+		t.Errorf("handler ran %d times, want 1", a.calls())
+	}
+}
+
+func TestIdempotencyScopes(t *testing.T) {
+	a := newIdempotencyApp()
+	a.do(t, http.MethodPost, "/bookings", "k1", "Bearer a", `{}`)
+	tests := []struct {
+		name               string
+		method, path, auth string
+		wantStatus         int
+		wantReplay         bool
+		wantCalls          int
+		wantCode           string
+	}{
+		{name: "other caller", method: http.MethodPost, path: "/bookings", auth: "Bearer b", wantStatus: fiber.StatusCreated, wantCalls: 2},
+		{name: "other path", method: http.MethodPost, path: "/full", auth: "Bearer a", wantStatus: fiber.StatusConflict, wantCalls: 3, wantCode: "slot_full"},
+		{name: "GET ignores the key", method: http.MethodGet, path: "/bookings", auth: "Bearer a", wantStatus: fiber.StatusOK, wantCalls: 4},
+		{name: "same caller replays", method: http.MethodPost, path: "/bookings", auth: "Bearer a", wantStatus: fiber.StatusCreated, wantReplay: true, wantCalls: 4},
+	}
+	for _, tt := range tests {
+		res := a.do(t, tt.method, tt.path, "k1", tt.auth, `{}`)
+		if res.status != tt.wantStatus || res.replayed != tt.wantReplay || a.calls() != tt.wantCalls {
+			t.Errorf("%s: status %d replayed %v after %d calls, want %d %v %d", tt.name, res.status, res.replayed, a.calls(), tt.wantStatus, tt.wantReplay, tt.wantCalls)
+		}
+		if tt.wantCode != "" && res.body["code"] != tt.wantCode {
+			t.Errorf("%s: code = %v, want %s", tt.name, res.body["code"], tt.wantCode)
+		}
+	}
+}
+
+func TestIdempotencyKeyReused(t *testing.T) {
+	a := newIdempotencyApp()
+	a.do(t, http.MethodPost, "/bookings", "k1", "", `{"service_id":1}`)
+	for _, tt := range []struct{ path, body string }{
+		{"/bookings", `{"service_id":2}`},
+		{"/bookings?dry_run=1", `{"service_id":1}`},
+	} {
+		res := a.do(t, http.MethodPost, tt.path, "k1", "", tt.body)
+		if res.status != fiber.StatusUnprocessableEntity || res.body["code"] != "idempotency_key_reused" {
+			t.Errorf("%s %s: %+v, want 422 idempotency_key_reused", tt.path, tt.body, res)
+		}
+	}
+	if a.calls() != 1 {
+		t.Errorf("handler ran %d times, want 1", a.calls())
+	}
+}
+
+func TestIdempotencyStoresErrorsButNotFailures(t *testing.T) {
+	a := newIdempotencyApp()
+	a.do(t, http.MethodPost, "/full", "k1", "", `{}`)
+	res := a.do(t, http.MethodPost, "/full", "k1", "", `{}`)
+	if res.status != fiber.StatusConflict || !res.replayed || res.body["code"] != "slot_full" {
+		t.Errorf("409 repeat = %+v, want a replayed slot_full", res)
+	}
+
+	a.do(t, http.MethodPost, "/broken", "k2", "", `{}`)
+	res = a.do(t, http.MethodPost, "/broken", "k2", "", `{}`)
+	if res.status != fiber.StatusInternalServerError || res.replayed {
+		t.Errorf("500 repeat = %+v, want a fresh attempt", res)
+	}
+	if a.calls() != 3 {
+		t.Errorf("handler ran %d times, want 3", a.calls())
+	}
+}
+
+func TestIdempotencyInProgress(t *testing.T) {
+	a := newIdempotencyApp()
+	a.started, a.release = make(chan struct{}), make(chan struct{})
+	done := make(chan idempotencyResponse)
+	go func() { done <- a.do(t, http.MethodPost, "/slow", "k1", "", `{}`) }()
+	<-a.started
+
+	res := a.do(t, http.MethodPost, "/slow", "k1", "", `{}`)
+	if res.status != fiber.StatusConflict || res.body["code"] != "idempotency_in_progress" {
+		t.Errorf("concurrent repeat = %+v, want 409 idempotency_in_progress", res)
+	}
+	close(a.release)
+	if first := <-done; first.status != fiber.StatusNoContent {
+		t.Errorf("first = %+v, want 204", first)
+	}
+	if res := a.do(t, http.MethodPost, "/slow", "k1", "", `{}`); res.status != fiber.StatusNoContent || !res.replayed {
+		t.Errorf("repeat after finish = %+v, want a replayed 204", res)
+	}
+}
+
+func TestIdempotencyKeyTooLong(t *testing.T) {
+	a := newIdempotencyApp()
+	res := a.do(t, http.MethodPost, "/bookings", strings.Repeat("k", maxIdempotencyKey+1), "", `{}`)
+	if res.status != fiber.StatusBadRequest || res.body["code"] != "invalid_idempotency_key" {
+		t.Errorf("res = %+v, want 400 invalid_idempotency_key", res)
+	}
+	if a.calls() != 0 {
+		t.Errorf("handler ran %d times, want 0", a.calls())
+	}
+}
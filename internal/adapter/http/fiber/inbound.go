@@ -0,0 +1,81 @@
+package fiber
+
+import (
+	"crypto/subtle"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/usecase"
+	"be-golang/pkg/webhooksig"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type InboundHandlers struct {
+	command *usecase.InboundCommand
+}
+
+func NewInboundHandlers(ic *usecase.InboundCommand) *InboundHandlers {
+	return &InboundHandlers{command: ic}
+}
+
+func (h *InboundHandlers) Register(app *fiber.App, auth fiber.Handler) {
+	app.Post("/inbound/messages", auth, h.receive)
+}
+
+// InboundAuth accepts a request carrying one of apiKeys in X-API-Key, or a
+// body signed with one of secrets using the webhooksig headers. With neither
+// configured every request is rejected.
+func InboundAuth(secrets, apiKeys []string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if key := c.Get("X-API-Key"); key != "" {
+			for _, k := range apiKeys {
+				if k != "" && subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+					return c.Next()
+				}
+			}
+			return domain.ErrUnauthorized
+		}
+		sig := c.Get(webhooksig.HeaderSignature)
+		if sig == "" || len(secrets) == 0 {
+			return domain.ErrUnauthorized
+		}
+		if err := webhooksig.Verify(secrets, c.Get(webhooksig.HeaderTimestamp), sig, c.Body(), 0, time.Now()); err != nil {
+			return domain.ErrUnauthorized
+		}
+		return c.Next()
+	}
+}
+
+func (h *InboundHandlers) receive(c *fiber.Ctx) error {
+	var body struct {
+		MessageID string `json:"message_id"`
+		Source    string `json:"source"`
+		Command   string `json:"command"`
+		BookingID int64  `json:"booking_id"`
+		Phone     string `json:"phone"`
+		Text      string `json:"text"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	m, replayed, err := h.command.Exec(domain.InboundMessage{
+		MessageID: body.MessageID,
+		Source:    body.Source,
+		Command:   body.Command,
+		BookingID: body.BookingID,
+		Phone:     body.Phone,
+		Text:      body.Text,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{
+		"message_id": m.MessageID,
+		"command":    m.Command,
+		"booking_id": m.BookingID,
+		"status":     m.Status,
+		"error":      m.Error,
+		"replayed":   replayed,
+	})
+}
@@ -0,0 +1,110 @@
+package fiber
+
+import (
+	"strconv"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/usecase"
+	"be-golang/internal/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type InvoiceHandlers struct {
+	issue *usecase.InvoiceIssue
+	list  *usecase.InvoiceList
+	pdf   *usecase.InvoicePDF
+	links *usecase.InvoiceLinks
+}
+
+// NewInvoiceHandlers takes a nil links when customer download links are
+// disabled.
+func NewInvoiceHandlers(ii *usecase.InvoiceIssue, il *usecase.InvoiceList, ip *usecase.InvoicePDF, links *usecase.InvoiceLinks) *InvoiceHandlers {
+	return &InvoiceHandlers{issue: ii, list: il, pdf: ip, links: links}
+}
+
+// Register adds the routes. The signed download link is public.
+func (h *InvoiceHandlers) Register(app *fiber.App, auth fiber.Handler) {
+	app.Get("/bookings/:id/invoice", auth, h.getInvoice)
+	app.Get("/bookings/:id/invoice.pdf", auth, h.getInvoicePDF)
+	app.Get("/admin/invoices", auth, h.listInvoices)
+	if h.links != nil {
+		app.Get("/invoices/:id.pdf", h.downloadInvoice)
+	}
+}
+
+func (h *InvoiceHandlers) getInvoice(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	inv, err := h.issue.Exec(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(inv)
+}
+
+// getInvoicePDF issues the invoice on first request for bookings completed
+// before invoicing was enabled.
+func (h *InvoiceHandlers) getInvoicePDF(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	inv, err := h.issue.Exec(id)
+	if err != nil {
+		return err
+	}
+	doc, err := h.pdf.Render(*inv)
+	if err != nil {
+		return err
+	}
+	return sendInvoicePDF(c, *inv, doc)
+}
+
+// downloadInvoice serves the PDF behind a link from invoice_issued. Only
+// invoices that were issued can be fetched this way.
+func (h *InvoiceHandlers) downloadInvoice(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		return domain.ErrInvalidInvoiceLink
+	}
+	if err := h.links.Verify(id, expires, c.Query("signature"), time.Now()); err != nil {
+		return err
+	}
+	inv, doc, err := h.pdf.Exec(id)
+	if err != nil {
+		return err
+	}
+	return sendInvoicePDF(c, *inv, doc)
+}
+
+func sendInvoicePDF(c *fiber.Ctx, inv domain.Invoice, doc []byte) error {
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="`+usecase.InvoiceFilename(inv.Number)+`"`)
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	return c.Send(doc)
+}
+
+func (h *InvoiceHandlers) listInvoices(c *fiber.Ctx) error {
+	var year int64
+	if s := c.Query("year"); s != "" {
+		var v validation.Validator
+		year, _ = strconv.ParseInt(s, 10, 64)
+		v.Range("year", year, 2000, 9999)
+		if err := v.Err(); err != nil {
+			return err
+		}
+	}
+	items, err := h.list.Exec(int(year))
+	if err != nil {
+		return err
+	}
+	return c.JSON(items)
+}
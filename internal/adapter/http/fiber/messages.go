@@ -0,0 +1,105 @@
+package fiber
+
+import (
+	"crypto/subtle"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type MessageHandlers struct {
+	list       *usecase.MessageList
+	status     *usecase.MessageStatusUpdate
+	optOuts    *usecase.MessageOptOutList
+	optOut     *usecase.MessageOptOutCreate
+	optOutDrop *usecase.MessageOptOutDelete
+}
+
+func NewMessageHandlers(ml *usecase.MessageList, ms *usecase.MessageStatusUpdate, mol *usecase.MessageOptOutList, moc *usecase.MessageOptOutCreate, mod *usecase.MessageOptOutDelete) *MessageHandlers {
+	return &MessageHandlers{list: ml, status: ms, optOuts: mol, optOut: moc, optOutDrop: mod}
+}
+
+func (h *MessageHandlers) Register(app *fiber.App, auth, callbackAuth fiber.Handler) {
+	app.Post("/messages/status", callbackAuth, h.statusCallback)
+	app.Get("/admin/messages", auth, h.listMessages)
+	app.Get("/admin/messages/opt-outs", auth, h.listOptOuts)
+	app.Post("/admin/messages/opt-outs", auth, h.createOptOut)
+	app.Delete("/admin/messages/opt-outs/:phone", auth, h.deleteOptOut)
+}
+
+// TokenAuth accepts one of tokens in the X-API-Key header or the token query
+// parameter, for gateways that can only be given a callback URL.
+func TokenAuth(tokens []string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		got := c.Get("X-API-Key")
+		if got == "" {
+			got = c.Query("token")
+		}
+		for _, t := range tokens {
+			if t != "" && got != "" && subtle.ConstantTimeCompare([]byte(got), []byte(t)) == 1 {
+				return c.Next()
+			}
+		}
+		return domain.ErrUnauthorized
+	}
+}
+
+func (h *MessageHandlers) statusCallback(c *fiber.Ctx) error {
+	var body struct {
+		ID        string `json:"id" form:"id"`
+		MessageID string `json:"message_id" form:"message_id"`
+		Status    string `json:"status" form:"status"`
+		Error     string `json:"error" form:"error"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	if body.ID == "" {
+		body.ID = body.MessageID
+	}
+	m, err := h.status.Exec(body.ID, body.Status, body.Error)
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"id": m.ID, "status": m.Status})
+}
+
+func (h *MessageHandlers) listMessages(c *fiber.Ctx) error {
+	items, err := h.list.Exec()
+	if err != nil {
+		return err
+	}
+	return c.JSON(items)
+}
+
+func (h *MessageHandlers) listOptOuts(c *fiber.Ctx) error {
+	items, err := h.optOuts.Exec()
+	if err != nil {
+		return err
+	}
+	return c.JSON(items)
+}
+
+func (h *MessageHandlers) createOptOut(c *fiber.Ctx) error {
+	var body struct {
+		Phone  string `json:"phone"`
+		Reason string `json:"reason"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	o, err := h.optOut.Exec(body.Phone, body.Reason)
+	if err != nil {
+		return err
+	}
+	return c.JSON(o)
+}
+
+func (h *MessageHandlers) deleteOptOut(c *fiber.Ctx) error {
+	if err := h.optOutDrop.Exec(c.Params("phone")); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,91 @@
+package fiber
+
+import (
+	"be-golang/internal/domain"
+	"be-golang/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type NotificationHandlers struct {
+	get    *usecase.NotificationPreferenceGet
+	list   *usecase.NotificationPreferenceList
+	save   *usecase.NotificationPreferenceSave
+	delete *usecase.NotificationPreferenceDelete
+}
+
+func NewNotificationHandlers(ng *usecase.NotificationPreferenceGet, nl *usecase.NotificationPreferenceList, ns *usecase.NotificationPreferenceSave, nd *usecase.NotificationPreferenceDelete) *NotificationHandlers {
+	return &NotificationHandlers{get: ng, list: nl, save: ns, delete: nd}
+}
+
+func (h *NotificationHandlers) Register(app *fiber.App, auth fiber.Handler) {
+	app.Get("/admin/notifications/preferences", auth, h.getBusiness)
+	app.Put("/admin/notifications/preferences", auth, h.saveBusiness)
+	app.Get("/admin/notifications/preferences/customers", auth, h.listCustomers)
+	app.Get("/admin/notifications/preferences/customers/:phone", auth, h.getCustomer)
+	app.Put("/admin/notifications/preferences/customers/:phone", auth, h.saveCustomer)
+	app.Delete("/admin/notifications/preferences/customers/:phone", auth, h.deleteCustomer)
+}
+
+func (h *NotificationHandlers) getBusiness(c *fiber.Ctx) error {
+	p, err := h.get.Exec(domain.PreferenceBusiness, "")
+	if err != nil {
+		return err
+	}
+	return c.JSON(p)
+}
+
+func (h *NotificationHandlers) saveBusiness(c *fiber.Ctx) error {
+	return h.savePreference(c, domain.PreferenceBusiness, "")
+}
+
+func (h *NotificationHandlers) listCustomers(c *fiber.Ctx) error {
+	items, err := h.list.Exec(domain.PreferenceCustomer)
+	if err != nil {
+		return err
+	}
+	return c.JSON(items)
+}
+
+func (h *NotificationHandlers) getCustomer(c *fiber.Ctx) error {
+	p, err := h.get.Exec(domain.PreferenceCustomer, c.Params("phone"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(p)
+}
+
+func (h *NotificationHandlers) saveCustomer(c *fiber.Ctx) error {
+	return h.savePreference(c, domain.PreferenceCustomer, c.Params("phone"))
+}
+
+func (h *NotificationHandlers) deleteCustomer(c *fiber.Ctx) error {
+	if err := h.delete.Exec(domain.PreferenceCustomer, c.Params("phone")); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *NotificationHandlers) savePreference(c *fiber.Ctx, scope, subject string) error {
+	var body struct {
+		Channels   []string `json:"channels"`
+		EventTypes []string `json:"event_types"`
+		QuietStart string   `json:"quiet_start"`
+		QuietEnd   string   `json:"quiet_end"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	p, err := h.save.Exec(domain.NotificationPreference{
+		Scope:      scope,
+		Subject:    subject,
+		Channels:   body.Channels,
+		EventTypes: body.EventTypes,
+		QuietStart: body.QuietStart,
+		QuietEnd:   body.QuietEnd,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(p)
+}
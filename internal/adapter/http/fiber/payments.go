@@ -0,0 +1,96 @@
+package fiber
+
+import (
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/usecase"
+	"be-golang/internal/util"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxBankLength bounds the virtual account bank code, e.g. "bca".
+const maxBankLength = 20
+
+type PaymentHandlers struct {
+	callback *usecase.PaymentCallback
+	list     *usecase.PaymentList
+	refund   *usecase.PaymentRefund
+}
+
+func NewPaymentHandlers(pc *usecase.PaymentCallback, pl *usecase.PaymentList, pr *usecase.PaymentRefund) *PaymentHandlers {
+	return &PaymentHandlers{callback: pc, list: pl, refund: pr}
+}
+
+// Register adds the routes. The callback is public; the gateway adapter
+// verifies its signature.
+func (h *PaymentHandlers) Register(app *fiber.App, auth fiber.Handler) {
+	app.Post("/payments/callback", h.paymentCallback)
+	app.Get("/bookings/:id/payments", auth, h.listPayments)
+	app.Post("/admin/payments/:id/refund", auth, h.refundPayment)
+}
+
+// paymentResponse is the payment shown to the customer right after
+// booking.
+type paymentResponse struct {
+	ID              int64     `json:"id"`
+	Status          string    `json:"status"`
+	Method          string    `json:"method"`
+	Bank            string    `json:"bank,omitempty"`
+	Amount          int64     `json:"amount"`
+	AmountFormatted string    `json:"amount_formatted"`
+	Currency        string    `json:"currency"`
+	QRString        string    `json:"qr_string,omitempty"`
+	VANumber        string    `json:"va_number,omitempty"`
+	PaymentURL      string    `json:"payment_url,omitempty"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+func newPaymentResponse(p domain.Payment) paymentResponse {
+	return paymentResponse{
+		ID:              p.ID,
+		Status:          p.Status,
+		Method:          p.Method,
+		Bank:            p.Bank,
+		Amount:          p.Amount,
+		AmountFormatted: util.FormatIDR(p.Amount),
+		Currency:        domain.PaymentCurrency,
+		QRString:        p.QRString,
+		VANumber:        p.VANumber,
+		PaymentURL:      p.PaymentURL,
+		ExpiresAt:       p.ExpiresAt,
+	}
+}
+
+func (h *PaymentHandlers) paymentCallback(c *fiber.Ctx) error {
+	header := func(key string) string { return c.Get(key) }
+	if err := h.callback.Exec(header, c.Body()); err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"received": true})
+}
+
+func (h *PaymentHandlers) listPayments(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	items, err := h.list.Exec(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(items)
+}
+
+func (h *PaymentHandlers) refundPayment(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	p, err := h.refund.Exec(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(p)
+}
@@ -0,0 +1,245 @@
+package fiber
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"be-golang/internal/domain"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const problemContentType = "application/problem+json"
+
+// fieldMessages phrases domain.FieldError codes per language. Placeholders
+// such as {min} are filled from FieldError.Params.
+var fieldMessages = map[string]map[string]string{
+	"id": {
+		"required":             "Wajib diisi.",
+		"too_short":            "Minimal {min} karakter.",
+		"too_long":             "Maksimal {max} karakter.",
+		"invalid_phone":        "Nomor telepon tidak valid. Gunakan format seperti 081234567890 atau +6281234567890.",
+		"invalid_date":         "Tanggal tidak valid. Gunakan format YYYY-MM-DD.",
+		"invalid_time":         "Jam tidak valid. Gunakan format 24 jam HH:MM.",
+		"must_be_future":       "Waktu booking harus di masa depan.",
+		"service_unavailable":  "Layanan tidak ditemukan atau tidak aktif.",
+		"out_of_range":         "Harus antara {min} dan {max}.",
+		"too_many":             "Maksimal {max} item.",
+		"not_found":            "Data tidak ditemukan.",
+		"invalid_image":        "Gambar harus berformat JPEG, PNG atau WebP.",
+		"too_large":            "Ukuran file maksimal {max_mb} MB.",
+		"invalid_choice":       "Pilihan tidak valid. Gunakan salah satu dari: {choices}.",
+		"invalid_code":         "Gunakan {min}–{max} huruf, angka, - atau _.",
+		"before_start":         "Tanggal berakhir harus setelah tanggal mulai.",
+		"promo_not_found":      "Kode promo tidak ditemukan.",
+		"promo_not_started":    "Promo belum berlaku.",
+		"promo_expired":        "Promo sudah berakhir.",
+		"promo_exhausted":      "Kuota promo sudah habis.",
+		"promo_customer_limit": "Anda sudah memakai promo ini sebanyak batas maksimal.",
+		"promo_first_visit":    "Promo hanya untuk kunjungan pertama.",
+		"promo_not_applicable": "Promo tidak berlaku untuk layanan ini.",
+		"promo_min_spend":      "Promo berlaku untuk transaksi minimal {min} rupiah.",
+	},
+	"en": {
+		"required":             "This field is required.",
+		"too_short":            "Must be at least {min} characters.",
+		"too_long":             "Must be at most {max} characters.",
+		"invalid_phone":        "Invalid phone number. Use a format like 081234567890 or +6281234567890.",
+		"invalid_date":         "Invalid date. Use the YYYY-MM-DD format.",
+		"invalid_time":         "Invalid time. Use the 24-hour HH:MM format.",
+		"must_be_future":       "The booking time must be in the future.",
+		"service_unavailable":  "The service does not exist or is inactive.",
+		"out_of_range":         "Must be between {min} and {max}.",
+		"too_many":             "At most {max} items.",
+		"not_found":            "Not found.",
+		"invalid_image":        "The image must be a JPEG, PNG or WebP file.",
+		"too_large":            "The file must be at most {max_mb} MB.",
+		"invalid_choice":       "Invalid choice. Use one of: {choices}.",
+		"invalid_code":         "Use {min}–{max} letters, digits, - or _.",
+		"before_start":         "The end date must be after the start date.",
+		"promo_not_found":      "Promo code not found.",
+		"promo_not_started":    "This promotion has not started yet.",
+		"promo_expired":        "This promotion has ended.",
+		"promo_exhausted":      "This promotion has been fully used.",
+		"promo_customer_limit": "You have already used this promotion the maximum number of times.",
+		"promo_first_visit":    "This promotion is for first visits only.",
+		"promo_not_applicable": "This promotion does not apply to this service.",
+		"promo_min_spend":      "This promotion requires a minimum spend of IDR {min}.",
+	},
+}
+
+var kindStatus = map[domain.Kind]int{
+	domain.KindInternal:     fiber.StatusInternalServerError,
+	domain.KindInvalid:      fiber.StatusBadRequest,
+	domain.KindUnauthorized: fiber.StatusUnauthorized,
+	domain.KindForbidden:    fiber.StatusForbidden,
+	domain.KindNotFound:     fiber.StatusNotFound,
+	domain.KindConflict:     fiber.StatusConflict,
+	domain.KindValidation:   fiber.StatusUnprocessableEntity,
+	domain.KindRateLimited:  fiber.StatusTooManyRequests,
+}
+
+var kindTitles = map[string]map[domain.Kind]string{
+	"id": {
+		domain.KindInternal:     "Terjadi kesalahan pada server.",
+		domain.KindInvalid:      "Request tidak valid.",
+		domain.KindUnauthorized: "Autentikasi diperlukan.",
+		domain.KindForbidden:    "Akses ditolak.",
+		domain.KindNotFound:     "Data tidak ditemukan.",
+		domain.KindConflict:     "Request bentrok dengan data yang ada.",
+		domain.KindValidation:   "Data yang dikirim tidak valid.",
+		domain.KindRateLimited:  "Terlalu banyak request, coba lagi nanti.",
+	},
+	"en": {
+		domain.KindInternal:     "Internal server error.",
+		domain.KindInvalid:      "Bad request.",
+		domain.KindUnauthorized: "Authentication required.",
+		domain.KindForbidden:    "Access denied.",
+		domain.KindNotFound:     "Resource not found.",
+		domain.KindConflict:     "The request conflicts with existing data.",
+		domain.KindValidation:   "The submitted data is invalid.",
+		domain.KindRateLimited:  "Too many requests, try again later.",
+	},
+}
+
+// Errors raised by the HTTP layer itself, before a usecase is reached.
+var (
+	errInvalidID   = domain.NewError(domain.KindInvalid, "invalid_id")
+	errInvalidBody = domain.NewError(domain.KindInvalid, "invalid_body")
+	errInvalidDate = domain.NewError(domain.KindInvalid, "invalid_date")
+)
+
+// paramID parses a positive integer route parameter.
+func paramID(c *fiber.Ctx, name string) (int64, error) {
+	id, err := strconv.ParseInt(c.Params(name), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errInvalidID
+	}
+	return id, nil
+}
+
+// parseBody decodes the request body into out.
+func parseBody(c *fiber.Ctx, out any) error {
+	if err := c.BodyParser(out); err != nil {
+		return errInvalidBody.Wrap(err)
+	}
+	return nil
+}
+
+// language picks "en" or "id" from Accept-Language, honouring q-values and
+// matching on the primary subtag ("en-US" counts as "en"). Indonesian is the
+// default.
+func language(c *fiber.Ctx) string {
+	type tag struct {
+		lang string
+		q    float64
+	}
+	var tags []tag
+	for _, part := range strings.Split(c.Get(fiber.HeaderAcceptLanguage), ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		lang, _, _ = strings.Cut(strings.ToLower(lang), "-")
+		if q > 0 {
+			tags = append(tags, tag{lang, q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+	for _, t := range tags {
+		if _, ok := kindTitles[t.lang]; ok {
+			return t.lang
+		}
+	}
+	return "id"
+}
+
+func fieldMessage(lang string, f domain.FieldError) string {
+	msg, ok := fieldMessages[lang][f.Code]
+	if !ok {
+		return f.Code
+	}
+	for k, v := range f.Params {
+		msg = strings.ReplaceAll(msg, "{"+k+"}", fmt.Sprint(v))
+	}
+	return msg
+}
+
+// ErrorHandler is the application's fiber ErrorHandler. It renders every
+// error returned by a handler as an RFC 7807 problem document with a stable
+// "code". Domain errors map to a status by kind; anything else is a 500
+// whose details are logged but not sent to the client.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	return problem(c, err, nil)
+}
+
+// problem writes err as a problem document with extra members, for errors
+// that carry more than a code, e.g. the conflicting dates of a series.
+func problem(c *fiber.Ctx, err error, extra fiber.Map) error {
+	kind := domain.KindOf(err)
+	status, code := kindStatus[kind], domain.CodeOf(err)
+	var fe *fiber.Error
+	if kind == domain.KindInternal && errors.As(err, &fe) {
+		// Routing failures (unknown path, wrong method, oversized body)
+		// raised by fiber itself.
+		status, code, kind = fe.Code, httpCode(fe.Code), statusKind(fe.Code)
+	}
+	var de *domain.Error
+	if status >= fiber.StatusInternalServerError || (errors.As(err, &de) && de.Err != nil) {
+		log.Printf("%s %s: %d %v", c.Method(), c.Route().Path, status, err)
+	}
+
+	lang := language(c)
+	body := fiber.Map{
+		"type":   "about:blank",
+		"title":  kindTitles[lang][kind],
+		"status": status,
+		"code":   code,
+	}
+	var ve *domain.ValidationError
+	if errors.As(err, &ve) {
+		fields := make([]fiber.Map, 0, len(ve.Fields))
+		for _, f := range ve.Fields {
+			fields = append(fields, fiber.Map{"field": f.Field, "code": f.Code, "message": fieldMessage(lang, f)})
+		}
+		body["errors"] = fields
+	}
+	for k, v := range extra {
+		body[k] = v
+	}
+	c.Set(fiber.HeaderContentLanguage, lang)
+	return c.Status(status).JSON(body, problemContentType)
+}
+
+func httpCode(status int) string {
+	switch status {
+	case fiber.StatusNotFound:
+		return "route_not_found"
+	case fiber.StatusMethodNotAllowed:
+		return "method_not_allowed"
+	case fiber.StatusRequestEntityTooLarge:
+		return "request_too_large"
+	case fiber.StatusUnsupportedMediaType:
+		return "unsupported_media_type"
+	}
+	return "request_failed"
+}
+
+func statusKind(status int) domain.Kind {
+	for k, s := range kindStatus {
+		if s == status {
+			return k
+		}
+	}
+	if status < fiber.StatusInternalServerError {
+		return domain.KindInvalid
+	}
+	return domain.KindInternal
+}
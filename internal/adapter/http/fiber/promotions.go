@@ -0,0 +1,231 @@
+package fiber
+
+import (
+	"strings"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/usecase"
+	"be-golang/internal/util"
+	"be-golang/internal/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PromotionHandlers struct {
+	create      *usecase.PromotionCreate
+	update      *usecase.PromotionUpdate
+	delete      *usecase.PromotionDelete
+	list        *usecase.PromotionList
+	redemptions *usecase.PromotionRedemptionList
+	report      *usecase.PromotionReport
+	apply       *usecase.PromotionApply
+	pricing     *usecase.PriceCalculate
+	loc         *time.Location
+}
+
+func NewPromotionHandlers(pc *usecase.PromotionCreate, pu *usecase.PromotionUpdate, pd *usecase.PromotionDelete, pl *usecase.PromotionList, prl *usecase.PromotionRedemptionList, pr *usecase.PromotionReport, pa *usecase.PromotionApply, calc *usecase.PriceCalculate, loc *time.Location) *PromotionHandlers {
+	return &PromotionHandlers{create: pc, update: pu, delete: pd, list: pl, redemptions: prl, report: pr, apply: pa, pricing: calc, loc: loc}
+}
+
+func (h *PromotionHandlers) Register(app *fiber.App, auth fiber.Handler) {
+	app.Get("/admin/promotions", auth, h.listPromotions)
+	app.Post("/admin/promotions", auth, h.createPromotion)
+	app.Get("/admin/promotions/report", auth, h.promotionReport)
+	app.Put("/admin/promotions/:id", auth, h.updatePromotion)
+	app.Delete("/admin/promotions/:id", auth, h.deletePromotion)
+	app.Get("/admin/promotions/:id/redemptions", auth, h.listRedemptions)
+	app.Post("/catalog/promo-check", h.checkPromotion)
+}
+
+type promotionBody struct {
+	Code             string  `json:"code"`
+	Description      string  `json:"description"`
+	DiscountType     string  `json:"discount_type"`
+	Value            int64   `json:"value"`
+	MaxDiscount      int64   `json:"max_discount"`
+	MinSpend         int64   `json:"min_spend"`
+	StartsOn         string  `json:"starts_on"`
+	EndsOn           string  `json:"ends_on"`
+	UsageLimit       int     `json:"usage_limit"`
+	PerCustomerLimit int     `json:"per_customer_limit"`
+	FirstVisitOnly   bool    `json:"first_visit_only"`
+	ServiceIDs       []int64 `json:"service_ids"`
+	IsActive         *bool   `json:"is_active"`
+}
+
+// promotion validates the body and converts it. starts_on and ends_on are
+// optional inclusive dates.
+func (b promotionBody) promotion() (domain.Promotion, error) {
+	var v validation.Validator
+	code := domain.NormalizePromoCode(b.Code)
+	if v.Required("code", code) {
+		v.PromoCode("code", code)
+	}
+	v.MaxLength("description", b.Description, domain.MaxDescription)
+	switch b.DiscountType {
+	case domain.DiscountPercent:
+		v.Range("value", b.Value, 1, 100)
+	case domain.DiscountFixed:
+		v.Range("value", b.Value, 1, domain.MaxServicePrice)
+	default:
+		v.Add("discount_type", "invalid_choice", map[string]any{"choices": domain.DiscountPercent + ", " + domain.DiscountFixed})
+	}
+	v.Range("max_discount", b.MaxDiscount, 0, domain.MaxServicePrice)
+	v.Range("min_spend", b.MinSpend, 0, domain.MaxServicePrice)
+	v.Range("usage_limit", int64(b.UsageLimit), 0, 1_000_000)
+	v.Range("per_customer_limit", int64(b.PerCustomerLimit), 0, 1_000_000)
+	p := domain.Promotion{
+		Code:             code,
+		Description:      strings.TrimSpace(b.Description),
+		DiscountType:     b.DiscountType,
+		Value:            b.Value,
+		MaxDiscount:      b.MaxDiscount,
+		MinSpend:         b.MinSpend,
+		UsageLimit:       b.UsageLimit,
+		PerCustomerLimit: b.PerCustomerLimit,
+		FirstVisitOnly:   b.FirstVisitOnly,
+		ServiceIDs:       b.ServiceIDs,
+		IsActive:         b.IsActive == nil || *b.IsActive,
+	}
+	if b.StartsOn != "" {
+		d := v.Date("starts_on", b.StartsOn)
+		p.StartsAt = &d
+	}
+	if b.EndsOn != "" {
+		d := v.Date("ends_on", b.EndsOn)
+		p.EndsAt = &d
+	}
+	if p.ServiceIDs == nil {
+		p.ServiceIDs = []int64{}
+	}
+	return p, v.Err()
+}
+
+func (h *PromotionHandlers) listPromotions(c *fiber.Ctx) error {
+	items, err := h.list.Exec()
+	if err != nil {
+		return err
+	}
+	return c.JSON(items)
+}
+
+func (h *PromotionHandlers) createPromotion(c *fiber.Ctx) error {
+	var body promotionBody
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	p, err := body.promotion()
+	if err != nil {
+		return err
+	}
+	id, err := h.create.Exec(p)
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"id": id})
+}
+
+func (h *PromotionHandlers) updatePromotion(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	var body promotionBody
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	p, err := body.promotion()
+	if err != nil {
+		return err
+	}
+	out, err := h.update.Exec(id, p)
+	if err != nil {
+		return err
+	}
+	return c.JSON(out)
+}
+
+func (h *PromotionHandlers) deletePromotion(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	if err := h.delete.Exec(id); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *PromotionHandlers) listRedemptions(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	items, err := h.redemptions.Exec(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(items)
+}
+
+// promotionReport sums redemptions per promotion, by default over the last
+// 30 days.
+func (h *PromotionHandlers) promotionReport(c *fiber.Ctx) error {
+	now := time.Now().In(h.loc)
+	today := now.Format("2006-01-02")
+	from, err := time.Parse("2006-01-02", c.Query("from", now.AddDate(0, 0, -29).Format("2006-01-02")))
+	if err != nil {
+		return errInvalidDate
+	}
+	to, err := time.Parse("2006-01-02", c.Query("to", today))
+	if err != nil {
+		return errInvalidDate
+	}
+	items, err := h.report.Exec(from, to)
+	if err != nil {
+		return err
+	}
+	return c.JSON(items)
+}
+
+// checkPromotion tells the booking form whether a code applies to the
+// selection and what the customer would pay. The code is checked again when
+// the booking is made.
+func (h *PromotionHandlers) checkPromotion(c *fiber.Ctx) error {
+	var body struct {
+		Code          string  `json:"code"`
+		ServiceID     int64   `json:"service_id"`
+		VariantID     *int64  `json:"variant_id"`
+		AddonIDs      []int64 `json:"addon_ids"`
+		CustomerPhone string  `json:"customer_phone"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	var v validation.Validator
+	v.Required("code", body.Code)
+	v.ID("service_id", body.ServiceID)
+	v.Phone("customer_phone", body.CustomerPhone)
+	if err := v.Err(); err != nil {
+		return err
+	}
+	svc, q, err := h.pricing.Exec(body.ServiceID, domain.OptionSelection{VariantID: body.VariantID, AddonIDs: body.AddonIDs})
+	if err != nil {
+		return err
+	}
+	p, discount, err := h.apply.Exec(body.Code, svc.ID, q.Total, body.CustomerPhone)
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{
+		"code":               p.Code,
+		"description":        p.Description,
+		"subtotal":           q.Total,
+		"discount":           discount,
+		"discount_formatted": util.FormatIDR(discount),
+		"total":              q.Total - discount,
+		"total_formatted":    util.FormatIDR(q.Total - discount),
+		"currency":           "IDR",
+	})
+}
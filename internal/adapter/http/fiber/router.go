@@ -1,14 +1,18 @@
 package fiber
 
 import (
+	"errors"
 	"strconv"
+	"strings"
 	"time"
 
 	"be-golang/internal/domain"
 	"be-golang/internal/usecase"
 	"be-golang/internal/util"
+	"be-golang/internal/validation"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
 )
 
 type Handlers struct {
@@ -16,23 +20,25 @@ type Handlers struct {
 	adminRegister     *usecase.AdminRegister
 	bookingCreate     *usecase.BookingCreate
 	bookingList       *usecase.BookingList
+	bookingGet        *usecase.BookingGet
+	bookingReschedule *usecase.BookingReschedule
+	bookingHistory    *usecase.BookingHistoryList
+	bookingSetStatus  *usecase.BookingSetStatus
 	dashboardStats    *usecase.DashboardStats
-	serviceCreate     *usecase.ServiceCreate
-	serviceDelete     *usecase.ServiceDelete
-	serviceListActive *usecase.ServiceListActive
 	jwt               *util.JWT
 }
 
-func NewHandlers(auth *usecase.AuthLogin, reg *usecase.AdminRegister, bc *usecase.BookingCreate, bl *usecase.BookingList, ds *usecase.DashboardStats, sc *usecase.ServiceCreate, sd *usecase.ServiceDelete, sla *usecase.ServiceListActive, jwt *util.JWT) *Handlers {
+func NewHandlers(auth *usecase.AuthLogin, reg *usecase.AdminRegister, bc *usecase.BookingCreate, bl *usecase.BookingList, bg *usecase.BookingGet, br *usecase.BookingReschedule, bh *usecase.BookingHistoryList, bss *usecase.BookingSetStatus, ds *usecase.DashboardStats, jwt *util.JWT) *Handlers {
 	return &Handlers{
 		authLogin:         auth,
 		adminRegister:     reg,
 		bookingCreate:     bc,
 		bookingList:       bl,
+		bookingGet:        bg,
+		bookingReschedule: br,
+		bookingHistory:    bh,
+		bookingSetStatus:  bss,
 		dashboardStats:    ds,
-		serviceCreate:     sc,
-		serviceDelete:     sd,
-		serviceListActive: sla,
 		jwt:               jwt,
 	}
 }
@@ -42,23 +48,41 @@ func (h *Handlers) Register(app *fiber.App) {
 	app.Post("/admin/register", h.register)
 	app.Post("/bookings", h.createBooking)
 	app.Get("/bookings", h.listBookings)
+	app.Get("/bookings/:id", h.jwtMiddleware, h.getBooking)
+	app.Post("/bookings/:id/reschedule", h.jwtMiddleware, h.rescheduleBooking)
+	app.Get("/bookings/:id/history", h.jwtMiddleware, h.listBookingHistory)
+	app.Patch("/bookings/:id/status", h.jwtMiddleware, h.setBookingStatus)
 	app.Get("/admin/dashboard", h.jwtMiddleware, h.dashboard)
-	app.Post("/services", h.jwtMiddleware, h.createService)
-	app.Delete("/services/:id", h.jwtMiddleware, h.deleteService)
-	app.Get("/services", h.jwtMiddleware, h.listActiveServices)
 }
 
 func (h *Handlers) jwtMiddleware(c *fiber.Ctx) error {
-	auth := c.Get("Authorization")
-	if len(auth) < 8 || auth[:7] != "Bearer " {
-		return c.SendStatus(fiber.StatusUnauthorized)
-	}
-	tokenStr := auth[7:]
-	tok, err := h.jwt.Parse(tokenStr)
-	if err != nil || !tok.Valid {
-		return c.SendStatus(fiber.StatusUnauthorized)
+	return JWTMiddleware(h.jwt)(c)
+}
+
+// currentUserID returns the admin user authenticated by JWTMiddleware, or 0.
+func currentUserID(c *fiber.Ctx) int64 {
+	id, _ := c.Locals("user_id").(int64)
+	return id
+}
+
+func JWTMiddleware(j *util.JWT) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		auth := c.Get("Authorization")
+		if len(auth) < 8 || auth[:7] != "Bearer " {
+			return domain.ErrUnauthorized
+		}
+		tokenStr := auth[7:]
+		tok, err := j.Parse(tokenStr)
+		if err != nil || !tok.Valid {
+			return domain.ErrUnauthorized
+		}
+		if claims, ok := tok.Claims.(jwt.MapClaims); ok {
+			if sub, ok := claims["sub"].(float64); ok {
+				c.Locals("user_id", int64(sub))
+			}
+		}
+		return c.Next()
 	}
-	return c.Next()
 }
 
 func (h *Handlers) login(c *fiber.Ctx) error {
@@ -66,12 +90,12 @@ func (h *Handlers) login(c *fiber.Ctx) error {
 		Email    string `json:"email"`
 		Password string `json:"password"`
 	}
-	if err := c.BodyParser(&body); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+	if err := parseBody(c, &body); err != nil {
+		return err
 	}
 	token, err := h.authLogin.Exec(body.Email, body.Password)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "invalid_credentials"})
+		return err
 	}
 	return c.JSON(fiber.Map{"token": token})
 }
@@ -81,45 +105,64 @@ func (h *Handlers) register(c *fiber.Ctx) error {
 		Email    string `json:"email"`
 		Password string `json:"password"`
 	}
-	if err := c.BodyParser(&body); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
+	if err := parseBody(c, &body); err != nil {
+		return err
 	}
 	id, err := h.adminRegister.Exec(body.Email, body.Password)
 	if err != nil {
-		if err.Error() == "email_exists" {
-			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "email_exists"})
-		}
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "register_failed"})
+		return err
 	}
 	return c.JSON(fiber.Map{"id": id})
 }
 
 func (h *Handlers) createBooking(c *fiber.Ctx) error {
 	var body struct {
-		CustomerName  string `json:"customer_name"`
-		CustomerPhone string `json:"customer_phone"`
-		ServiceID     int64  `json:"service_id"`
-		BookingDate   string `json:"booking_date"`
-		BookingTime   string `json:"booking_time"`
-	}
-	if err := c.BodyParser(&body); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
-	}
-	date, err := time.Parse("2006-01-02", body.BookingDate)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_date"})
-	}
-	id, err := h.bookingCreate.Exec(domain.Booking{
+		CustomerName  string  `json:"customer_name"`
+		CustomerPhone string  `json:"customer_phone"`
+		ServiceID     int64   `json:"service_id"`
+		BookingDate   string  `json:"booking_date"`
+		BookingTime   string  `json:"booking_time"`
+		VariantID     *int64  `json:"variant_id"`
+		AddonIDs      []int64 `json:"addon_ids"`
+		PromoCode     string  `json:"promo_code"`
+		PaymentMethod string  `json:"payment_method"`
+		PaymentBank   string  `json:"payment_bank"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	var v validation.Validator
+	v.Name("customer_name", body.CustomerName)
+	v.Phone("customer_phone", body.CustomerPhone)
+	v.ID("service_id", body.ServiceID)
+	date := v.Date("booking_date", body.BookingDate)
+	v.Clock("booking_time", body.BookingTime)
+	if body.PaymentMethod != "" && body.PaymentMethod != domain.PaymentMethodQRIS && body.PaymentMethod != domain.PaymentMethodVA {
+		v.Add("payment_method", "invalid_choice", map[string]any{"choices": domain.PaymentMethodQRIS + ", " + domain.PaymentMethodVA})
+	}
+	v.MaxLength("payment_bank", body.PaymentBank, maxBankLength)
+	if err := v.Err(); err != nil {
+		return err
+	}
+	id, payment, err := h.bookingCreate.Exec(domain.Booking{
 		CustomerName:  body.CustomerName,
 		CustomerPhone: body.CustomerPhone,
 		ServiceID:     body.ServiceID,
 		BookingDate:   date,
 		BookingTime:   body.BookingTime,
-	})
+		PromoCode:     strings.TrimSpace(body.PromoCode),
+	}, domain.OptionSelection{VariantID: body.VariantID, AddonIDs: body.AddonIDs},
+		domain.PaymentChoice{Method: body.PaymentMethod, Bank: strings.ToLower(strings.TrimSpace(body.PaymentBank))})
+	if errors.Is(err, domain.ErrSlotFull) {
+		return problem(c, err, fiber.Map{"waitlist_available": true})
+	}
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "create_failed"})
+		return err
 	}
-	return c.JSON(fiber.Map{"id": id})
+	if payment != nil {
+		return c.JSON(fiber.Map{"id": id, "status": domain.StatusAwaitingPayment, "payment": newPaymentResponse(*payment)})
+	}
+	return c.JSON(fiber.Map{"id": id, "status": domain.StatusPending})
 }
 
 func (h *Handlers) listBookings(c *fiber.Ctx) error {
@@ -127,56 +170,83 @@ func (h *Handlers) listBookings(c *fiber.Ctx) error {
 	limit, _ := strconv.Atoi(limitStr)
 	items, err := h.bookingList.Exec(limit)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "list_failed"})
+		return err
 	}
 	return c.JSON(items)
 }
 
-func (h *Handlers) dashboard(c *fiber.Ctx) error {
-	res, err := h.dashboardStats.Exec(time.Now())
+func (h *Handlers) rescheduleBooking(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "stats_failed"})
+		return err
 	}
-	return c.JSON(res)
-}
-
-func (h *Handlers) createService(c *fiber.Ctx) error {
 	var body struct {
-		Name     string `json:"name"`
-		Price    int64  `json:"price"`
-		IsActive bool   `json:"is_active"`
-	}
-	if err := c.BodyParser(&body); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_body"})
-	}
-	id, err := h.serviceCreate.Exec(domain.Service{
-		Name:     body.Name,
-		Price:    body.Price,
-		IsActive: body.IsActive,
-	})
+		BookingDate string `json:"booking_date"`
+		BookingTime string `json:"booking_time"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	var v validation.Validator
+	date := v.Date("booking_date", body.BookingDate)
+	v.Clock("booking_time", body.BookingTime)
+	if err := v.Err(); err != nil {
+		return err
+	}
+	b, err := h.bookingReschedule.Exec(id, date, body.BookingTime)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "create_failed"})
+		return err
 	}
-	return c.JSON(fiber.Map{"id": id})
+	return c.JSON(b)
 }
 
-func (h *Handlers) deleteService(c *fiber.Ctx) error {
-	idStr := c.Params("id")
-	id, err := strconv.ParseInt(idStr, 10, 64)
+func (h *Handlers) getBooking(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid_id"})
+		return err
 	}
-	err = h.serviceDelete.Exec(id)
+	b, err := h.bookingGet.Exec(id)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "delete_failed"})
+		return err
 	}
-	return c.SendStatus(fiber.StatusNoContent)
+	return c.JSON(b)
 }
 
-func (h *Handlers) listActiveServices(c *fiber.Ctx) error {
-	items, err := h.serviceListActive.Exec()
+func (h *Handlers) listBookingHistory(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "list_failed"})
+		return err
+	}
+	items, err := h.bookingHistory.Exec(id)
+	if err != nil {
+		return err
 	}
 	return c.JSON(items)
 }
+
+func (h *Handlers) setBookingStatus(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	var body struct {
+		Status   string `json:"status"`
+		WaiveFee bool   `json:"waive_fee"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	b, err := h.bookingSetStatus.Exec(id, body.Status, body.WaiveFee)
+	if err != nil {
+		return err
+	}
+	return c.JSON(b)
+}
+
+func (h *Handlers) dashboard(c *fiber.Ctx) error {
+	res, err := h.dashboardStats.Exec(time.Now())
+	if err != nil {
+		return err
+	}
+	return c.JSON(res)
+}
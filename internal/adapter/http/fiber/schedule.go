@@ -0,0 +1,217 @@
+package fiber
+
+import (
+	"bytes"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ScheduleHandlers struct {
+	calendar       *usecase.ScheduleCalendar
+	weeklyList     *usecase.ScheduleWeeklyList
+	weeklyCreate   *usecase.ScheduleWeeklyCreate
+	weeklyDelete   *usecase.ScheduleWeeklyDelete
+	overrideList   *usecase.ScheduleOverrideList
+	overrideCreate *usecase.ScheduleOverrideCreate
+	overrideDelete *usecase.ScheduleOverrideDelete
+	closureList    *usecase.ScheduleClosureList
+	closureCreate  *usecase.ScheduleClosureCreate
+	closureDelete  *usecase.ScheduleClosureDelete
+	holidayImport  *usecase.ScheduleHolidayImport
+	loc            *time.Location
+}
+
+func NewScheduleHandlers(cal *usecase.ScheduleCalendar, wl *usecase.ScheduleWeeklyList, wc *usecase.ScheduleWeeklyCreate, wd *usecase.ScheduleWeeklyDelete, ol *usecase.ScheduleOverrideList, oc *usecase.ScheduleOverrideCreate, od *usecase.ScheduleOverrideDelete, cl *usecase.ScheduleClosureList, cc *usecase.ScheduleClosureCreate, cd *usecase.ScheduleClosureDelete, hi *usecase.ScheduleHolidayImport, loc *time.Location) *ScheduleHandlers {
+	return &ScheduleHandlers{
+		calendar:       cal,
+		weeklyList:     wl,
+		weeklyCreate:   wc,
+		weeklyDelete:   wd,
+		overrideList:   ol,
+		overrideCreate: oc,
+		overrideDelete: od,
+		closureList:    cl,
+		closureCreate:  cc,
+		closureDelete:  cd,
+		holidayImport:  hi,
+		loc:            loc,
+	}
+}
+
+func (h *ScheduleHandlers) Register(app *fiber.App, auth fiber.Handler) {
+	app.Get("/schedule", h.getCalendar)
+	app.Get("/admin/schedule/hours", auth, h.listWeekly)
+	app.Post("/admin/schedule/hours", auth, h.createWeekly)
+	app.Delete("/admin/schedule/hours/:id", auth, h.deleteWeekly)
+	app.Get("/admin/schedule/overrides", auth, h.listOverrides)
+	app.Post("/admin/schedule/overrides", auth, h.createOverride)
+	app.Delete("/admin/schedule/overrides/:id", auth, h.deleteOverride)
+	app.Get("/admin/schedule/closures", auth, h.listClosures)
+	app.Post("/admin/schedule/closures", auth, h.createClosure)
+	app.Post("/admin/schedule/closures/import", auth, h.importHolidays)
+	app.Delete("/admin/schedule/closures/:id", auth, h.deleteClosure)
+}
+
+// dateRangeQuery reads from and to, defaulting to today in loc and the days
+// after it.
+func dateRangeQuery(c *fiber.Ctx, days int, loc *time.Location) (time.Time, time.Time, error) {
+	today := time.Now().In(loc)
+	from, err := time.Parse("2006-01-02", c.Query("from", today.Format("2006-01-02")))
+	if err != nil {
+		return from, from, err
+	}
+	to, err := time.Parse("2006-01-02", c.Query("to", from.AddDate(0, 0, days).Format("2006-01-02")))
+	return from, to, err
+}
+
+func (h *ScheduleHandlers) getCalendar(c *fiber.Ctx) error {
+	from, to, err := dateRangeQuery(c, 13, h.loc)
+	if err != nil {
+		return errInvalidDate
+	}
+	days, err := h.calendar.Exec(from, to)
+	if err != nil {
+		return err
+	}
+	return c.JSON(days)
+}
+
+func (h *ScheduleHandlers) listWeekly(c *fiber.Ctx) error {
+	items, err := h.weeklyList.Exec()
+	if err != nil {
+		return err
+	}
+	return c.JSON(items)
+}
+
+func (h *ScheduleHandlers) createWeekly(c *fiber.Ctx) error {
+	var body struct {
+		Weekday int    `json:"weekday"`
+		Opens   string `json:"opens"`
+		Closes  string `json:"closes"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	id, err := h.weeklyCreate.Exec(domain.OpeningInterval{Weekday: body.Weekday, Opens: body.Opens, Closes: body.Closes})
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"id": id})
+}
+
+func (h *ScheduleHandlers) deleteWeekly(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	if err := h.weeklyDelete.Exec(id); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *ScheduleHandlers) listOverrides(c *fiber.Ctx) error {
+	from, to, err := dateRangeQuery(c, 365, h.loc)
+	if err != nil {
+		return errInvalidDate
+	}
+	items, err := h.overrideList.Exec(from, to)
+	if err != nil {
+		return err
+	}
+	return c.JSON(items)
+}
+
+func (h *ScheduleHandlers) createOverride(c *fiber.Ctx) error {
+	var body struct {
+		Date   string `json:"date"`
+		Opens  string `json:"opens"`
+		Closes string `json:"closes"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	date, err := time.Parse("2006-01-02", body.Date)
+	if err != nil {
+		return errInvalidDate
+	}
+	id, err := h.overrideCreate.Exec(domain.ScheduleOverride{Date: date, Opens: body.Opens, Closes: body.Closes})
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"id": id})
+}
+
+func (h *ScheduleHandlers) deleteOverride(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	if err := h.overrideDelete.Exec(id); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *ScheduleHandlers) listClosures(c *fiber.Ctx) error {
+	from, to, err := dateRangeQuery(c, 365, h.loc)
+	if err != nil {
+		return errInvalidDate
+	}
+	items, err := h.closureList.Exec(from, to)
+	if err != nil {
+		return err
+	}
+	return c.JSON(items)
+}
+
+func (h *ScheduleHandlers) createClosure(c *fiber.Ctx) error {
+	var body struct {
+		StartDate string `json:"start_date"`
+		EndDate   string `json:"end_date"`
+		Reason    string `json:"reason"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	start, err := time.Parse("2006-01-02", body.StartDate)
+	if err != nil {
+		return errInvalidDate
+	}
+	var end time.Time
+	if body.EndDate != "" {
+		end, err = time.Parse("2006-01-02", body.EndDate)
+		if err != nil {
+			return errInvalidDate
+		}
+	}
+	id, err := h.closureCreate.Exec(domain.Closure{StartDate: start, EndDate: end, Reason: body.Reason})
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"id": id})
+}
+
+func (h *ScheduleHandlers) importHolidays(c *fiber.Ctx) error {
+	n, err := h.holidayImport.Exec(bytes.NewReader(c.Body()))
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"imported": n})
+}
+
+func (h *ScheduleHandlers) deleteClosure(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	if err := h.closureDelete.Exec(id); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
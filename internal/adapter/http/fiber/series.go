@@ -0,0 +1,131 @@
+package fiber
+
+import (
+	"errors"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/usecase"
+	"be-golang/internal/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SeriesHandlers struct {
+	create     *usecase.BookingSeriesCreate
+	get        *usecase.BookingSeriesGet
+	cancel     *usecase.BookingSeriesCancel
+	reschedule *usecase.BookingSeriesReschedule
+}
+
+func NewSeriesHandlers(sc *usecase.BookingSeriesCreate, sg *usecase.BookingSeriesGet, scl *usecase.BookingSeriesCancel, sr *usecase.BookingSeriesReschedule) *SeriesHandlers {
+	return &SeriesHandlers{create: sc, get: sg, cancel: scl, reschedule: sr}
+}
+
+func (h *SeriesHandlers) Register(app *fiber.App, auth fiber.Handler) {
+	app.Post("/bookings/series", auth, h.createSeries)
+	app.Get("/bookings/series/:id", auth, h.getSeries)
+	app.Post("/bookings/:id/series/cancel", auth, h.cancelOccurrences)
+	app.Post("/bookings/:id/series/reschedule", auth, h.rescheduleOccurrences)
+}
+
+// seriesError adds the conflicting occurrences to a conflict problem so the
+// client can show which dates failed.
+func seriesError(c *fiber.Ctx, err error, res usecase.SeriesResult) error {
+	if errors.Is(err, domain.ErrSeriesConflicts) || errors.Is(err, domain.ErrSlotFull) {
+		return problem(c, err, fiber.Map{"conflicts": res.Conflicts})
+	}
+	return err
+}
+
+func (h *SeriesHandlers) createSeries(c *fiber.Ctx) error {
+	var body struct {
+		CustomerName  string `json:"customer_name"`
+		CustomerPhone string `json:"customer_phone"`
+		ServiceID     int64  `json:"service_id"`
+		BookingDate   string `json:"booking_date"`
+		BookingTime   string `json:"booking_time"`
+		Rule          string `json:"rule"`
+		SkipConflicts bool   `json:"skip_conflicts"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	var v validation.Validator
+	v.Name("customer_name", body.CustomerName)
+	v.Phone("customer_phone", body.CustomerPhone)
+	v.ID("service_id", body.ServiceID)
+	date := v.Date("booking_date", body.BookingDate)
+	v.Clock("booking_time", body.BookingTime)
+	v.Required("rule", body.Rule)
+	if err := v.Err(); err != nil {
+		return err
+	}
+	res, err := h.create.Exec(domain.Booking{
+		CustomerName:  body.CustomerName,
+		CustomerPhone: body.CustomerPhone,
+		ServiceID:     body.ServiceID,
+		BookingDate:   date,
+		BookingTime:   body.BookingTime,
+	}, body.Rule, body.SkipConflicts)
+	if err != nil {
+		return seriesError(c, err, res)
+	}
+	return c.JSON(res)
+}
+
+func (h *SeriesHandlers) getSeries(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	res, err := h.get.Exec(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(res)
+}
+
+func (h *SeriesHandlers) cancelOccurrences(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	var body struct {
+		Scope    string `json:"scope"`
+		WaiveFee bool   `json:"waive_fee"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	ids, err := h.cancel.Exec(id, body.Scope, body.WaiveFee)
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"cancelled": ids})
+}
+
+func (h *SeriesHandlers) rescheduleOccurrences(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	var body struct {
+		Scope       string `json:"scope"`
+		BookingDate string `json:"booking_date"`
+		BookingTime string `json:"booking_time"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	var v validation.Validator
+	date := v.Date("booking_date", body.BookingDate)
+	v.Clock("booking_time", body.BookingTime)
+	if err := v.Err(); err != nil {
+		return err
+	}
+	res, err := h.reschedule.Exec(id, body.Scope, date, body.BookingTime)
+	if err != nil {
+		return seriesError(c, err, res)
+	}
+	return c.JSON(res)
+}
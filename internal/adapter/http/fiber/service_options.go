@@ -0,0 +1,277 @@
+package fiber
+
+import (
+	"strconv"
+	"strings"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/usecase"
+	"be-golang/internal/util"
+	"be-golang/internal/validation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/etag"
+)
+
+// optionPaths maps the route segment under /services/:id to the option kind.
+var optionPaths = map[string]string{
+	"variants": domain.OptionVariant,
+	"addons":   domain.OptionAddon,
+}
+
+type ServiceOptionHandlers struct {
+	create  *usecase.ServiceOptionCreate
+	update  *usecase.ServiceOptionUpdate
+	delete  *usecase.ServiceOptionDelete
+	list    *usecase.ServiceOptionList
+	pricing *usecase.PriceCalculate
+	cancel  *usecase.CancellationPolicyResolve
+}
+
+func NewServiceOptionHandlers(oc *usecase.ServiceOptionCreate, ou *usecase.ServiceOptionUpdate, od *usecase.ServiceOptionDelete, ol *usecase.ServiceOptionList, pc *usecase.PriceCalculate, cr *usecase.CancellationPolicyResolve) *ServiceOptionHandlers {
+	return &ServiceOptionHandlers{create: oc, update: ou, delete: od, list: ol, pricing: pc, cancel: cr}
+}
+
+func (h *ServiceOptionHandlers) Register(app *fiber.App, auth fiber.Handler) {
+	for path, kind := range optionPaths {
+		app.Get("/services/:id/"+path, auth, h.listOptions(kind))
+		app.Post("/services/:id/"+path, auth, h.createOption(kind))
+		app.Patch("/services/:id/"+path+"/:optionID", auth, h.updateOption(kind))
+		app.Delete("/services/:id/"+path+"/:optionID", auth, h.deleteOption(kind))
+	}
+	app.Get("/catalog/services/:id/options", etag.New(), h.catalogOptions)
+	app.Get("/catalog/quote", h.quote)
+}
+
+func validateOptionFields(v *validation.Validator, name *string, price *int64, duration *int) {
+	if name != nil {
+		v.Name("name", *name)
+	}
+	if price != nil {
+		v.Range("price", *price, domain.MinServicePrice, domain.MaxServicePrice)
+	}
+	if duration != nil && *duration != 0 {
+		v.Range("duration_minutes", int64(*duration), domain.MinServiceDuration, domain.MaxServiceDuration)
+	}
+}
+
+func (h *ServiceOptionHandlers) listOptions(kind string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := paramID(c, "id")
+		if err != nil {
+			return err
+		}
+		variants, addons, err := h.list.Exec(id, false)
+		if err != nil {
+			return err
+		}
+		if kind == domain.OptionVariant {
+			return c.JSON(variants)
+		}
+		return c.JSON(addons)
+	}
+}
+
+func (h *ServiceOptionHandlers) createOption(kind string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		serviceID, err := paramID(c, "id")
+		if err != nil {
+			return err
+		}
+		var body struct {
+			Name            string `json:"name"`
+			Price           int64  `json:"price"`
+			DurationMinutes int    `json:"duration_minutes"`
+			SortOrder       int    `json:"sort_order"`
+			IsActive        *bool  `json:"is_active"`
+		}
+		if err := parseBody(c, &body); err != nil {
+			return err
+		}
+		var v validation.Validator
+		validateOptionFields(&v, &body.Name, &body.Price, &body.DurationMinutes)
+		if err := v.Err(); err != nil {
+			return err
+		}
+		id, err := h.create.Exec(domain.ServiceOption{
+			ServiceID:       serviceID,
+			Kind:            kind,
+			Name:            strings.TrimSpace(body.Name),
+			Price:           body.Price,
+			DurationMinutes: body.DurationMinutes,
+			SortOrder:       body.SortOrder,
+			IsActive:        body.IsActive == nil || *body.IsActive,
+		})
+		if err != nil {
+			return err
+		}
+		return c.JSON(fiber.Map{"id": id})
+	}
+}
+
+func (h *ServiceOptionHandlers) updateOption(kind string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		serviceID, err := paramID(c, "id")
+		if err != nil {
+			return err
+		}
+		id, err := paramID(c, "optionID")
+		if err != nil {
+			return err
+		}
+		var body struct {
+			Name            *string `json:"name"`
+			Price           *int64  `json:"price"`
+			DurationMinutes *int    `json:"duration_minutes"`
+			SortOrder       *int    `json:"sort_order"`
+			IsActive        *bool   `json:"is_active"`
+		}
+		if err := parseBody(c, &body); err != nil {
+			return err
+		}
+		var v validation.Validator
+		validateOptionFields(&v, body.Name, body.Price, body.DurationMinutes)
+		if err := v.Err(); err != nil {
+			return err
+		}
+		if body.Name != nil {
+			name := strings.TrimSpace(*body.Name)
+			body.Name = &name
+		}
+		o, err := h.update.Exec(serviceID, kind, id, usecase.ServiceOptionUpdateInput{
+			Name:            body.Name,
+			Price:           body.Price,
+			DurationMinutes: body.DurationMinutes,
+			SortOrder:       body.SortOrder,
+			IsActive:        body.IsActive,
+		})
+		if err != nil {
+			return err
+		}
+		return c.JSON(o)
+	}
+}
+
+func (h *ServiceOptionHandlers) deleteOption(kind string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		serviceID, err := paramID(c, "id")
+		if err != nil {
+			return err
+		}
+		id, err := paramID(c, "optionID")
+		if err != nil {
+			return err
+		}
+		if err := h.delete.Exec(serviceID, kind, id); err != nil {
+			return err
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
+
+type catalogOption struct {
+	ID              int64  `json:"id"`
+	Name            string `json:"name"`
+	Price           int64  `json:"price"`
+	PriceFormatted  string `json:"price_formatted"`
+	DurationMinutes int    `json:"duration_minutes"`
+}
+
+func catalogOptions(options []domain.ServiceOption) []catalogOption {
+	out := make([]catalogOption, 0, len(options))
+	for _, o := range options {
+		out = append(out, catalogOption{
+			ID:              o.ID,
+			Name:            o.Name,
+			Price:           o.Price,
+			PriceFormatted:  util.FormatIDR(o.Price),
+			DurationMinutes: o.DurationMinutes,
+		})
+	}
+	return out
+}
+
+// catalogOptions lists the active variants and add-ons a customer can pick
+// for a service.
+func (h *ServiceOptionHandlers) catalogOptions(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	variants, addons, err := h.list.Exec(id, true)
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderCacheControl, "public, max-age="+strconv.Itoa(catalogMaxAge))
+	return c.JSON(fiber.Map{"variants": catalogOptions(variants), "addons": catalogOptions(addons)})
+}
+
+type quoteItem struct {
+	Kind            string `json:"kind"`
+	RefID           int64  `json:"ref_id"`
+	Name            string `json:"name"`
+	Quantity        int    `json:"quantity"`
+	UnitPrice       int64  `json:"unit_price"`
+	Amount          int64  `json:"amount"`
+	AmountFormatted string `json:"amount_formatted"`
+	DurationMinutes int    `json:"duration_minutes"`
+}
+
+// quote prices a selection before booking, e.g.
+// /catalog/quote?service_id=1&variant_id=3&addon_ids=7,8. POST /bookings
+// with the same selection stores exactly these line items.
+func (h *ServiceOptionHandlers) quote(c *fiber.Ctx) error {
+	var v validation.Validator
+	serviceID, _ := strconv.ParseInt(c.Query("service_id"), 10, 64)
+	v.ID("service_id", serviceID)
+	var sel domain.OptionSelection
+	if s := c.Query("variant_id"); s != "" {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || id <= 0 {
+			v.Add("variant_id", "not_found", nil)
+		}
+		sel.VariantID = &id
+	}
+	for _, s := range strings.Split(c.Query("addon_ids"), ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || id <= 0 {
+			v.Add("addon_ids", "not_found", nil)
+		}
+		sel.AddonIDs = append(sel.AddonIDs, id)
+	}
+	if err := v.Err(); err != nil {
+		return err
+	}
+	_, q, err := h.pricing.Exec(serviceID, sel)
+	if err != nil {
+		return err
+	}
+	policy, err := h.cancel.Exec(serviceID)
+	if err != nil {
+		return err
+	}
+	items := make([]quoteItem, 0, len(q.Items))
+	for _, it := range q.Items {
+		items = append(items, quoteItem{
+			Kind:            it.Kind,
+			RefID:           it.RefID,
+			Name:            it.Name,
+			Quantity:        it.Quantity,
+			UnitPrice:       it.UnitPrice,
+			Amount:          it.Amount(),
+			AmountFormatted: util.FormatIDR(it.Amount()),
+			DurationMinutes: it.DurationMinutes,
+		})
+	}
+	return c.JSON(fiber.Map{
+		"items":               items,
+		"total":               q.Total,
+		"total_formatted":     util.FormatIDR(q.Total),
+		"currency":            "IDR",
+		"duration_minutes":    q.DurationMinutes,
+		"cancellation_policy": newCancellationPolicyResponse(policy).withFees(policy, q.Total),
+	})
+}
@@ -0,0 +1,376 @@
+package fiber
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/usecase"
+	"be-golang/internal/util"
+	"be-golang/internal/validation"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/etag"
+)
+
+// catalogMaxAge is how long browsers and proxies may reuse the public
+// catalog before revalidating it with If-None-Match.
+const catalogMaxAge = 300
+
+type ServiceHandlers struct {
+	create      *usecase.ServiceCreate
+	update      *usecase.ServiceUpdate
+	archive     *usecase.ServiceArchive
+	restore     *usecase.ServiceRestore
+	listActive  *usecase.ServiceListActive
+	list        *usecase.ServiceList
+	reorder     *usecase.ServiceReorder
+	setImage    *usecase.ServiceSetImage
+	removeImage *usecase.ServiceRemoveImage
+}
+
+func NewServiceHandlers(sc *usecase.ServiceCreate, su *usecase.ServiceUpdate, sa *usecase.ServiceArchive, sr *usecase.ServiceRestore, sla *usecase.ServiceListActive, sl *usecase.ServiceList, so *usecase.ServiceReorder, ssi *usecase.ServiceSetImage, sri *usecase.ServiceRemoveImage) *ServiceHandlers {
+	return &ServiceHandlers{create: sc, update: su, archive: sa, restore: sr, listActive: sla, list: sl, reorder: so, setImage: ssi, removeImage: sri}
+}
+
+func (h *ServiceHandlers) Register(app *fiber.App, auth fiber.Handler) {
+	app.Post("/services", auth, h.createService)
+	app.Get("/services", auth, h.listActiveServices)
+	app.Patch("/services/:id", auth, h.updateService)
+	app.Delete("/services/:id", auth, h.archiveService)
+	app.Post("/services/:id/restore", auth, h.restoreService)
+	app.Put("/services/:id/image", auth, h.uploadImage)
+	app.Delete("/services/:id/image", auth, h.deleteImage)
+	app.Get("/admin/services", auth, h.listServices)
+	app.Put("/admin/services/order", auth, h.reorderServices)
+	app.Get("/catalog/services", etag.New(), h.catalog)
+}
+
+func validateServiceFields(v *validation.Validator, name, description *string, price *int64, duration *int, tags []string) {
+	if name != nil {
+		v.Name("name", *name)
+	}
+	if description != nil {
+		v.MaxLength("description", *description, domain.MaxDescription)
+	}
+	if price != nil {
+		v.Range("price", *price, domain.MinServicePrice, domain.MaxServicePrice)
+	}
+	if duration != nil && *duration != 0 {
+		v.Range("duration_minutes", int64(*duration), domain.MinServiceDuration, domain.MaxServiceDuration)
+	}
+	v.Count("tags", len(tags), domain.MaxTags)
+	for _, t := range tags {
+		v.MaxLength("tags", strings.TrimSpace(t), domain.MaxTagLength)
+	}
+}
+
+// categoryRef turns the category_id of a request into a domain reference:
+// nil and 0 both mean uncategorised.
+func categoryRef(id *int64) *int64 {
+	if id == nil || *id == 0 {
+		return nil
+	}
+	return id
+}
+
+func (h *ServiceHandlers) createService(c *fiber.Ctx) error {
+	var body struct {
+		Name            string   `json:"name"`
+		Description     string   `json:"description"`
+		Price           int64    `json:"price"`
+		DurationMinutes int      `json:"duration_minutes"`
+		IsActive        bool     `json:"is_active"`
+		CategoryID      *int64   `json:"category_id"`
+		SortOrder       int      `json:"sort_order"`
+		Tags            []string `json:"tags"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	var v validation.Validator
+	validateServiceFields(&v, &body.Name, &body.Description, &body.Price, &body.DurationMinutes, body.Tags)
+	if err := v.Err(); err != nil {
+		return err
+	}
+	id, err := h.create.Exec(domain.Service{
+		Name:            strings.TrimSpace(body.Name),
+		Description:     strings.TrimSpace(body.Description),
+		Price:           body.Price,
+		DurationMinutes: body.DurationMinutes,
+		IsActive:        body.IsActive,
+		CategoryID:      categoryRef(body.CategoryID),
+		SortOrder:       body.SortOrder,
+		Tags:            body.Tags,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"id": id})
+}
+
+func (h *ServiceHandlers) updateService(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	var body struct {
+		Name            *string  `json:"name"`
+		Description     *string  `json:"description"`
+		Price           *int64   `json:"price"`
+		DurationMinutes *int     `json:"duration_minutes"`
+		IsActive        *bool    `json:"is_active"`
+		CategoryID      *int64   `json:"category_id"`
+		SortOrder       *int     `json:"sort_order"`
+		Tags            []string `json:"tags"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	var v validation.Validator
+	validateServiceFields(&v, body.Name, body.Description, body.Price, body.DurationMinutes, body.Tags)
+	if body.DurationMinutes != nil && *body.DurationMinutes == 0 {
+		v.Add("duration_minutes", "required", nil)
+	}
+	if err := v.Err(); err != nil {
+		return err
+	}
+	if body.Name != nil {
+		name := strings.TrimSpace(*body.Name)
+		body.Name = &name
+	}
+	if body.Description != nil {
+		description := strings.TrimSpace(*body.Description)
+		body.Description = &description
+	}
+	s, err := h.update.Exec(id, usecase.ServiceUpdateInput{
+		Name:            body.Name,
+		Description:     body.Description,
+		Price:           body.Price,
+		DurationMinutes: body.DurationMinutes,
+		IsActive:        body.IsActive,
+		CategoryID:      categoryRef(body.CategoryID),
+		SortOrder:       body.SortOrder,
+		Tags:            body.Tags,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(s)
+}
+
+func (h *ServiceHandlers) archiveService(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	if err := h.archive.Exec(id); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *ServiceHandlers) restoreService(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	s, err := h.restore.Exec(id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(s)
+}
+
+func (h *ServiceHandlers) uploadImage(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	fh, err := c.FormFile("image")
+	if err != nil {
+		return domain.Invalid("image", "required")
+	}
+	if fh.Size > domain.MaxImageBytes {
+		var v validation.Validator
+		v.Add("image", "too_large", map[string]any{"max_mb": domain.MaxImageBytes >> 20})
+		return v.Err()
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	s, err := h.setImage.Exec(id, data)
+	if err != nil {
+		return err
+	}
+	return c.JSON(s)
+}
+
+func (h *ServiceHandlers) deleteImage(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	if err := h.removeImage.Exec(id); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *ServiceHandlers) reorderServices(c *fiber.Ctx) error {
+	var body struct {
+		IDs []int64 `json:"ids"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	if len(body.IDs) == 0 {
+		return domain.Invalid("ids", "required")
+	}
+	if err := h.reorder.Exec(body.IDs); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// serviceFilter reads the category_id and tag query parameters shared by
+// the admin listing and the public catalog.
+func serviceFilter(c *fiber.Ctx) (domain.ServiceFilter, error) {
+	f := domain.ServiceFilter{Tag: strings.ToLower(strings.TrimSpace(c.Query("tag")))}
+	if v := c.Query("category_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			return f, errInvalidID
+		}
+		f.CategoryID = id
+	}
+	return f, nil
+}
+
+// serviceGroup is one category of a listing requested with
+// ?group=category. Uncategorised services come last, with a null id.
+type serviceGroup[T any] struct {
+	ID       *int64 `json:"id"`
+	Name     string `json:"name"`
+	Services []T    `json:"services"`
+}
+
+// groupByCategory splits services, already sorted by category, into one
+// group per category, converting each service with view.
+func groupByCategory[T any](items []domain.Service, view func(domain.Service) T) []serviceGroup[T] {
+	groups := []serviceGroup[T]{}
+	for _, s := range items {
+		n := len(groups)
+		if n == 0 || !sameCategory(groups[n-1].ID, s.CategoryID) {
+			groups = append(groups, serviceGroup[T]{ID: s.CategoryID, Name: s.CategoryName})
+			n++
+		}
+		groups[n-1].Services = append(groups[n-1].Services, view(s))
+	}
+	return groups
+}
+
+func sameCategory(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func (h *ServiceHandlers) listActiveServices(c *fiber.Ctx) error {
+	f, err := serviceFilter(c)
+	if err != nil {
+		return err
+	}
+	items, err := h.listActive.Exec(f)
+	if err != nil {
+		return err
+	}
+	return c.JSON(items)
+}
+
+func (h *ServiceHandlers) listServices(c *fiber.Ctx) error {
+	f, err := serviceFilter(c)
+	if err != nil {
+		return err
+	}
+	f.IncludeArchived = c.QueryBool("include_archived")
+	items, err := h.list.Exec(f)
+	if err != nil {
+		return err
+	}
+	if c.Query("group") == "category" {
+		return c.JSON(groupByCategory(items, func(s domain.Service) domain.Service { return s }))
+	}
+	return c.JSON(items)
+}
+
+type catalogCategory struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// catalogService is the public view of a service: no activity flags or
+// archive timestamps, and the price is also given ready to display.
+type catalogService struct {
+	ID              int64            `json:"id"`
+	Name            string           `json:"name"`
+	Description     string           `json:"description"`
+	Category        *catalogCategory `json:"category"`
+	ImageURL        string           `json:"image_url"`
+	Tags            []string         `json:"tags"`
+	Price           int64            `json:"price"`
+	PriceFormatted  string           `json:"price_formatted"`
+	Currency        string           `json:"currency"`
+	DurationMinutes int              `json:"duration_minutes"`
+}
+
+func newCatalogService(s domain.Service) catalogService {
+	out := catalogService{
+		ID:              s.ID,
+		Name:            s.Name,
+		Description:     s.Description,
+		ImageURL:        s.ImageURL,
+		Tags:            s.Tags,
+		Price:           s.Price,
+		PriceFormatted:  util.FormatIDR(s.Price),
+		Currency:        "IDR",
+		DurationMinutes: s.DurationMinutes,
+	}
+	if s.CategoryID != nil {
+		out.Category = &catalogCategory{ID: *s.CategoryID, Name: s.CategoryName}
+	}
+	if out.Tags == nil {
+		out.Tags = []string{}
+	}
+	return out
+}
+
+// catalog lists the bookable services for the customer booking form. It
+// needs no login; the etag middleware answers If-None-Match with 304.
+func (h *ServiceHandlers) catalog(c *fiber.Ctx) error {
+	f, err := serviceFilter(c)
+	if err != nil {
+		return err
+	}
+	items, err := h.listActive.Exec(f)
+	if err != nil {
+		return err
+	}
+	c.Set(fiber.HeaderCacheControl, "public, max-age="+strconv.Itoa(catalogMaxAge))
+	if c.Query("group") == "category" {
+		return c.JSON(fiber.Map{"categories": groupByCategory(items, newCatalogService)})
+	}
+	out := make([]catalogService, 0, len(items))
+	for _, s := range items {
+		out = append(out, newCatalogService(s))
+	}
+	return c.JSON(fiber.Map{"services": out})
+}
@@ -0,0 +1,94 @@
+package fiber
+
+import (
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/usecase"
+	"be-golang/internal/validation"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WaitlistHandlers struct {
+	join  *usecase.WaitlistJoin
+	claim *usecase.WaitlistClaim
+	list  *usecase.WaitlistList
+	loc   *time.Location
+}
+
+func NewWaitlistHandlers(wj *usecase.WaitlistJoin, wc *usecase.WaitlistClaim, wl *usecase.WaitlistList, loc *time.Location) *WaitlistHandlers {
+	return &WaitlistHandlers{join: wj, claim: wc, list: wl, loc: loc}
+}
+
+func (h *WaitlistHandlers) Register(app *fiber.App, auth fiber.Handler) {
+	app.Post("/waitlist", h.joinWaitlist)
+	app.Post("/waitlist/:id/claim", h.claimOffer)
+	app.Get("/admin/waitlist", auth, h.listWaitlist)
+}
+
+func (h *WaitlistHandlers) joinWaitlist(c *fiber.Ctx) error {
+	var body struct {
+		CustomerName  string `json:"customer_name"`
+		CustomerPhone string `json:"customer_phone"`
+		ServiceID     int64  `json:"service_id"`
+		Date          string `json:"date"`
+		WindowStart   string `json:"window_start"`
+		WindowEnd     string `json:"window_end"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	var v validation.Validator
+	v.Name("customer_name", body.CustomerName)
+	v.Phone("customer_phone", body.CustomerPhone)
+	v.ID("service_id", body.ServiceID)
+	date := v.Date("date", body.Date)
+	v.Clock("window_start", body.WindowStart)
+	v.Clock("window_end", body.WindowEnd)
+	if err := v.Err(); err != nil {
+		return err
+	}
+	id, err := h.join.Exec(domain.WaitlistEntry{
+		CustomerName:  body.CustomerName,
+		CustomerPhone: body.CustomerPhone,
+		ServiceID:     body.ServiceID,
+		Date:          date,
+		WindowStart:   body.WindowStart,
+		WindowEnd:     body.WindowEnd,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"id": id})
+}
+
+func (h *WaitlistHandlers) claimOffer(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	var body struct {
+		Token string `json:"token"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	bookingID, err := h.claim.Exec(id, body.Token)
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"booking_id": bookingID})
+}
+
+func (h *WaitlistHandlers) listWaitlist(c *fiber.Ctx) error {
+	date, err := time.Parse("2006-01-02", c.Query("date", time.Now().In(h.loc).Format("2006-01-02")))
+	if err != nil {
+		return errInvalidDate
+	}
+	items, err := h.list.Exec(date)
+	if err != nil {
+		return err
+	}
+	return c.JSON(items)
+}
@@ -0,0 +1,199 @@
+package fiber
+
+import (
+	"errors"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/usecase"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WebhookHandlers struct {
+	list       *usecase.WebhookList
+	create     *usecase.WebhookCreate
+	update     *usecase.WebhookUpdate
+	delete     *usecase.WebhookDelete
+	deliveries *usecase.WebhookDeliveries
+	test       *usecase.WebhookTest
+}
+
+func NewWebhookHandlers(wl *usecase.WebhookList, wc *usecase.WebhookCreate, wu *usecase.WebhookUpdate, wd *usecase.WebhookDelete, wdl *usecase.WebhookDeliveries, wt *usecase.WebhookTest) *WebhookHandlers {
+	return &WebhookHandlers{list: wl, create: wc, update: wu, delete: wd, deliveries: wdl, test: wt}
+}
+
+func (h *WebhookHandlers) Register(app *fiber.App, auth fiber.Handler) {
+	app.Get("/admin/webhooks", auth, h.listWebhooks)
+	app.Post("/admin/webhooks", auth, h.createWebhook)
+	app.Patch("/admin/webhooks/:id", auth, h.updateWebhook)
+	app.Delete("/admin/webhooks/:id", auth, h.deleteWebhook)
+	app.Get("/admin/webhooks/:id/deliveries", auth, h.listDeliveries)
+	app.Post("/admin/webhooks/:id/test", auth, h.testWebhook)
+}
+
+// webhookResponse never includes the previous secret, and the current one
+// only right after it was generated or set.
+type webhookResponse struct {
+	ID         int64             `json:"id"`
+	URL        string            `json:"url"`
+	EventTypes []string          `json:"event_types"`
+	Active     bool              `json:"active"`
+	Headers    map[string]string `json:"headers"`
+	Secret     string            `json:"secret,omitempty"`
+	CreatedAt  time.Time         `json:"created_at"`
+}
+
+func newWebhookResponse(s domain.WebhookSubscriber, withSecret bool) webhookResponse {
+	r := webhookResponse{
+		ID:         s.ID,
+		URL:        s.URL,
+		EventTypes: s.EventTypes,
+		Active:     s.Active,
+		Headers:    s.Headers,
+		CreatedAt:  s.CreatedAt,
+	}
+	if r.EventTypes == nil {
+		r.EventTypes = []string{}
+	}
+	if r.Headers == nil {
+		r.Headers = map[string]string{}
+	}
+	if withSecret {
+		r.Secret = s.Secret
+	}
+	return r
+}
+
+type webhookDeliveryResponse struct {
+	ID              int64     `json:"id"`
+	SubscriberID    int64     `json:"subscriber_id"`
+	EventID         string    `json:"event_id"`
+	EventType       string    `json:"event_type"`
+	StatusCode      int       `json:"status_code"`
+	LatencyMS       int64     `json:"latency_ms"`
+	ResponseSnippet string    `json:"response_snippet"`
+	Error           string    `json:"error,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func newWebhookDeliveryResponse(d domain.WebhookDelivery) webhookDeliveryResponse {
+	return webhookDeliveryResponse{
+		ID:              d.ID,
+		SubscriberID:    d.SubscriberID,
+		EventID:         d.EventID,
+		EventType:       d.EventType,
+		StatusCode:      d.StatusCode,
+		LatencyMS:       d.LatencyMS,
+		ResponseSnippet: d.ResponseSnippet,
+		Error:           d.Error,
+		CreatedAt:       d.CreatedAt,
+	}
+}
+
+func (h *WebhookHandlers) listWebhooks(c *fiber.Ctx) error {
+	items, err := h.list.Exec()
+	if err != nil {
+		return err
+	}
+	out := make([]webhookResponse, 0, len(items))
+	for _, s := range items {
+		out = append(out, newWebhookResponse(s, false))
+	}
+	return c.JSON(out)
+}
+
+func (h *WebhookHandlers) createWebhook(c *fiber.Ctx) error {
+	var body struct {
+		URL        string            `json:"url"`
+		EventTypes []string          `json:"event_types"`
+		Secret     string            `json:"secret"`
+		Active     *bool             `json:"active"`
+		Headers    map[string]string `json:"headers"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	active := body.Active == nil || *body.Active
+	s, err := h.create.Exec(domain.WebhookSubscriber{
+		URL:        body.URL,
+		EventTypes: body.EventTypes,
+		Secret:     body.Secret,
+		Active:     active,
+		Headers:    body.Headers,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(newWebhookResponse(*s, true))
+}
+
+func (h *WebhookHandlers) updateWebhook(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	var body struct {
+		URL          *string            `json:"url"`
+		EventTypes   *[]string          `json:"event_types"`
+		Active       *bool              `json:"active"`
+		Headers      *map[string]string `json:"headers"`
+		RotateSecret bool               `json:"rotate_secret"`
+	}
+	if err := parseBody(c, &body); err != nil {
+		return err
+	}
+	s, err := h.update.Exec(id, usecase.WebhookUpdateInput{
+		URL:          body.URL,
+		EventTypes:   body.EventTypes,
+		Active:       body.Active,
+		Headers:      body.Headers,
+		RotateSecret: body.RotateSecret,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(newWebhookResponse(*s, body.RotateSecret))
+}
+
+func (h *WebhookHandlers) deleteWebhook(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	if err := h.delete.Exec(id); err != nil {
+		return err
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *WebhookHandlers) listDeliveries(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	items, err := h.deliveries.Exec(id)
+	if err != nil {
+		return err
+	}
+	out := make([]webhookDeliveryResponse, 0, len(items))
+	for _, d := range items {
+		out = append(out, newWebhookDeliveryResponse(d))
+	}
+	return c.JSON(out)
+}
+
+func (h *WebhookHandlers) testWebhook(c *fiber.Ctx) error {
+	id, err := paramID(c, "id")
+	if err != nil {
+		return err
+	}
+	d, err := h.test.Exec(id)
+	if errors.Is(err, domain.ErrWebhookFailed) {
+		return problem(c, err, fiber.Map{"delivery": newWebhookDeliveryResponse(d)})
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSON(newWebhookDeliveryResponse(d))
+}
@@ -0,0 +1,61 @@
+// Package fake is an in-memory message provider for tests and local
+// development. Nothing leaves the process.
+package fake
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+)
+
+type Message struct {
+	ID      string
+	Channel string
+	To      string
+	Body    string
+}
+
+type Provider struct {
+	mu       sync.Mutex
+	sent     []Message
+	failNext error
+}
+
+func New() *Provider { return &Provider{} }
+
+func (p *Provider) Send(channel, to, body string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.failNext; err != nil {
+		p.failNext = nil
+		return "", err
+	}
+	m := Message{ID: "fake-" + strconv.Itoa(len(p.sent)+1), Channel: channel, To: to, Body: body}
+	p.sent = append(p.sent, m)
+	return m.ID, nil
+}
+
+// Sent returns a copy of the messages accepted so far.
+func (p *Provider) Sent() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Message(nil), p.sent...)
+}
+
+// FailNext makes the next Send return err. A nil err uses a generic error.
+func (p *Provider) FailNext(err error) {
+	if err == nil {
+		err = errors.New("fake: send failed")
+	}
+	p.mu.Lock()
+	p.failNext = err
+	p.mu.Unlock()
+}
+
+// Reset forgets all sent messages.
+func (p *Provider) Reset() {
+	p.mu.Lock()
+	p.sent = nil
+	p.failNext = nil
+	p.mu.Unlock()
+}
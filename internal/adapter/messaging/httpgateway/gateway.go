@@ -0,0 +1,111 @@
+// Package httpgateway sends SMS and WhatsApp messages through a gateway that
+// accepts one HTTP request per message. The request body is a template so
+// most Indonesian gateways can be configured without code.
+package httpgateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"text/template"
+	"time"
+
+	"be-golang/internal/util"
+)
+
+// DefaultBody is used when Config.Body is empty.
+const DefaultBody = `{"to":{{json .To}},"message":{{json .Body}},"channel":{{json .Channel}}}`
+
+type Config struct {
+	URL string
+	// Token is sent in AuthHeader (default "Authorization") when set.
+	Token      string
+	AuthHeader string
+	// Body is a text/template over .Channel, .To and .Body. The functions
+	// json and query escape a value for JSON and form bodies.
+	Body        string
+	ContentType string
+	// IDField is the dotted path to the message ID in the JSON response,
+	// e.g. "id", "data.message_id" or "id.0". When it is empty or missing
+	// from the response, status callbacks cannot be matched.
+	IDField string
+}
+
+type Gateway struct {
+	cfg   Config
+	body  *template.Template
+	httpc *http.Client
+}
+
+func New(cfg Config) (*Gateway, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("httpgateway: url is required")
+	}
+	if cfg.Body == "" {
+		cfg.Body = DefaultBody
+	}
+	if cfg.ContentType == "" {
+		cfg.ContentType = "application/json"
+	}
+	if cfg.AuthHeader == "" {
+		cfg.AuthHeader = "Authorization"
+	}
+	t, err := template.New("body").Funcs(template.FuncMap{
+		"json": func(s string) string {
+			b, _ := json.Marshal(s)
+			return string(b)
+		},
+		"query": url.QueryEscape,
+	}).Parse(cfg.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &Gateway{cfg: cfg, body: t, httpc: &http.Client{Timeout: 10 * time.Second}}, nil
+}
+
+func (g *Gateway) Send(channel, to, body string) (string, error) {
+	var buf bytes.Buffer
+	err := g.body.Execute(&buf, struct{ Channel, To, Body string }{channel, to, body})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(http.MethodPost, g.cfg.URL, &buf)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", g.cfg.ContentType)
+	if g.cfg.Token != "" {
+		req.Header.Set(g.cfg.AuthHeader, g.cfg.Token)
+	}
+	resp, err := g.httpc.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("httpgateway: status %d: %s", resp.StatusCode, snippet(respBody))
+	}
+	if g.cfg.IDField == "" {
+		return "", nil
+	}
+	// The gateway accepted the message; a response without a usable ID only
+	// means later status callbacks cannot be matched to it.
+	var payload any
+	if err := json.Unmarshal(respBody, &payload); err != nil {
+		return "", nil
+	}
+	id, _ := util.LookupJSON(payload, g.cfg.IDField)
+	return id, nil
+}
+
+func snippet(b []byte) string {
+	if len(b) > 256 {
+		b = b[:256]
+	}
+	return string(b)
+}
@@ -0,0 +1,187 @@
+// Package email delivers notifications over SMTP, rendered from the
+// per-language templates in templates/.
+package email
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	htmltemplate "html/template"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/util"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+const defaultLanguage = "id"
+
+// Languages lists the template sets bundled with the adapter.
+var Languages = []string{"id", "en"}
+
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+	Language string
+	Location *time.Location
+	// Attachments, if set, returns extra files to send with an event, such
+	// as the PDF of an issued invoice.
+	Attachments func(e domain.Event) ([]domain.Attachment, error)
+}
+
+// Notifier emails every event to the configured recipients. Confirmed and
+// rescheduled bookings carry an ICS invitation.
+type Notifier struct {
+	cfg  Config
+	text map[string]*texttemplate.Template
+	html map[string]*htmltemplate.Template
+	// send is smtp.SendMail; it is a field so a stand-in can capture mail.
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func New(cfg Config) (*Notifier, error) {
+	if cfg.Host == "" || cfg.From == "" || len(cfg.To) == 0 {
+		return nil, errors.New("email: host, from and recipients are required")
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	if cfg.Language == "" {
+		cfg.Language = defaultLanguage
+	}
+	n := &Notifier{
+		cfg:  cfg,
+		text: map[string]*texttemplate.Template{},
+		html: map[string]*htmltemplate.Template{},
+		send: smtp.SendMail,
+	}
+	funcs := map[string]any{
+		"datetime": func(t time.Time) string { return t.In(cfg.Location).Format("02/01/2006 15:04") },
+		"clock":    func(t time.Time) string { return t.In(cfg.Location).Format("15:04") },
+		"json": func(v any) string {
+			b, _ := json.MarshalIndent(v, "", "  ")
+			return string(b)
+		},
+	}
+	for _, lang := range Languages {
+		t, err := texttemplate.New(lang).Funcs(funcs).ParseFS(templateFS, "templates/"+lang+".txt.tmpl")
+		if err != nil {
+			return nil, err
+		}
+		h, err := htmltemplate.New(lang).Funcs(funcs).ParseFS(templateFS, "templates/"+lang+".html.tmpl")
+		if err != nil {
+			return nil, err
+		}
+		n.text[lang], n.html[lang] = t, h
+	}
+	if n.text[cfg.Language] == nil {
+		return nil, errors.New("email: unsupported language " + cfg.Language)
+	}
+	return n, nil
+}
+
+type view struct {
+	Event   domain.Event
+	Data    any
+	Subject string
+}
+
+func (n *Notifier) Notify(e domain.Event) error {
+	if e.Type == domain.EventWebhookTest {
+		return nil
+	}
+	subject, text, html, err := n.render(e)
+	if err != nil {
+		return err
+	}
+	var invite *util.ICalEvent
+	if b, ok := confirmedBooking(e); ok {
+		invite = &util.ICalEvent{
+			UID:         domain.CalendarUID(b.BookingID),
+			Summary:     subject,
+			Description: b.CustomerName + " (" + b.CustomerPhone + ")",
+			Start:       b.StartAt,
+			End:         b.EndAt,
+			Sequence:    b.Revision,
+			Status:      "CONFIRMED",
+			Stamp:       e.OccurredAt,
+		}
+	}
+	var files []domain.Attachment
+	if n.cfg.Attachments != nil {
+		if files, err = n.cfg.Attachments(e); err != nil {
+			return err
+		}
+	}
+	msg, err := buildMessage(n.cfg.From, n.cfg.To, subject, text, html, invite, files, e)
+	if err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if n.cfg.Username != "" {
+		auth = smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)
+	}
+	addr := net.JoinHostPort(n.cfg.Host, strconv.Itoa(n.cfg.Port))
+	return n.send(addr, auth, n.cfg.From, n.cfg.To, msg)
+}
+
+// render executes the subject, text and HTML templates for e in the
+// configured language, falling back to the default blocks for event types
+// without their own.
+func (n *Notifier) render(e domain.Event) (subject, text, html string, err error) {
+	tt, ht := n.text[n.cfg.Language], n.html[n.cfg.Language]
+	name := e.Type
+	if tt.Lookup(name+".subject") == nil || ht.Lookup(name+".html") == nil {
+		name = "default"
+	}
+	v := view{Event: e, Data: e.Data}
+	var buf bytes.Buffer
+	if err = tt.ExecuteTemplate(&buf, name+".subject", v); err != nil {
+		return
+	}
+	subject = strings.TrimSpace(buf.String())
+	v.Subject = subject
+	buf.Reset()
+	if err = tt.ExecuteTemplate(&buf, name+".text", v); err != nil {
+		return
+	}
+	text = buf.String()
+	buf.Reset()
+	if err = ht.ExecuteTemplate(&buf, name+".html", v); err != nil {
+		return
+	}
+	html = buf.String()
+	return
+}
+
+func confirmedBooking(e domain.Event) (domain.BookingData, bool) {
+	switch d := e.Data.(type) {
+	case domain.BookingData:
+		return d, e.Type == domain.EventBookingConfirmed
+	case domain.BookingRescheduledData:
+		return d.BookingData, d.Status == domain.StatusConfirmed
+	}
+	return domain.BookingData{}, false
+}
+
+func mailDomain(addr string) string {
+	if i := strings.LastIndex(addr, "@"); i >= 0 {
+		return strings.Trim(addr[i+1:], "> ")
+	}
+	return "localhost"
+}
@@ -0,0 +1,191 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"be-golang/internal/domain"
+)
+
+type part struct {
+	contentType string
+	disposition string
+	body        string
+}
+
+// capture returns a notifier whose mail is kept in *msg instead of sent.
+func capture(t *testing.T, cfg Config, msg *[]byte) *Notifier {
+	t.Helper()
+	cfg.Host, cfg.From, cfg.To = "smtp.example.com", "Salon <booking@example.com>", []string{"owner@example.com"}
+	n, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.send = func(addr string, a smtp.Auth, from string, to []string, m []byte) error {
+		if addr != "smtp.example.com:587" {
+			t.Errorf("addr = %q", addr)
+		}
+		*msg = m
+		return nil
+	}
+	return n
+}
+
+// parts flattens the MIME tree of raw into its leaf parts, decoded.
+func parts(t *testing.T, raw []byte) (mail.Header, []part) {
+	t.Helper()
+	m, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out []part
+	var walk func(contentType, disposition, encoding string, r io.Reader)
+	walk = func(contentType, disposition, encoding string, r io.Reader) {
+		mediaType, params, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			t.Fatalf("content type %q: %v", contentType, err)
+		}
+		if strings.HasPrefix(mediaType, "multipart/") {
+			out = append(out, part{contentType: mediaType})
+			mr := multipart.NewReader(r, params["boundary"])
+			for {
+				p, err := mr.NextRawPart()
+				if err == io.EOF {
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				walk(p.Header.Get("Content-Type"), p.Header.Get("Content-Disposition"), p.Header.Get("Content-Transfer-Encoding"), p)
+			}
+		}
+		switch encoding {
+		case "base64":
+			r = base64.NewDecoder(base64.StdEncoding, r)
+		case "quoted-printable":
+			r = quotedprintable.NewReader(r)
+		}
+		b, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out = append(out, part{contentType: mediaType, disposition: disposition, body: string(b)})
+	}
+	walk(m.Header.Get("Content-Type"), "", m.Header.Get("Content-Transfer-Encoding"), m.Body)
+	return m.Header, out
+}
+
+func find(ps []part, mediaType string) *part {
+	for i := range ps {
+		if ps[i].contentType == mediaType {
+			return &ps[i]
+		}
+	}
+	return nil
+}
+
+func bookingEvent(typ string, d domain.BookingData) domain.Event {
+	return domain.Event{ID: "evt-1", Type: typ, OccurredAt: time.Date(2026, 5, 4, 3, 0, 0, 0, time.UTC), Data: d}
+}
+
+var booking = domain.BookingData{
+	BookingID:     42,
+	CustomerName:  "Budi",
+	CustomerPhone: "+628123456789",
+	ServiceID:     1,
+	BookingDate:   "2026-05-10",
+	BookingTime:   "10:00",
+	StartAt:       time.Date(2026, 5, 10, 3, 0, 0, 0, time.UTC),
+	EndAt:         time.Date(2026, 5, 10, 4, 0, 0, 0, time.UTC),
+	Status:        domain.StatusConfirmed,
+	Revision:      3,
+}
+
+func TestNotifyConfirmedCarriesInvite(t *testing.T) {
+	var raw []byte
+	n := capture(t, Config{}, &raw)
+	if err := n.Notify(bookingEvent(domain.EventBookingConfirmed, booking)); err != nil {
+		t.Fatal(err)
+	}
+	h, ps := parts(t, raw)
+	if got := h.Get("X-Event-Type"); got != domain.EventBookingConfirmed {
+		t.Errorf("X-Event-Type = %q", got)
+	}
+	if got := h.Get("Message-Id"); got != "<evt-1@example.com>" {
+		t.Errorf("Message-ID = %q", got)
+	}
+	var types []string
+	for _, p := range ps {
+		types = append(types, p.contentType)
+	}
+	want := []string{"multipart/mixed", "multipart/alternative", "text/plain", "text/html", "text/calendar"}
+	if strings.Join(types, ",") != strings.Join(want, ",") {
+		t.Fatalf("parts = %v, want %v", types, want)
+	}
+	if p := find(ps, "text/plain"); !strings.Contains(p.body, "Budi") {
+		t.Errorf("text body misses the customer:\n%s", p.body)
+	}
+	ics := find(ps, "text/calendar")
+	if !strings.HasPrefix(ics.disposition, "attachment") || !strings.Contains(ics.disposition, "booking.ics") {
+		t.Errorf("calendar disposition = %q", ics.disposition)
+	}
+	for _, line := range []string{
+		"METHOD:REQUEST",
+		"UID:" + domain.CalendarUID(42),
+		"SEQUENCE:3",
+		"STATUS:CONFIRMED",
+		"DTSTART:20260510T030000Z",
+		"DTEND:20260510T040000Z",
+	} {
+		if !strings.Contains(ics.body, line+"\r\n") {
+			t.Errorf("invite misses %q:\n%s", line, ics.body)
+		}
+	}
+}
+
+func TestNotifyAttachments(t *testing.T) {
+	var raw []byte
+	n := capture(t, Config{
+		Attachments: func(e domain.Event) ([]domain.Attachment, error) {
+			return []domain.Attachment{{Name: "invoice.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.4 test")}}, nil
+		},
+	}, &raw)
+	if err := n.Notify(bookingEvent(domain.EventBookingCompleted, booking)); err != nil {
+		t.Fatal(err)
+	}
+	_, ps := parts(t, raw)
+	if find(ps, "text/calendar") != nil {
+		t.Error("completed booking carries an invite")
+	}
+	pdf := find(ps, "application/pdf")
+	if pdf == nil {
+		t.Fatal("missing the attachment")
+	}
+	if pdf.body != "%PDF-1.4 test" {
+		t.Errorf("attachment = %q", pdf.body)
+	}
+	if !strings.Contains(pdf.disposition, `filename="invoice.pdf"`) {
+		t.Errorf("attachment disposition = %q", pdf.disposition)
+	}
+}
+
+func TestNotifyPlainWithoutFiles(t *testing.T) {
+	var raw []byte
+	n := capture(t, Config{Language: "en"}, &raw)
+	if err := n.Notify(bookingEvent(domain.EventBookingCreated, booking)); err != nil {
+		t.Fatal(err)
+	}
+	_, ps := parts(t, raw)
+	if len(ps) != 3 || ps[0].contentType != "multipart/alternative" {
+		t.Fatalf("parts = %+v, want a bare multipart/alternative", ps)
+	}
+}
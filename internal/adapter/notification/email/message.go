@@ -0,0 +1,112 @@
+package email
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/util"
+)
+
+const icalProdID = "-//be-golang//booking//EN"
+
+// buildMessage assembles a multipart/alternative text and HTML body, wrapped
+// in multipart/mixed with a text/calendar attachment when invite is set and
+// with any other files.
+func buildMessage(from string, to []string, subject, text, html string, invite *util.ICalEvent, files []domain.Attachment, e domain.Event) ([]byte, error) {
+	var alt bytes.Buffer
+	aw := multipart.NewWriter(&alt)
+	if err := writeQP(aw, "text/plain; charset=utf-8", text); err != nil {
+		return nil, err
+	}
+	if err := writeQP(aw, "text/html; charset=utf-8", html); err != nil {
+		return nil, err
+	}
+	if err := aw.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: <%s@%s>\r\n", e.ID, mailDomain(from))
+	fmt.Fprintf(&msg, "X-Event-Type: %s\r\n", e.Type)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+
+	if invite != nil {
+		var ics bytes.Buffer
+		if err := util.WriteICalendar(&ics, util.ICalendar{
+			ProdID: icalProdID,
+			Method: "REQUEST",
+			Events: []util.ICalEvent{*invite},
+		}); err != nil {
+			return nil, err
+		}
+		files = append([]domain.Attachment{{
+			Name:        "booking.ics",
+			ContentType: "text/calendar; charset=utf-8; method=REQUEST",
+			Data:        ics.Bytes(),
+		}}, files...)
+	}
+	if len(files) == 0 {
+		fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", aw.Boundary())
+		msg.Write(alt.Bytes())
+		return msg.Bytes(), nil
+	}
+
+	mw := multipart.NewWriter(&msg)
+	fmt.Fprintf(&msg, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", mw.Boundary())
+	p, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + aw.Boundary()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.Write(alt.Bytes()); err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		p, err = mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {fmt.Sprintf("%s; name=%q", f.ContentType, f.Name)},
+			"Content-Disposition":       {fmt.Sprintf("attachment; filename=%q", f.Name)},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		enc := base64.StdEncoding.EncodeToString(f.Data)
+		for len(enc) > 76 {
+			fmt.Fprintf(p, "%s\r\n", enc[:76])
+			enc = enc[76:]
+		}
+		fmt.Fprintf(p, "%s\r\n", enc)
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return msg.Bytes(), nil
+}
+
+func writeQP(w *multipart.Writer, contentType, body string) error {
+	p, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	qp := quotedprintable.NewWriter(p)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
@@ -0,0 +1,36 @@
+// Package fanout sends every event to several notification channels.
+package fanout
+
+import (
+	"errors"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+)
+
+type Notifier struct {
+	channels []ports.Notifier
+}
+
+// New skips nil channels so optional adapters can be passed unconditionally.
+func New(channels ...ports.Notifier) *Notifier {
+	n := &Notifier{}
+	for _, c := range channels {
+		if c != nil {
+			n.channels = append(n.channels, c)
+		}
+	}
+	return n
+}
+
+// Notify delivers e to every channel, even when an earlier one fails, and
+// returns the joined errors.
+func (n *Notifier) Notify(e domain.Event) error {
+	var errs []error
+	for _, c := range n.channels {
+		if err := c.Notify(e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
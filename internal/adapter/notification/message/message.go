@@ -0,0 +1,115 @@
+// Package message sends customer-facing SMS or WhatsApp messages for events
+// that have a template, through any ports.MessageSender.
+package message
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+type Config struct {
+	Channel  string
+	Language string
+	// TemplateDir, when set, replaces the bundled templates with
+	// <TemplateDir>/<Language>.tmpl.
+	TemplateDir string
+	Location    *time.Location
+}
+
+type Notifier struct {
+	sender   ports.MessageSender
+	messages ports.MessageRepository
+	cfg      Config
+	tmpl     *template.Template
+}
+
+func New(sender ports.MessageSender, messages ports.MessageRepository, cfg Config) (*Notifier, error) {
+	if cfg.Channel == "" {
+		cfg.Channel = domain.ChannelWhatsApp
+	}
+	if cfg.Channel != domain.ChannelWhatsApp && cfg.Channel != domain.ChannelSMS {
+		return nil, errors.New("message: unsupported channel " + cfg.Channel)
+	}
+	if cfg.Language == "" {
+		cfg.Language = "id"
+	}
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	t := template.New(cfg.Language).Funcs(template.FuncMap{
+		"datetime": func(t time.Time) string { return t.In(cfg.Location).Format("02/01/2006 15:04") },
+		"clock":    func(t time.Time) string { return t.In(cfg.Location).Format("15:04") },
+	})
+	var err error
+	if cfg.TemplateDir != "" {
+		var src []byte
+		src, err = os.ReadFile(filepath.Join(cfg.TemplateDir, cfg.Language+".tmpl"))
+		if err == nil {
+			t, err = t.Parse(string(src))
+		}
+	} else {
+		t, err = t.ParseFS(templateFS, "templates/"+cfg.Language+".tmpl")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Notifier{sender: sender, messages: messages, cfg: cfg, tmpl: t}, nil
+}
+
+// Notify messages the customer named in the event. Events without a
+// template, without a phone number, or for opted-out phones are skipped.
+// Every attempt is logged with its outcome.
+func (n *Notifier) Notify(e domain.Event) error {
+	if n.tmpl.Lookup(e.Type) == nil {
+		return nil
+	}
+	phone := domain.NormalizePhone(domain.EventPhone(e.Data))
+	if phone == "" {
+		return nil
+	}
+	opted, err := n.messages.IsOptedOut(phone)
+	if err != nil || opted {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := n.tmpl.ExecuteTemplate(&buf, e.Type, struct {
+		Event domain.Event
+		Data  any
+	}{e, e.Data}); err != nil {
+		return err
+	}
+	m := domain.OutboundMessage{
+		Channel:   n.cfg.Channel,
+		To:        phone,
+		Body:      strings.TrimSpace(buf.String()),
+		EventID:   e.ID,
+		EventType: e.Type,
+		Status:    domain.MessageQueued,
+		CreatedAt: time.Now().UTC(),
+	}
+	id, err := n.messages.Create(m)
+	if err != nil {
+		return err
+	}
+	providerID, sendErr := n.sender.Send(m.Channel, m.To, m.Body)
+	status, errText := domain.MessageSent, ""
+	if sendErr != nil {
+		status, errText = domain.MessageFailed, sendErr.Error()
+	}
+	if err := n.messages.Finish(id, status, providerID, errText, time.Now().UTC()); err != nil {
+		return errors.Join(sendErr, err)
+	}
+	return sendErr
+}
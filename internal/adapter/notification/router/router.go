@@ -0,0 +1,156 @@
+// Package router applies notification preferences and quiet hours in front
+// of the channel notifiers.
+package router
+
+import (
+	"errors"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+	"be-golang/internal/util"
+)
+
+const flushBatch = 100
+
+// deferrable lists the channels read by people. Webhooks feed other systems
+// and are never held back.
+var deferrable = map[string]bool{
+	domain.NotifyChannelEmail:   true,
+	domain.NotifyChannelMessage: true,
+}
+
+type Router struct {
+	prefs    ports.NotificationPreferenceRepository
+	deferred ports.DeferredNotificationRepository
+	loc      *time.Location
+	channels map[string]ports.Notifier
+}
+
+func New(prefs ports.NotificationPreferenceRepository, deferred ports.DeferredNotificationRepository, loc *time.Location) *Router {
+	return &Router{prefs: prefs, deferred: deferred, loc: loc, channels: map[string]ports.Notifier{}}
+}
+
+// Channel registers n under name and returns a notifier that applies the
+// preferences before passing events on to n. A nil n stays nil so optional
+// channels can be wired unconditionally. Channels are registered at startup,
+// before any event is sent.
+func (r *Router) Channel(name string, n ports.Notifier) ports.Notifier {
+	if n == nil {
+		return nil
+	}
+	r.channels[name] = n
+	return &gate{router: r, name: name, next: n}
+}
+
+type gate struct {
+	router *Router
+	name   string
+	next   ports.Notifier
+}
+
+// Notify drops events the business, or for customer messages the customer,
+// has switched off, and defers non-urgent events that fall in quiet hours.
+// A customer's own quiet hours take precedence over the business ones.
+func (g *gate) Notify(e domain.Event) error {
+	r := g.router
+	pref, err := r.preference(domain.PreferenceBusiness, "")
+	if err != nil {
+		return err
+	}
+	if !pref.Allows(g.name, e.Type) {
+		return nil
+	}
+	quiet := pref
+	if g.name == domain.NotifyChannelMessage {
+		if phone := domain.NormalizePhone(domain.EventPhone(e.Data)); phone != "" {
+			cust, err := r.preference(domain.PreferenceCustomer, phone)
+			if err != nil {
+				return err
+			}
+			if !cust.Allows(g.name, e.Type) {
+				return nil
+			}
+			if cust.HasQuietHours() {
+				quiet = cust
+			}
+		}
+	}
+	if deferrable[g.name] && !domain.IsUrgentEvent(e.Type) {
+		now := time.Now().UTC()
+		if until, ok := quietUntil(quiet, now, r.loc); ok {
+			return r.deferred.Defer(domain.DeferredNotification{
+				Channel:   g.name,
+				Event:     e,
+				DeliverAt: until,
+				CreatedAt: now,
+			})
+		}
+	}
+	return g.next.Notify(e)
+}
+
+// Flush delivers deferred notifications whose quiet hours have ended.
+func (r *Router) Flush(now time.Time) error {
+	due, err := r.deferred.Due(now, flushBatch)
+	if err != nil {
+		return err
+	}
+	for _, d := range due {
+		status, errText := domain.DeferredSent, ""
+		n, ok := r.channels[d.Channel]
+		if !ok {
+			status, errText = domain.DeferredFailed, "unknown_channel"
+		} else if err := n.Notify(d.Event); err != nil {
+			status, errText = domain.DeferredFailed, err.Error()
+		}
+		if err := r.deferred.Finish(d.ID, status, errText, time.Now().UTC()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// preference returns the stored preference, or the allow-everything default
+// when there is none.
+func (r *Router) preference(scope, subject string) (domain.NotificationPreference, error) {
+	p, err := r.prefs.Get(scope, subject)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.NotificationPreference{Scope: scope, Subject: subject}, nil
+	}
+	if err != nil {
+		return domain.NotificationPreference{}, err
+	}
+	return *p, nil
+}
+
+// quietUntil reports whether now falls in p's quiet window and, if so, when
+// that window ends. Windows where start is after end run past midnight.
+func quietUntil(p domain.NotificationPreference, now time.Time, loc *time.Location) (time.Time, bool) {
+	if !p.HasQuietHours() {
+		return time.Time{}, false
+	}
+	local := now.In(loc)
+	start, err := util.AtClock(local, p.QuietStart, loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := util.AtClock(local, p.QuietEnd, loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if start.Before(end) {
+		if !now.Before(start) && now.Before(end) {
+			return end, true
+		}
+		return time.Time{}, false
+	}
+	if now.Before(end) {
+		return end, true
+	}
+	if !now.Before(start) {
+		next, err := util.AtClock(local.AddDate(0, 0, 1), p.QuietEnd, loc)
+		return next, err == nil
+	}
+	return time.Time{}, false
+}
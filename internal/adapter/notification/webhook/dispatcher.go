@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+	"be-golang/pkg/webhooksig"
+)
+
+const snippetLimit = 512
+
+// Dispatcher fans every event out to the active webhook subscribers whose
+// event filter matches. Subscribers registered at runtime come from the
+// repository; static ones (e.g. N8N_WEBHOOK_URL) are passed to New and are
+// not delivery-logged because they have no row to log against.
+type Dispatcher struct {
+	subscribers ports.WebhookRepository
+	static      []domain.WebhookSubscriber
+	httpc       *http.Client
+}
+
+func New(subscribers ports.WebhookRepository, static ...domain.WebhookSubscriber) *Dispatcher {
+	return &Dispatcher{subscribers: subscribers, static: static, httpc: &http.Client{Timeout: 5 * time.Second}}
+}
+
+func (d *Dispatcher) Notify(e domain.Event) error {
+	subs := append([]domain.WebhookSubscriber(nil), d.static...)
+	if d.subscribers != nil {
+		active, err := d.subscribers.ListActive()
+		if err != nil {
+			return err
+		}
+		subs = append(subs, active...)
+	}
+	var errs []error
+	for _, s := range subs {
+		if !s.Active || s.URL == "" || !s.Wants(e.Type) {
+			continue
+		}
+		if _, err := d.Deliver(s, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Deliver sends e to a single subscriber regardless of its filter and records
+// the outcome in the subscriber's delivery log.
+func (d *Dispatcher) Deliver(s domain.WebhookSubscriber, e domain.Event) (domain.WebhookDelivery, error) {
+	del := domain.WebhookDelivery{SubscriberID: s.ID, EventID: e.ID, EventType: e.Type, CreatedAt: time.Now().UTC()}
+	err := d.post(s, e, &del)
+	if err != nil {
+		del.Error = err.Error()
+	}
+	if s.ID != 0 && d.subscribers != nil {
+		_ = d.subscribers.LogDelivery(del)
+	}
+	return del, err
+}
+
+func (d *Dispatcher) post(s domain.WebhookSubscriber, e domain.Event, del *domain.WebhookDelivery) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", s.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	for k, v := range s.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Type", e.Type)
+	req.Header.Set("X-Event-Id", e.ID)
+	req.Header.Set("X-Event-Schema-Version", strconv.Itoa(e.SchemaVersion))
+	webhooksig.SignRequest(req, []string{s.Secret, s.PreviousSecret}, data, time.Now())
+	start := time.Now()
+	resp, err := d.httpc.Do(req)
+	del.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, snippetLimit))
+	del.StatusCode = resp.StatusCode
+	del.ResponseSnippet = string(snippet)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s responded %d", s.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+var _ ports.Notifier = (*Dispatcher)(nil)
+var _ ports.WebhookSender = (*Dispatcher)(nil)
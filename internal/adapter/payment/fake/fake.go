@@ -0,0 +1,128 @@
+// Package fake is an in-memory payment gateway for tests and local
+// development. No money moves; callbacks are built with Callback and signed
+// the same way as outgoing webhooks (see pkg/webhooksig).
+package fake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/pkg/webhooksig"
+)
+
+type Refund struct {
+	ExternalID string
+	Amount     int64
+}
+
+type Gateway struct {
+	secret   string
+	mu       sync.Mutex
+	intents  []domain.Payment
+	refunds  []Refund
+	failNext error
+}
+
+// New returns a gateway that accepts callbacks signed with secret. With an
+// empty secret every callback is rejected.
+func New(secret string) *Gateway { return &Gateway{secret: secret} }
+
+func (g *Gateway) Name() string { return "fake" }
+
+func (g *Gateway) CreateIntent(p domain.Payment, b domain.Booking) (domain.PaymentIntent, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.takeFailure(); err != nil {
+		return domain.PaymentIntent{}, err
+	}
+	p.ExternalID = "fake-pay-" + strconv.Itoa(len(g.intents)+1)
+	g.intents = append(g.intents, p)
+	in := domain.PaymentIntent{ExternalID: p.ExternalID}
+	switch p.Method {
+	case domain.PaymentMethodVA:
+		in.VANumber = fmt.Sprintf("8808%012d", p.ID)
+	default:
+		in.QRString = "FAKEQRIS." + p.ExternalID + "." + strconv.FormatInt(p.Amount, 10)
+	}
+	return in, nil
+}
+
+func (g *Gateway) Refund(p domain.Payment, amount int64) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.takeFailure(); err != nil {
+		return err
+	}
+	g.refunds = append(g.refunds, Refund{ExternalID: p.ExternalID, Amount: amount})
+	return nil
+}
+
+type callbackBody struct {
+	ExternalID string `json:"external_id"`
+	Status     string `json:"status"`
+	Amount     int64  `json:"amount"`
+}
+
+func (g *Gateway) ParseCallback(header func(key string) string, body []byte) (domain.PaymentCallback, error) {
+	if g.secret == "" {
+		return domain.PaymentCallback{}, domain.ErrUnauthorized
+	}
+	err := webhooksig.Verify([]string{g.secret}, header(webhooksig.HeaderTimestamp), header(webhooksig.HeaderSignature), body, 0, time.Now())
+	if err != nil {
+		return domain.PaymentCallback{}, domain.ErrUnauthorized
+	}
+	var cb callbackBody
+	if err := json.Unmarshal(body, &cb); err != nil || cb.ExternalID == "" {
+		return domain.PaymentCallback{}, domain.ErrInvalidInput
+	}
+	return domain.PaymentCallback{ExternalID: cb.ExternalID, Status: cb.Status, Amount: cb.Amount}, nil
+}
+
+// Callback builds the signed headers and body of a callback reporting
+// status (domain.PaymentPaid or domain.PaymentExpired) for a payment.
+func (g *Gateway) Callback(externalID, status string, amount int64) (map[string]string, []byte) {
+	body, _ := json.Marshal(callbackBody{ExternalID: externalID, Status: status, Amount: amount})
+	ts := time.Now().Unix()
+	return map[string]string{
+		"Content-Type":             "application/json",
+		webhooksig.HeaderTimestamp: strconv.FormatInt(ts, 10),
+		webhooksig.HeaderSignature: webhooksig.SignatureHeader([]string{g.secret}, ts, body),
+	}, body
+}
+
+// Intents returns a copy of the payments intents were created for, with
+// ExternalID filled.
+func (g *Gateway) Intents() []domain.Payment {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]domain.Payment(nil), g.intents...)
+}
+
+// Refunds returns a copy of the refunds made so far.
+func (g *Gateway) Refunds() []Refund {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]Refund(nil), g.refunds...)
+}
+
+// FailNext makes the next CreateIntent or Refund return err. A nil err
+// uses a generic error.
+func (g *Gateway) FailNext(err error) {
+	if err == nil {
+		err = errors.New("fake: gateway failed")
+	}
+	g.mu.Lock()
+	g.failNext = err
+	g.mu.Unlock()
+}
+
+func (g *Gateway) takeFailure() error {
+	err := g.failNext
+	g.failNext = nil
+	return err
+}
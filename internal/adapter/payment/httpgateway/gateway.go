@@ -0,0 +1,325 @@
+// Package httpgateway collects payments through a gateway that creates QRIS
+// or virtual account payment intents over HTTP, the way most Indonesian
+// aggregators do. Request bodies are templates and response and callback
+// fields are dotted paths, so a new gateway needs configuration, not code.
+package httpgateway
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/util"
+)
+
+// DefaultBody is used when Config.Body is empty.
+const DefaultBody = `{"reference_id":{{json .Reference}},"amount":{{.Amount}},"currency":"IDR","method":{{json .Method}},"bank":{{json .Bank}},` +
+	`"customer_name":{{json .CustomerName}},"customer_phone":{{json .CustomerPhone}},"expires_at":{{json .ExpiresAt}}}`
+
+// DefaultRefundBody is used when Config.RefundBody is empty.
+const DefaultRefundBody = `{"reference_id":{{json .Reference}},"amount":{{.Amount}},"reason":"booking_cancelled"}`
+
+// DefaultSignatureHeader carries the callback signature when
+// Config.SignatureHeader is empty.
+const DefaultSignatureHeader = "X-Callback-Signature"
+
+// NoAmountField as Config.CallbackAmountField declares that the gateway's
+// callbacks carry no amount, so paid callbacks are not checked against it.
+const NoAmountField = "-"
+
+var (
+	defaultPaidStatuses    = []string{"paid", "settlement", "capture", "succeeded", "success", "completed"}
+	defaultExpiredStatuses = []string{"expired", "expire", "cancel", "cancelled", "failed", "deny"}
+)
+
+type Config struct {
+	// Provider names the gateway on stored payments (default "http").
+	Provider string
+	URL      string
+	// RefundURL is a text/template over the same fields as RefundBody,
+	// e.g. "https://api.example/payments/{{.ExternalID}}/refunds". Refunds
+	// fail when it is empty.
+	RefundURL string
+	// Token is sent in AuthHeader (default "Authorization") when set.
+	Token      string
+	AuthHeader string
+	// Body is a text/template over .Reference, .Amount, .Method, .Bank,
+	// .CustomerName, .CustomerPhone, .ExpiresAt (RFC 3339) and
+	// .ExpiryMinutes. RefundBody sees .Reference, .ExternalID and .Amount.
+	// The functions json and query escape a value for JSON and form bodies.
+	Body        string
+	RefundBody  string
+	ContentType string
+	// IDField, QRField, VAField and URLField are dotted paths into the JSON
+	// response of URL, e.g. "id" or "data.qr_string".
+	IDField  string
+	QRField  string
+	VAField  string
+	URLField string
+	// Callbacks are accepted when SignatureHeader holds the hex HMAC-SHA256
+	// of the raw body under one of CallbackSecrets, optionally prefixed with
+	// "sha256=".
+	CallbackSecrets []string
+	SignatureHeader string
+	// CallbackIDField, CallbackStatusField and CallbackAmountField are
+	// dotted paths into the callback body. The ID must match the one read
+	// through IDField. A paid callback without a readable amount counts as
+	// paying nothing, unless CallbackAmountField is NoAmountField.
+	CallbackIDField     string
+	CallbackStatusField string
+	CallbackAmountField string
+	// PaidStatuses and ExpiredStatuses list the gateway statuses, compared
+	// case-insensitively, that mean paid and expired. Others are ignored.
+	PaidStatuses    []string
+	ExpiredStatuses []string
+}
+
+type Gateway struct {
+	cfg        Config
+	body       *template.Template
+	refundURL  *template.Template
+	refundBody *template.Template
+	httpc      *http.Client
+}
+
+func New(cfg Config) (*Gateway, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("httpgateway: url is required")
+	}
+	if cfg.Provider == "" {
+		cfg.Provider = "http"
+	}
+	if cfg.Body == "" {
+		cfg.Body = DefaultBody
+	}
+	if cfg.RefundBody == "" {
+		cfg.RefundBody = DefaultRefundBody
+	}
+	if cfg.ContentType == "" {
+		cfg.ContentType = "application/json"
+	}
+	if cfg.AuthHeader == "" {
+		cfg.AuthHeader = "Authorization"
+	}
+	if cfg.IDField == "" {
+		cfg.IDField = "id"
+	}
+	if cfg.SignatureHeader == "" {
+		cfg.SignatureHeader = DefaultSignatureHeader
+	}
+	if cfg.CallbackIDField == "" {
+		cfg.CallbackIDField = "id"
+	}
+	if cfg.CallbackStatusField == "" {
+		cfg.CallbackStatusField = "status"
+	}
+	if cfg.CallbackAmountField == "" {
+		cfg.CallbackAmountField = "amount"
+	}
+	if len(cfg.PaidStatuses) == 0 {
+		cfg.PaidStatuses = defaultPaidStatuses
+	}
+	if len(cfg.ExpiredStatuses) == 0 {
+		cfg.ExpiredStatuses = defaultExpiredStatuses
+	}
+	g := &Gateway{cfg: cfg, httpc: &http.Client{Timeout: 10 * time.Second}}
+	var err error
+	if g.body, err = parse("body", cfg.Body); err != nil {
+		return nil, err
+	}
+	if g.refundBody, err = parse("refund_body", cfg.RefundBody); err != nil {
+		return nil, err
+	}
+	if cfg.RefundURL != "" {
+		if g.refundURL, err = parse("refund_url", cfg.RefundURL); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+func parse(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(template.FuncMap{
+		"json": func(s string) string {
+			b, _ := json.Marshal(s)
+			return string(b)
+		},
+		"query": url.QueryEscape,
+	}).Parse(text)
+}
+
+func (g *Gateway) Name() string { return g.cfg.Provider }
+
+// reference is the merchant reference sent for payment p. It is unique per
+// payment, so a booking that is paid again after an expiry gets a new one.
+func reference(p domain.Payment) string {
+	return "BK" + strconv.FormatInt(p.BookingID, 10) + "-" + strconv.FormatInt(p.ID, 10)
+}
+
+func (g *Gateway) CreateIntent(p domain.Payment, b domain.Booking) (domain.PaymentIntent, error) {
+	data := struct {
+		Reference     string
+		Amount        int64
+		Method        string
+		Bank          string
+		CustomerName  string
+		CustomerPhone string
+		ExpiresAt     string
+		ExpiryMinutes int64
+	}{
+		Reference:     reference(p),
+		Amount:        p.Amount,
+		Method:        p.Method,
+		Bank:          p.Bank,
+		CustomerName:  b.CustomerName,
+		CustomerPhone: domain.NormalizePhone(b.CustomerPhone),
+		ExpiresAt:     p.ExpiresAt.UTC().Format(time.RFC3339),
+		ExpiryMinutes: int64(math.Ceil(time.Until(p.ExpiresAt).Minutes())),
+	}
+	var buf bytes.Buffer
+	if err := g.body.Execute(&buf, data); err != nil {
+		return domain.PaymentIntent{}, err
+	}
+	respBody, err := g.post(g.cfg.URL, &buf)
+	if err != nil {
+		return domain.PaymentIntent{}, err
+	}
+	var payload any
+	if err := json.Unmarshal(respBody, &payload); err != nil {
+		return domain.PaymentIntent{}, fmt.Errorf("httpgateway: invalid response: %w", err)
+	}
+	var in domain.PaymentIntent
+	var ok bool
+	if in.ExternalID, ok = util.LookupJSON(payload, g.cfg.IDField); !ok {
+		return domain.PaymentIntent{}, fmt.Errorf("httpgateway: response has no %q: %s", g.cfg.IDField, snippet(respBody))
+	}
+	if g.cfg.QRField != "" {
+		in.QRString, _ = util.LookupJSON(payload, g.cfg.QRField)
+	}
+	if g.cfg.VAField != "" {
+		in.VANumber, _ = util.LookupJSON(payload, g.cfg.VAField)
+	}
+	if g.cfg.URLField != "" {
+		in.PaymentURL, _ = util.LookupJSON(payload, g.cfg.URLField)
+	}
+	return in, nil
+}
+
+func (g *Gateway) Refund(p domain.Payment, amount int64) error {
+	if g.refundURL == nil {
+		return errors.New("httpgateway: refund url is not configured")
+	}
+	data := struct {
+		Reference  string
+		ExternalID string
+		Amount     int64
+	}{reference(p), p.ExternalID, amount}
+	var u, buf bytes.Buffer
+	if err := g.refundURL.Execute(&u, data); err != nil {
+		return err
+	}
+	if err := g.refundBody.Execute(&buf, data); err != nil {
+		return err
+	}
+	_, err := g.post(u.String(), &buf)
+	return err
+}
+
+func (g *Gateway) post(target string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, target, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", g.cfg.ContentType)
+	if g.cfg.Token != "" {
+		req.Header.Set(g.cfg.AuthHeader, g.cfg.Token)
+	}
+	resp, err := g.httpc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("httpgateway: status %d: %s", resp.StatusCode, snippet(respBody))
+	}
+	return respBody, nil
+}
+
+func (g *Gateway) ParseCallback(header func(key string) string, body []byte) (domain.PaymentCallback, error) {
+	if !g.verify(header(g.cfg.SignatureHeader), body) {
+		return domain.PaymentCallback{}, domain.ErrUnauthorized
+	}
+	var payload any
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return domain.PaymentCallback{}, domain.ErrInvalidInput.Wrap(err)
+	}
+	id, ok := util.LookupJSON(payload, g.cfg.CallbackIDField)
+	if !ok {
+		return domain.PaymentCallback{}, domain.ErrInvalidInput
+	}
+	cb := domain.PaymentCallback{ExternalID: id}
+	status, _ := util.LookupJSON(payload, g.cfg.CallbackStatusField)
+	switch {
+	case contains(g.cfg.PaidStatuses, status):
+		cb.Status = domain.PaymentPaid
+	case contains(g.cfg.ExpiredStatuses, status):
+		cb.Status = domain.PaymentExpired
+	}
+	if g.cfg.CallbackAmountField == NoAmountField {
+		cb.NoAmount = true
+	} else if s, ok := util.LookupJSON(payload, g.cfg.CallbackAmountField); ok {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			cb.Amount = int64(math.Round(f))
+		}
+	}
+	return cb, nil
+}
+
+func (g *Gateway) verify(sig string, body []byte) bool {
+	sig = strings.TrimPrefix(strings.TrimSpace(sig), "sha256=")
+	got, err := hex.DecodeString(sig)
+	if err != nil || len(got) == 0 {
+		return false
+	}
+	for _, secret := range g.cfg.CallbackSecrets {
+		if secret == "" {
+			continue
+		}
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		if hmac.Equal(got, mac.Sum(nil)) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func snippet(b []byte) string {
+	if len(b) > 256 {
+		b = b[:256]
+	}
+	return string(b)
+}
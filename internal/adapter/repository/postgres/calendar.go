@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"be-golang/internal/domain"
+)
+
+type CalendarTokenRepo struct{ db *sql.DB }
+
+func (c *Connection) CalendarTokens() *CalendarTokenRepo { return &CalendarTokenRepo{db: c.DB} }
+
+const calendarTokenColumns = `id, user_id, name, token_hash, service_id, created_at, last_used_at, revoked_at`
+
+func scanCalendarToken(row rowScanner) (domain.CalendarToken, error) {
+	var t domain.CalendarToken
+	var serviceID sql.NullInt64
+	var lastUsed, revoked sql.NullTime
+	err := row.Scan(&t.ID, &t.UserID, &t.Name, &t.TokenHash, &serviceID, &t.CreatedAt, &lastUsed, &revoked)
+	if serviceID.Valid {
+		t.ServiceID = &serviceID.Int64
+	}
+	if lastUsed.Valid {
+		t.LastUsedAt = &lastUsed.Time
+	}
+	if revoked.Valid {
+		t.RevokedAt = &revoked.Time
+	}
+	return t, err
+}
+
+func (r *CalendarTokenRepo) Create(t domain.CalendarToken) (int64, error) {
+	err := r.db.QueryRow(
+		`INSERT INTO calendar_tokens (user_id, name, token_hash, service_id, created_at) VALUES ($1,$2,$3,$4,$5) RETURNING id`,
+		t.UserID, t.Name, t.TokenHash, t.ServiceID, t.CreatedAt,
+	).Scan(&t.ID)
+	if err != nil {
+		return 0, err
+	}
+	return t.ID, nil
+}
+
+func (r *CalendarTokenRepo) ListByUser(userID int64) ([]domain.CalendarToken, error) {
+	rows, err := r.db.Query(`SELECT `+calendarTokenColumns+` FROM calendar_tokens WHERE user_id=$1 ORDER BY id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.CalendarToken
+	for rows.Next() {
+		t, err := scanCalendarToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+func (r *CalendarTokenRepo) GetByHash(hash string) (*domain.CalendarToken, error) {
+	t, err := scanCalendarToken(r.db.QueryRow(
+		`SELECT `+calendarTokenColumns+` FROM calendar_tokens WHERE token_hash=$1 AND revoked_at IS NULL`, hash,
+	))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *CalendarTokenRepo) Revoke(id, userID int64, at time.Time) error {
+	res, err := r.db.Exec(
+		`UPDATE calendar_tokens SET revoked_at=$3 WHERE id=$1 AND user_id=$2 AND revoked_at IS NULL`, id, userID, at,
+	)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *CalendarTokenRepo) Touch(id int64, at time.Time) error {
+	_, err := r.db.Exec(`UPDATE calendar_tokens SET last_used_at=$2 WHERE id=$1`, id, at)
+	return err
+}
+
+var _ interface {
+	Create(domain.CalendarToken) (int64, error)
+	ListByUser(int64) ([]domain.CalendarToken, error)
+	GetByHash(string) (*domain.CalendarToken, error)
+	Revoke(int64, int64, time.Time) error
+	Touch(int64, time.Time) error
+} = (*CalendarTokenRepo)(nil)
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"be-golang/internal/domain"
+)
+
+type CancellationPolicyRepo struct{ db *sql.DB }
+
+func (c *Connection) CancellationPolicies() *CancellationPolicyRepo {
+	return &CancellationPolicyRepo{db: c.DB}
+}
+
+const cancellationPolicyColumns = `service_id, free_cancel_minutes, late_cancel_fee_percent, no_show_fee_percent, updated_at`
+
+func scanCancellationPolicy(row rowScanner) (domain.CancellationPolicy, error) {
+	var p domain.CancellationPolicy
+	err := row.Scan(&p.ServiceID, &p.FreeCancelMinutes, &p.LateCancelFeePercent, &p.NoShowFeePercent, &p.UpdatedAt)
+	return p, err
+}
+
+func (r *CancellationPolicyRepo) Get(serviceID int64) (*domain.CancellationPolicy, error) {
+	p, err := scanCancellationPolicy(r.db.QueryRow(
+		`SELECT `+cancellationPolicyColumns+` FROM cancellation_policies WHERE service_id=$1`, serviceID,
+	))
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return &p, nil
+}
+
+func (r *CancellationPolicyRepo) List() ([]domain.CancellationPolicy, error) {
+	rows, err := r.db.Query(`SELECT ` + cancellationPolicyColumns + ` FROM cancellation_policies ORDER BY service_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.CancellationPolicy
+	for rows.Next() {
+		p, err := scanCancellationPolicy(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (r *CancellationPolicyRepo) Save(p domain.CancellationPolicy) error {
+	_, err := r.db.Exec(
+		`INSERT INTO cancellation_policies (service_id, free_cancel_minutes, late_cancel_fee_percent, no_show_fee_percent, updated_at)
+		 VALUES ($1,$2,$3,$4,$5)
+		 ON CONFLICT (service_id) DO UPDATE SET free_cancel_minutes=EXCLUDED.free_cancel_minutes,
+		   late_cancel_fee_percent=EXCLUDED.late_cancel_fee_percent, no_show_fee_percent=EXCLUDED.no_show_fee_percent,
+		   updated_at=EXCLUDED.updated_at`,
+		p.ServiceID, p.FreeCancelMinutes, p.LateCancelFeePercent, p.NoShowFeePercent, p.UpdatedAt,
+	)
+	return dbError(err)
+}
+
+func (r *CancellationPolicyRepo) Delete(serviceID int64) error {
+	return affectedOne(r.db.Exec(`DELETE FROM cancellation_policies WHERE service_id=$1`, serviceID))
+}
+
+var _ interface {
+	Get(int64) (*domain.CancellationPolicy, error)
+	List() ([]domain.CancellationPolicy, error)
+	Save(domain.CancellationPolicy) error
+	Delete(int64) error
+} = (*CancellationPolicyRepo)(nil)
@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"be-golang/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+type CategoryRepo struct{ db *sql.DB }
+
+func (c *Connection) Categories() *CategoryRepo { return &CategoryRepo{db: c.DB} }
+
+const categoryColumns = `id, name, sort_order, created_at`
+
+func scanCategory(row rowScanner) (domain.Category, error) {
+	var c domain.Category
+	err := row.Scan(&c.ID, &c.Name, &c.SortOrder, &c.CreatedAt)
+	return c, err
+}
+
+func (r *CategoryRepo) Create(c domain.Category) (int64, error) {
+	err := r.db.QueryRow(
+		`INSERT INTO categories (name, sort_order) VALUES ($1,$2) RETURNING id`,
+		c.Name, c.SortOrder,
+	).Scan(&c.ID)
+	if err != nil {
+		return 0, dbError(err)
+	}
+	return c.ID, nil
+}
+
+func (r *CategoryRepo) GetByID(id int64) (*domain.Category, error) {
+	c, err := scanCategory(r.db.QueryRow(`SELECT `+categoryColumns+` FROM categories WHERE id=$1`, id))
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return &c, nil
+}
+
+func (r *CategoryRepo) Update(c domain.Category) error {
+	return affectedOne(r.db.Exec(`UPDATE categories SET name=$2, sort_order=$3 WHERE id=$1`, c.ID, c.Name, c.SortOrder))
+}
+
+func (r *CategoryRepo) Delete(id int64) error {
+	return affectedOne(r.db.Exec(`DELETE FROM categories WHERE id=$1`, id))
+}
+
+func (r *CategoryRepo) List() ([]domain.Category, error) {
+	rows, err := r.db.Query(`SELECT ` + categoryColumns + ` FROM categories ORDER BY sort_order, name, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.Category
+	for rows.Next() {
+		c, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+func (r *CategoryRepo) Reorder(ids []int64) error {
+	return reorder(r.db, "categories", ids)
+}
+
+// reorder sets sort_order of the rows in table to their position in ids.
+// Rows not listed keep their order.
+func reorder(db *sql.DB, table string, ids []int64) error {
+	_, err := db.Exec(
+		`UPDATE `+table+` t SET sort_order = x.ord
+		 FROM unnest($1::bigint[]) WITH ORDINALITY AS x(id, ord) WHERE t.id = x.id`,
+		pq.Array(ids),
+	)
+	return err
+}
+
+var _ interface {
+	Create(domain.Category) (int64, error)
+	GetByID(int64) (*domain.Category, error)
+	Update(domain.Category) error
+	Delete(int64) error
+	List() ([]domain.Category, error)
+	Reorder([]int64) error
+} = (*CategoryRepo)(nil)
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"be-golang/internal/domain"
+)
+
+type IdempotencyRepo struct{ db *sql.DB }
+
+func (c *Connection) Idempotency() *IdempotencyRepo { return &IdempotencyRepo{db: c.DB} }
+
+func (r *IdempotencyRepo) Begin(rec domain.IdempotencyRecord, staleBefore time.Time) (*domain.IdempotencyRecord, error) {
+	res, err := r.db.Exec(
+		`INSERT INTO idempotency_keys (idem_key, scope, fingerprint, status_code, content_type, body, created_at, expires_at)
+		 VALUES ($1,$2,$3,0,'','',$4,$5)
+		 ON CONFLICT (idem_key, scope) DO UPDATE SET fingerprint=EXCLUDED.fingerprint, status_code=0, content_type='',
+		   body='', created_at=EXCLUDED.created_at, expires_at=EXCLUDED.expires_at
+		 WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+		    OR (idempotency_keys.status_code = 0 AND idempotency_keys.created_at < $6)`,
+		rec.Key, rec.Scope, rec.Fingerprint, rec.CreatedAt, rec.ExpiresAt, staleBefore,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := res.RowsAffected(); n == 1 {
+		return nil, nil
+	}
+	var existing domain.IdempotencyRecord
+	err = r.db.QueryRow(
+		`SELECT idem_key, scope, fingerprint, status_code, content_type, body, created_at, expires_at
+		 FROM idempotency_keys WHERE idem_key=$1 AND scope=$2`, rec.Key, rec.Scope,
+	).Scan(&existing.Key, &existing.Scope, &existing.Fingerprint, &existing.StatusCode, &existing.ContentType, &existing.Body, &existing.CreatedAt, &existing.ExpiresAt)
+	if err == sql.ErrNoRows {
+		// Released between the insert and the select; the caller may retry.
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+func (r *IdempotencyRepo) Complete(rec domain.IdempotencyRecord) error {
+	_, err := r.db.Exec(
+		`UPDATE idempotency_keys SET status_code=$3, content_type=$4, body=$5 WHERE idem_key=$1 AND scope=$2`,
+		rec.Key, rec.Scope, rec.StatusCode, rec.ContentType, rec.Body,
+	)
+	return err
+}
+
+func (r *IdempotencyRepo) Release(key, scope string) error {
+	_, err := r.db.Exec(`DELETE FROM idempotency_keys WHERE idem_key=$1 AND scope=$2`, key, scope)
+	return err
+}
+
+func (r *IdempotencyRepo) DeleteExpired(now time.Time) (int64, error) {
+	res, err := r.db.Exec(`DELETE FROM idempotency_keys WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+var _ interface {
+	Begin(domain.IdempotencyRecord, time.Time) (*domain.IdempotencyRecord, error)
+	Complete(domain.IdempotencyRecord) error
+	Release(string, string) error
+	DeleteExpired(time.Time) (int64, error)
+} = (*IdempotencyRepo)(nil)
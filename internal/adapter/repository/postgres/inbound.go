@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"be-golang/internal/domain"
+)
+
+type InboundRepo struct{ db *sql.DB }
+
+func (c *Connection) Inbound() *InboundRepo { return &InboundRepo{db: c.DB} }
+
+// Record inserts m unless its message ID exists. A message whose earlier
+// attempt failed on an internal error is taken over again so the sender's
+// retry is processed.
+func (r *InboundRepo) Record(m domain.InboundMessage) (bool, error) {
+	res, err := r.db.Exec(
+		`INSERT INTO inbound_messages (message_id, source, command, booking_id, phone, text, status, error, created_at)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9) ON CONFLICT (message_id) DO UPDATE SET
+		   source=EXCLUDED.source, command=EXCLUDED.command, booking_id=EXCLUDED.booking_id, phone=EXCLUDED.phone,
+		   text=EXCLUDED.text, status=EXCLUDED.status, error=EXCLUDED.error, processed_at=NULL
+		 WHERE inbound_messages.status='failed'`,
+		m.MessageID, m.Source, m.Command, m.BookingID, m.Phone, m.Text, m.Status, m.Error, m.CreatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (r *InboundRepo) Get(messageID string) (*domain.InboundMessage, error) {
+	var m domain.InboundMessage
+	var processed sql.NullTime
+	err := r.db.QueryRow(
+		`SELECT message_id, source, command, booking_id, phone, text, status, error, created_at, processed_at
+		 FROM inbound_messages WHERE message_id=$1`, messageID,
+	).Scan(&m.MessageID, &m.Source, &m.Command, &m.BookingID, &m.Phone, &m.Text, &m.Status, &m.Error, &m.CreatedAt, &processed)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if processed.Valid {
+		m.ProcessedAt = &processed.Time
+	}
+	return &m, nil
+}
+
+func (r *InboundRepo) Finish(messageID, status, errCode string, at time.Time) error {
+	_, err := r.db.Exec(`UPDATE inbound_messages SET status=$2, error=$3, processed_at=$4 WHERE message_id=$1`, messageID, status, errCode, at)
+	return err
+}
+
+var _ interface {
+	Record(domain.InboundMessage) (bool, error)
+	Get(string) (*domain.InboundMessage, error)
+	Finish(string, string, string, time.Time) error
+} = (*InboundRepo)(nil)
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"be-golang/internal/domain"
+)
+
+type InvoiceRepo struct{ db *sql.DB }
+
+func (c *Connection) Invoices() *InvoiceRepo { return &InvoiceRepo{db: c.DB} }
+
+const invoiceColumns = `id, number, year, sequence, booking_id, customer_name, customer_phone, service_name,
+	subtotal, promo_code, discount, tax_percent, tax, total, deposit, issued_at`
+
+func scanInvoice(row rowScanner) (domain.Invoice, error) {
+	var inv domain.Invoice
+	err := row.Scan(&inv.ID, &inv.Number, &inv.Year, &inv.Sequence, &inv.BookingID, &inv.CustomerName, &inv.CustomerPhone, &inv.ServiceName,
+		&inv.Subtotal, &inv.PromoCode, &inv.Discount, &inv.TaxPercent, &inv.Tax, &inv.Total, &inv.Deposit, &inv.IssuedAt)
+	return inv, err
+}
+
+// Create bumps the year's counter and inserts the invoice in one
+// transaction. The counter row stays locked until commit, so concurrent
+// invoices take numbers one after another, and a rollback returns the
+// number.
+func (r *InvoiceRepo) Create(inv *domain.Invoice, prefix string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var seq int
+	err = tx.QueryRow(
+		`INSERT INTO invoice_sequences (year, last_number) VALUES ($1, 1)
+		 ON CONFLICT (year) DO UPDATE SET last_number = invoice_sequences.last_number + 1
+		 RETURNING last_number`, inv.Year,
+	).Scan(&seq)
+	if err != nil {
+		return err
+	}
+	number := domain.InvoiceNumber(prefix, inv.Year, seq)
+	var id int64
+	err = tx.QueryRow(
+		`INSERT INTO invoices (number, year, sequence, booking_id, customer_name, customer_phone, service_name,
+		   subtotal, promo_code, discount, tax_percent, tax, total, deposit, issued_at)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15) RETURNING id`,
+		number, inv.Year, seq, inv.BookingID, inv.CustomerName, inv.CustomerPhone, inv.ServiceName,
+		inv.Subtotal, inv.PromoCode, inv.Discount, inv.TaxPercent, inv.Tax, inv.Total, inv.Deposit, inv.IssuedAt,
+	).Scan(&id)
+	if err != nil {
+		return dbError(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	inv.ID, inv.Sequence, inv.Number = id, seq, number
+	return nil
+}
+
+func (r *InvoiceRepo) GetByBooking(bookingID int64) (*domain.Invoice, error) {
+	inv, err := scanInvoice(r.db.QueryRow(`SELECT `+invoiceColumns+` FROM invoices WHERE booking_id=$1`, bookingID))
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return &inv, nil
+}
+
+func (r *InvoiceRepo) ListByYear(year int) ([]domain.Invoice, error) {
+	rows, err := r.db.Query(`SELECT `+invoiceColumns+` FROM invoices WHERE year=$1 ORDER BY sequence`, year)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.Invoice
+	for rows.Next() {
+		inv, err := scanInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, inv)
+	}
+	return out, rows.Err()
+}
+
+var _ interface {
+	Create(*domain.Invoice, string) error
+	GetByBooking(int64) (*domain.Invoice, error)
+	ListByYear(int) ([]domain.Invoice, error)
+} = (*InvoiceRepo)(nil)
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"be-golang/internal/domain"
+)
+
+type MessageRepo struct{ db *sql.DB }
+
+func (c *Connection) Messages() *MessageRepo { return &MessageRepo{db: c.DB} }
+
+const messageColumns = `id, channel, recipient, body, event_id, event_type, status, provider_id, error, created_at, updated_at`
+
+func scanMessage(row rowScanner) (domain.OutboundMessage, error) {
+	var m domain.OutboundMessage
+	err := row.Scan(&m.ID, &m.Channel, &m.To, &m.Body, &m.EventID, &m.EventType, &m.Status, &m.ProviderID, &m.Error, &m.CreatedAt, &m.UpdatedAt)
+	return m, err
+}
+
+func (r *MessageRepo) Create(m domain.OutboundMessage) (int64, error) {
+	err := r.db.QueryRow(
+		`INSERT INTO outbound_messages (channel, recipient, body, event_id, event_type, status, provider_id, error, created_at, updated_at)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$9) RETURNING id`,
+		m.Channel, m.To, m.Body, m.EventID, m.EventType, m.Status, m.ProviderID, m.Error, m.CreatedAt,
+	).Scan(&m.ID)
+	if err != nil {
+		return 0, err
+	}
+	return m.ID, nil
+}
+
+func (r *MessageRepo) Finish(id int64, status, providerID, errText string, at time.Time) error {
+	_, err := r.db.Exec(`UPDATE outbound_messages SET status=$2, provider_id=$3, error=$4, updated_at=$5 WHERE id=$1`, id, status, providerID, errText, at)
+	return err
+}
+
+func (r *MessageRepo) GetByProviderID(providerID string) (*domain.OutboundMessage, error) {
+	m, err := scanMessage(r.db.QueryRow(`SELECT `+messageColumns+` FROM outbound_messages WHERE provider_id=$1 ORDER BY id DESC LIMIT 1`, providerID))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *MessageRepo) UpdateStatus(id int64, status, errText string, at time.Time) error {
+	_, err := r.db.Exec(`UPDATE outbound_messages SET status=$2, error=$3, updated_at=$4 WHERE id=$1`, id, status, errText, at)
+	return err
+}
+
+func (r *MessageRepo) ListRecent(limit int) ([]domain.OutboundMessage, error) {
+	rows, err := r.db.Query(`SELECT `+messageColumns+` FROM outbound_messages ORDER BY id DESC LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.OutboundMessage
+	for rows.Next() {
+		m, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, nil
+}
+
+func (r *MessageRepo) IsOptedOut(phone string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`SELECT EXISTS (SELECT 1 FROM message_opt_outs WHERE phone=$1)`, phone).Scan(&exists)
+	return exists, err
+}
+
+func (r *MessageRepo) OptOut(o domain.MessageOptOut) error {
+	_, err := r.db.Exec(
+		`INSERT INTO message_opt_outs (phone, reason, created_at) VALUES ($1,$2,$3) ON CONFLICT (phone) DO NOTHING`,
+		o.Phone, o.Reason, o.CreatedAt,
+	)
+	return err
+}
+
+func (r *MessageRepo) OptIn(phone string) error {
+	res, err := r.db.Exec(`DELETE FROM message_opt_outs WHERE phone=$1`, phone)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MessageRepo) ListOptOuts() ([]domain.MessageOptOut, error) {
+	rows, err := r.db.Query(`SELECT phone, reason, created_at FROM message_opt_outs ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.MessageOptOut
+	for rows.Next() {
+		var o domain.MessageOptOut
+		if err := rows.Scan(&o.Phone, &o.Reason, &o.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, o)
+	}
+	return out, nil
+}
+
+var _ interface {
+	Create(domain.OutboundMessage) (int64, error)
+	Finish(int64, string, string, string, time.Time) error
+	GetByProviderID(string) (*domain.OutboundMessage, error)
+	UpdateStatus(int64, string, string, time.Time) error
+	ListRecent(int) ([]domain.OutboundMessage, error)
+	IsOptedOut(string) (bool, error)
+	OptOut(domain.MessageOptOut) error
+	OptIn(string) error
+	ListOptOuts() ([]domain.MessageOptOut, error)
+} = (*MessageRepo)(nil)
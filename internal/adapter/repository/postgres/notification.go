@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"be-golang/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+type NotificationPreferenceRepo struct{ db *sql.DB }
+
+func (c *Connection) NotificationPreferences() *NotificationPreferenceRepo {
+	return &NotificationPreferenceRepo{db: c.DB}
+}
+
+const preferenceColumns = `scope, subject, channels, event_types, quiet_start, quiet_end, updated_at`
+
+func scanPreference(row rowScanner) (domain.NotificationPreference, error) {
+	var p domain.NotificationPreference
+	err := row.Scan(&p.Scope, &p.Subject, pq.Array(&p.Channels), pq.Array(&p.EventTypes), &p.QuietStart, &p.QuietEnd, &p.UpdatedAt)
+	return p, err
+}
+
+func (r *NotificationPreferenceRepo) Get(scope, subject string) (*domain.NotificationPreference, error) {
+	p, err := scanPreference(r.db.QueryRow(`SELECT `+preferenceColumns+` FROM notification_preferences WHERE scope=$1 AND subject=$2`, scope, subject))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *NotificationPreferenceRepo) List(scope string) ([]domain.NotificationPreference, error) {
+	rows, err := r.db.Query(`SELECT `+preferenceColumns+` FROM notification_preferences WHERE scope=$1 ORDER BY subject`, scope)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.NotificationPreference
+	for rows.Next() {
+		p, err := scanPreference(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+func (r *NotificationPreferenceRepo) Save(p domain.NotificationPreference) error {
+	_, err := r.db.Exec(
+		`INSERT INTO notification_preferences (scope, subject, channels, event_types, quiet_start, quiet_end, updated_at)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7)
+		 ON CONFLICT (scope, subject) DO UPDATE SET channels=EXCLUDED.channels, event_types=EXCLUDED.event_types,
+		   quiet_start=EXCLUDED.quiet_start, quiet_end=EXCLUDED.quiet_end, updated_at=EXCLUDED.updated_at`,
+		p.Scope, p.Subject, pq.Array(p.Channels), pq.Array(p.EventTypes), p.QuietStart, p.QuietEnd, p.UpdatedAt,
+	)
+	return err
+}
+
+func (r *NotificationPreferenceRepo) Delete(scope, subject string) error {
+	res, err := r.db.Exec(`DELETE FROM notification_preferences WHERE scope=$1 AND subject=$2`, scope, subject)
+	if err != nil {
+		return err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+type DeferredNotificationRepo struct{ db *sql.DB }
+
+func (c *Connection) DeferredNotifications() *DeferredNotificationRepo {
+	return &DeferredNotificationRepo{db: c.DB}
+}
+
+func (r *DeferredNotificationRepo) Defer(d domain.DeferredNotification) error {
+	event, err := json.Marshal(d.Event)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(
+		`INSERT INTO deferred_notifications (channel, event, deliver_at, status, created_at) VALUES ($1,$2,$3,$4,$5)`,
+		d.Channel, event, d.DeliverAt, domain.DeferredPending, d.CreatedAt,
+	)
+	return err
+}
+
+func (r *DeferredNotificationRepo) Due(now time.Time, limit int) ([]domain.DeferredNotification, error) {
+	rows, err := r.db.Query(
+		`SELECT id, channel, event, deliver_at, status, error, created_at FROM deferred_notifications
+		 WHERE status=$1 AND deliver_at <= $2 ORDER BY deliver_at, id LIMIT $3`,
+		domain.DeferredPending, now, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.DeferredNotification
+	for rows.Next() {
+		var d domain.DeferredNotification
+		var event []byte
+		if err := rows.Scan(&d.ID, &d.Channel, &event, &d.DeliverAt, &d.Status, &d.Error, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		if d.Event, err = domain.UnmarshalEvent(event); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+func (r *DeferredNotificationRepo) Finish(id int64, status, errText string, at time.Time) error {
+	_, err := r.db.Exec(`UPDATE deferred_notifications SET status=$2, error=$3, sent_at=$4 WHERE id=$1`, id, status, errText, at)
+	return err
+}
+
+var _ interface {
+	Get(string, string) (*domain.NotificationPreference, error)
+	List(string) ([]domain.NotificationPreference, error)
+	Save(domain.NotificationPreference) error
+	Delete(string, string) error
+} = (*NotificationPreferenceRepo)(nil)
+
+var _ interface {
+	Defer(domain.DeferredNotification) error
+	Due(time.Time, int) ([]domain.DeferredNotification, error)
+	Finish(int64, string, string, time.Time) error
+} = (*DeferredNotificationRepo)(nil)
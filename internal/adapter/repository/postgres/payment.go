@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"be-golang/internal/domain"
+)
+
+type PaymentRepo struct{ db *sql.DB }
+
+func (c *Connection) Payments() *PaymentRepo { return &PaymentRepo{db: c.DB} }
+
+const paymentColumns = `id, booking_id, provider, method, bank, amount, status, external_id, qr_string, va_number, payment_url,
+	expires_at, paid_at, kept_amount, refunded_amount, refunded_at, created_at`
+
+func scanPayment(row rowScanner) (domain.Payment, error) {
+	var p domain.Payment
+	var paidAt, refundedAt sql.NullTime
+	err := row.Scan(&p.ID, &p.BookingID, &p.Provider, &p.Method, &p.Bank, &p.Amount, &p.Status, &p.ExternalID, &p.QRString, &p.VANumber, &p.PaymentURL,
+		&p.ExpiresAt, &paidAt, &p.Kept, &p.RefundedAmount, &refundedAt, &p.CreatedAt)
+	if paidAt.Valid {
+		p.PaidAt = &paidAt.Time
+	}
+	if refundedAt.Valid {
+		p.RefundedAt = &refundedAt.Time
+	}
+	return p, err
+}
+
+func (r *PaymentRepo) Create(p domain.Payment) (int64, error) {
+	err := r.db.QueryRow(
+		`INSERT INTO payments (booking_id, provider, method, bank, amount, status, expires_at, created_at)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING id`,
+		p.BookingID, p.Provider, p.Method, p.Bank, p.Amount, p.Status, p.ExpiresAt, p.CreatedAt,
+	).Scan(&p.ID)
+	if err != nil {
+		return 0, dbError(err)
+	}
+	return p.ID, nil
+}
+
+func (r *PaymentRepo) SetIntent(id int64, in domain.PaymentIntent) error {
+	return affectedOne(r.db.Exec(
+		`UPDATE payments SET external_id=$2, qr_string=$3, va_number=$4, payment_url=$5 WHERE id=$1`,
+		id, in.ExternalID, in.QRString, in.VANumber, in.PaymentURL,
+	))
+}
+
+func (r *PaymentRepo) GetByID(id int64) (*domain.Payment, error) {
+	p, err := scanPayment(r.db.QueryRow(`SELECT `+paymentColumns+` FROM payments WHERE id=$1`, id))
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return &p, nil
+}
+
+func (r *PaymentRepo) GetByExternalID(provider, externalID string) (*domain.Payment, error) {
+	p, err := scanPayment(r.db.QueryRow(
+		`SELECT `+paymentColumns+` FROM payments WHERE provider=$1 AND external_id=$2 AND external_id <> ''`,
+		provider, externalID,
+	))
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return &p, nil
+}
+
+func (r *PaymentRepo) ListByBooking(bookingID int64) ([]domain.Payment, error) {
+	return r.query(`SELECT `+paymentColumns+` FROM payments WHERE booking_id=$1 ORDER BY created_at, id`, bookingID)
+}
+
+func (r *PaymentRepo) MarkPaid(id int64, at time.Time) (bool, error) {
+	return r.update(`UPDATE payments SET status='paid', paid_at=$2 WHERE id=$1 AND status IN ('pending', 'expired')`, id, at)
+}
+
+func (r *PaymentRepo) MarkExpired(id int64) (bool, error) {
+	return r.update(`UPDATE payments SET status='expired' WHERE id=$1 AND status='pending'`, id)
+}
+
+func (r *PaymentRepo) Keep(id int64, amount int64) (bool, error) {
+	return r.update(`UPDATE payments SET kept_amount=$2 WHERE id=$1 AND status='paid'`, id, amount)
+}
+
+func (r *PaymentRepo) MarkRefunding(id int64) (bool, error) {
+	return r.update(`UPDATE payments SET status='refunding' WHERE id=$1 AND status='paid'`, id)
+}
+
+func (r *PaymentRepo) MarkRefunded(id int64, amount int64, at time.Time) (bool, error) {
+	return r.update(
+		`UPDATE payments SET status='refunded', refunded_amount=$2, refunded_at=$3 WHERE id=$1 AND status='refunding'`,
+		id, amount, at,
+	)
+}
+
+func (r *PaymentRepo) ReleaseRefund(id int64) error {
+	_, err := r.update(`UPDATE payments SET status='paid' WHERE id=$1 AND status='refunding'`, id)
+	return err
+}
+
+func (r *PaymentRepo) ListExpired(now time.Time, limit int) ([]domain.Payment, error) {
+	return r.query(
+		`SELECT `+paymentColumns+` FROM payments WHERE status='pending' AND expires_at <= $1 ORDER BY expires_at LIMIT $2`,
+		now, limit,
+	)
+}
+
+func (r *PaymentRepo) update(q string, args ...any) (bool, error) {
+	res, err := r.db.Exec(q, args...)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *PaymentRepo) query(q string, args ...any) ([]domain.Payment, error) {
+	rows, err := r.db.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.Payment
+	for rows.Next() {
+		p, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+var _ interface {
+	Create(domain.Payment) (int64, error)
+	SetIntent(int64, domain.PaymentIntent) error
+	GetByID(int64) (*domain.Payment, error)
+	GetByExternalID(string, string) (*domain.Payment, error)
+	ListByBooking(int64) ([]domain.Payment, error)
+	MarkPaid(int64, time.Time) (bool, error)
+	MarkExpired(int64) (bool, error)
+	Keep(int64, int64) (bool, error)
+	MarkRefunding(int64) (bool, error)
+	MarkRefunded(int64, int64, time.Time) (bool, error)
+	ReleaseRefund(int64) error
+	ListExpired(time.Time, int) ([]domain.Payment, error)
+} = (*PaymentRepo)(nil)
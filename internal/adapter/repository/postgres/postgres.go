@@ -2,11 +2,12 @@ package postgres
 
 import (
 	"database/sql"
+	"errors"
 	"time"
 
 	"be-golang/internal/domain"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 type Connection struct {
@@ -21,6 +22,37 @@ func New(dsn string) (*Connection, error) {
 	return &Connection{DB: db}, nil
 }
 
+// dbError translates driver errors callers can act on into domain errors:
+// a missing row, a unique key clash and a row still referenced by another
+// table. Anything else is returned unchanged and treated as internal.
+func dbError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "23505":
+			return domain.ErrAlreadyExists.Wrap(err)
+		case "23503":
+			return domain.ErrInUse.Wrap(err)
+		}
+	}
+	return err
+}
+
+// affectedOne returns domain.ErrNotFound when an UPDATE or DELETE matched no
+// rows.
+func affectedOne(res sql.Result, err error) error {
+	if err != nil {
+		return dbError(err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
 type UserRepo struct{ db *sql.DB }
 type BookingRepo struct{ db *sql.DB }
 type ServiceRepo struct{ db *sql.DB }
@@ -34,7 +66,7 @@ func (r *UserRepo) GetByEmail(email string) (*domain.User, error) {
 	var u domain.User
 	err := row.Scan(&u.ID, &u.Email, &u.PasswordHash, &u.CreatedAt)
 	if err != nil {
-		return nil, err
+		return nil, dbError(err)
 	}
 	return &u, nil
 }
@@ -42,26 +74,259 @@ func (r *UserRepo) GetByEmail(email string) (*domain.User, error) {
 func (r *UserRepo) Create(u domain.User) (int64, error) {
 	err := r.db.QueryRow(`INSERT INTO users (email, password_hash, created_at) VALUES ($1,$2,$3) RETURNING id`, u.Email, u.PasswordHash, u.CreatedAt).Scan(&u.ID)
 	if err != nil {
-		return 0, err
+		return 0, dbError(err)
 	}
 	return u.ID, nil
 }
-func (r *BookingRepo) Create(b domain.Booking) (int64, error) {
-	err := r.db.QueryRow(
-		`INSERT INTO bookings (customer_name, customer_phone, service_id, booking_date, booking_time, status, created_at)
-		 VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING id`,
-		b.CustomerName, b.CustomerPhone, b.ServiceID, b.BookingDate, b.BookingTime, b.Status, b.CreatedAt,
+
+const bookingColumns = `id, customer_name, customer_phone, service_id, series_id, start_at, end_at, status, created_at, service_name, service_price, total_price, promotion_id, promo_code, discount, revision`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanBooking(row rowScanner) (domain.Booking, error) {
+	var b domain.Booking
+	var seriesID, promotionID sql.NullInt64
+	err := row.Scan(&b.ID, &b.CustomerName, &b.CustomerPhone, &b.ServiceID, &seriesID, &b.StartAt, &b.EndAt, &b.Status, &b.CreatedAt,
+		&b.ServiceName, &b.ServicePrice, &b.TotalPrice, &promotionID, &b.PromoCode, &b.Discount, &b.Revision)
+	if seriesID.Valid {
+		b.SeriesID = &seriesID.Int64
+	}
+	if promotionID.Valid {
+		b.PromotionID = &promotionID.Int64
+	}
+	return b, err
+}
+
+type queryRower interface {
+	QueryRow(query string, args ...any) *sql.Row
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// insertBooking copies the service name into the booking in the same
+// statement, so a missing service surfaces as domain.ErrInvalidService. A
+// booking priced by domain.Quote brings its own price and line items;
+// otherwise the service's base price is used. A booking with a promotion
+// claims a use of it first; q must be a transaction for that lock to hold.
+func insertBooking(q queryRower, b domain.Booking) (int64, error) {
+	phone := domain.NormalizePhone(b.CustomerPhone)
+	if b.PromotionID != nil {
+		if err := claimPromotion(q, *b.PromotionID, phone); err != nil {
+			return 0, err
+		}
+	}
+	var price, total sql.NullInt64
+	if len(b.Items) > 0 {
+		price = sql.NullInt64{Int64: b.ServicePrice, Valid: true}
+		total = sql.NullInt64{Int64: b.TotalPrice, Valid: true}
+	}
+	err := q.QueryRow(
+		`INSERT INTO bookings (customer_name, customer_phone, service_id, series_id, start_at, end_at, status, created_at,
+		   service_name, service_price, total_price, promotion_id, promo_code, discount)
+		 SELECT $1,$2,$3,$4,$5,$6,$7,$8, s.name, COALESCE($9, s.price), COALESCE($10, s.price), $11, $12, $13
+		 FROM services s WHERE s.id=$3
+		 RETURNING id`,
+		b.CustomerName, b.CustomerPhone, b.ServiceID, b.SeriesID, b.StartAt, b.EndAt, b.Status, b.CreatedAt, price, total,
+		b.PromotionID, b.PromoCode, b.Discount,
 	).Scan(&b.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, domain.ErrInvalidService
+	}
+	if err != nil {
+		return 0, err
+	}
+	if b.PromotionID != nil {
+		_, err = q.Exec(
+			`INSERT INTO promotion_redemptions (promotion_id, booking_id, code, customer_phone, discount, redeemed_at)
+			 VALUES ($1,$2,$3,$4,$5,$6)`,
+			*b.PromotionID, b.ID, b.PromoCode, phone, b.Discount, b.CreatedAt,
+		)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if len(b.Items) == 0 {
+		return b.ID, nil
+	}
+	var (
+		kinds, names        []string
+		refs, prices        []int64
+		quantities, minutes []int64
+	)
+	for _, it := range b.Items {
+		kinds = append(kinds, it.Kind)
+		refs = append(refs, it.RefID)
+		names = append(names, it.Name)
+		quantities = append(quantities, int64(it.Quantity))
+		prices = append(prices, it.UnitPrice)
+		minutes = append(minutes, int64(it.DurationMinutes))
+	}
+	_, err = q.Exec(
+		`INSERT INTO booking_items (booking_id, kind, ref_id, name, quantity, unit_price, duration_minutes)
+		 SELECT $1, * FROM unnest($2::text[], $3::bigint[], $4::text[], $5::int[], $6::bigint[], $7::int[])`,
+		b.ID, pq.Array(kinds), pq.Array(refs), pq.Array(names), pq.Array(quantities), pq.Array(prices), pq.Array(minutes),
+	)
 	if err != nil {
 		return 0, err
 	}
 	return b.ID, nil
 }
 
-func (r *BookingRepo) ListLatest(limit int) ([]domain.Booking, error) {
+func (r *BookingRepo) Create(b domain.Booking) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	id, err := insertBooking(tx, b)
+	if err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+// CreateInSlot inserts the booking only if fewer than capacity active
+// bookings overlap it. A capacity of zero disables the check.
+func (r *BookingRepo) CreateInSlot(b domain.Booking, capacity int) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	id, err := insertInSlot(tx, b, capacity)
+	if err != nil {
+		return 0, err
+	}
+	return id, tx.Commit()
+}
+
+// insertInSlot is CreateInSlot inside a transaction the caller owns.
+func insertInSlot(tx *sql.Tx, b domain.Booking, capacity int) (int64, error) {
+	_, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('booking_slots'))`)
+	if err != nil {
+		return 0, err
+	}
+	if capacity > 0 {
+		var taken int
+		err = tx.QueryRow(
+			`SELECT COUNT(*) FROM bookings WHERE start_at < $2 AND end_at > $1 AND status <> 'cancelled'`,
+			b.StartAt, b.EndAt,
+		).Scan(&taken)
+		if err != nil {
+			return 0, err
+		}
+		if taken >= capacity {
+			return 0, domain.ErrSlotFull
+		}
+	}
+	return insertBooking(tx, b)
+}
+
+// UpdateStatus only applies while the booking is still in status from, so
+// two concurrent changes cannot both win.
+func (r *BookingRepo) UpdateStatus(id int64, from, to string) error {
+	res, err := r.db.Exec(`UPDATE bookings SET status=$1, revision=revision+1 WHERE id=$2 AND status=$3`, to, id, from)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrInvalidTransition
+	}
+	return nil
+}
+
+func (r *BookingRepo) CountOverlapping(start, end time.Time, excludeID int64) (int, error) {
+	var c int
+	err := r.db.QueryRow(
+		`SELECT COUNT(*) FROM bookings
+		 WHERE start_at < $2 AND end_at > $1 AND status <> 'cancelled' AND id <> $3`,
+		start, end, excludeID,
+	).Scan(&c)
+	return c, err
+}
+
+// CreateSeries stores the series and all of its occurrences in one
+// transaction, re-checking capacity for each occurrence under the slot lock.
+func (r *BookingRepo) CreateSeries(s domain.BookingSeries, items []domain.Booking, capacity int) (int64, []int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('booking_slots'))`)
+	if err != nil {
+		return 0, nil, err
+	}
+	err = tx.QueryRow(
+		`INSERT INTO booking_series (customer_name, customer_phone, service_id, rule, created_at)
+		 VALUES ($1,$2,$3,$4,$5) RETURNING id`,
+		s.CustomerName, s.CustomerPhone, s.ServiceID, s.Rule, s.CreatedAt,
+	).Scan(&s.ID)
+	if err != nil {
+		return 0, nil, err
+	}
+	ids := make([]int64, 0, len(items))
+	for _, b := range items {
+		if capacity > 0 {
+			var taken int
+			err = tx.QueryRow(
+				`SELECT COUNT(*) FROM bookings WHERE start_at < $2 AND end_at > $1 AND status <> 'cancelled'`,
+				b.StartAt, b.EndAt,
+			).Scan(&taken)
+			if err != nil {
+				return 0, nil, err
+			}
+			if taken >= capacity {
+				return 0, nil, domain.ErrSlotFull
+			}
+		}
+		b.SeriesID = &s.ID
+		id, err := insertBooking(tx, b)
+		if err != nil {
+			return 0, nil, err
+		}
+		ids = append(ids, id)
+	}
+	return s.ID, ids, tx.Commit()
+}
+
+func (r *BookingRepo) GetSeries(id int64) (*domain.BookingSeries, error) {
+	var s domain.BookingSeries
+	err := r.db.QueryRow(
+		`SELECT id, customer_name, customer_phone, service_id, rule, created_at FROM booking_series WHERE id=$1`, id,
+	).Scan(&s.ID, &s.CustomerName, &s.CustomerPhone, &s.ServiceID, &s.Rule, &s.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// withExtra scans the booking columns followed by extra selected columns.
+type withExtra struct {
+	rowScanner
+	extra []any
+}
+
+func (s withExtra) Scan(dest ...any) error {
+	return s.rowScanner.Scan(append(dest, s.extra...)...)
+}
+
+// ListCalendar returns bookings overlapping [from, to) with their service
+// name. A serviceID of 0 selects all services.
+func (r *BookingRepo) ListCalendar(from, to time.Time, serviceID int64) ([]domain.Booking, error) {
 	rows, err := r.db.Query(
-		`SELECT id, customer_name, customer_phone, service_id, booking_date, booking_time, status, created_at
-		 FROM bookings ORDER BY created_at DESC LIMIT $1`, limit,
+		`SELECT `+bookingColumns+` FROM bookings
+		 WHERE start_at < $2 AND end_at > $1 AND ($3 = 0 OR service_id = $3)
+		 ORDER BY start_at`,
+		from, to, serviceID,
 	)
 	if err != nil {
 		return nil, err
@@ -69,8 +334,24 @@ func (r *BookingRepo) ListLatest(limit int) ([]domain.Booking, error) {
 	defer rows.Close()
 	var out []domain.Booking
 	for rows.Next() {
-		var b domain.Booking
-		err = rows.Scan(&b.ID, &b.CustomerName, &b.CustomerPhone, &b.ServiceID, &b.BookingDate, &b.BookingTime, &b.Status, &b.CreatedAt)
+		b, err := scanBooking(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+func (r *BookingRepo) ListBySeries(seriesID int64) ([]domain.Booking, error) {
+	rows, err := r.db.Query(`SELECT `+bookingColumns+` FROM bookings WHERE series_id=$1 ORDER BY start_at`, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.Booking
+	for rows.Next() {
+		b, err := scanBooking(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -79,9 +360,24 @@ func (r *BookingRepo) ListLatest(limit int) ([]domain.Booking, error) {
 	return out, nil
 }
 
-func (r *BookingRepo) CountOnDate(day time.Time) (int, error) {
-	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
-	end := start.Add(24 * time.Hour)
+func (r *BookingRepo) ListLatest(limit int) ([]domain.Booking, error) {
+	rows, err := r.db.Query(`SELECT `+bookingColumns+` FROM bookings ORDER BY created_at DESC LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.Booking
+	for rows.Next() {
+		b, err := scanBooking(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+func (r *BookingRepo) CountCreatedBetween(start, end time.Time) (int, error) {
 	row := r.db.QueryRow(`SELECT COUNT(*) FROM bookings WHERE created_at >= $1 AND created_at < $2`, start, end)
 	var c int
 	err := row.Scan(&c)
@@ -91,35 +387,264 @@ func (r *BookingRepo) CountOnDate(day time.Time) (int, error) {
 	return c, nil
 }
 
-func (r *ServiceRepo) Create(s domain.Service) (int64, error) {
-	err := r.db.QueryRow(`INSERT INTO services (name, price, is_active) VALUES ($1,$2,$3) RETURNING id`, s.Name, s.Price, s.IsActive).Scan(&s.ID)
+func (r *BookingRepo) GetByID(id int64) (*domain.Booking, error) {
+	b, err := scanBooking(r.db.QueryRow(`SELECT `+bookingColumns+` FROM bookings WHERE id=$1`, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// Reschedule moves a booking to a new slot inside a single transaction. Slot
+// claims are serialized with an advisory lock so two concurrent moves cannot
+// both take the last free place.
+func (r *BookingRepo) Reschedule(id int64, start, end time.Time, capacity int, changedAt time.Time) (domain.BookingHistory, error) {
+	h := domain.BookingHistory{BookingID: id, NewStartAt: start, NewEndAt: end, ChangedAt: changedAt}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return h, err
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRow(`SELECT start_at, end_at, status FROM bookings WHERE id=$1 FOR UPDATE`, id).Scan(&h.PrevStartAt, &h.PrevEndAt, &status)
+	if err == sql.ErrNoRows {
+		return h, domain.ErrNotFound
+	}
+	if err != nil {
+		return h, err
+	}
+	// Checked under the row lock so a concurrent cancel cannot slip in
+	// between the caller's read and the move.
+	if err := domain.CheckReschedulable(status); err != nil {
+		return h, err
+	}
+	_, err = tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('booking_slots'))`)
+	if err != nil {
+		return h, err
+	}
+	var taken int
+	err = tx.QueryRow(
+		`SELECT COUNT(*) FROM bookings
+		 WHERE start_at < $2 AND end_at > $1 AND status <> 'cancelled' AND id <> $3`,
+		start, end, id,
+	).Scan(&taken)
+	if err != nil {
+		return h, err
+	}
+	if capacity > 0 && taken >= capacity {
+		return h, domain.ErrSlotFull
+	}
+	_, err = tx.Exec(`UPDATE bookings SET start_at=$1, end_at=$2, revision=revision+1 WHERE id=$3`, start, end, id)
+	if err != nil {
+		return h, err
+	}
+	err = tx.QueryRow(
+		`INSERT INTO booking_history (booking_id, prev_start_at, prev_end_at, new_start_at, new_end_at, changed_at)
+		 VALUES ($1,$2,$3,$4,$5,$6) RETURNING id`,
+		id, h.PrevStartAt, h.PrevEndAt, h.NewStartAt, h.NewEndAt, h.ChangedAt,
+	).Scan(&h.ID)
 	if err != nil {
+		return h, err
+	}
+	return h, tx.Commit()
+}
+
+func (r *BookingRepo) ListHistory(bookingID int64) ([]domain.BookingHistory, error) {
+	rows, err := r.db.Query(
+		`SELECT id, booking_id, prev_start_at, prev_end_at, new_start_at, new_end_at, changed_at
+		 FROM booking_history WHERE booking_id=$1 ORDER BY changed_at DESC`, bookingID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.BookingHistory
+	for rows.Next() {
+		var h domain.BookingHistory
+		err = rows.Scan(&h.ID, &h.BookingID, &h.PrevStartAt, &h.PrevEndAt, &h.NewStartAt, &h.NewEndAt, &h.ChangedAt)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, h)
+	}
+	return out, nil
+}
+
+// normalizedPhone is domain.NormalizePhone in SQL.
+const normalizedPhone = `regexp_replace(regexp_replace(customer_phone, '[^0-9]', '', 'g'), '^0', '62')`
+
+func (r *BookingRepo) CountByPhone(phone string) (int, error) {
+	var n int
+	err := r.db.QueryRow(
+		`SELECT COUNT(*) FROM bookings WHERE `+normalizedPhone+` = $1 AND status <> 'cancelled'`, phone,
+	).Scan(&n)
+	return n, err
+}
+
+func (r *BookingRepo) ListItems(bookingID int64) ([]domain.BookingLineItem, error) {
+	rows, err := r.db.Query(
+		`SELECT id, booking_id, kind, ref_id, name, quantity, unit_price, duration_minutes
+		 FROM booking_items WHERE booking_id=$1 ORDER BY id`, bookingID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.BookingLineItem
+	for rows.Next() {
+		var it domain.BookingLineItem
+		err = rows.Scan(&it.ID, &it.BookingID, &it.Kind, &it.RefID, &it.Name, &it.Quantity, &it.UnitPrice, &it.DurationMinutes)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, it)
+	}
+	return out, rows.Err()
+}
+
+// UpdateStatusCharged changes the status like UpdateStatus and records the
+// charge in the same transaction, so a booking is never cancelled without
+// its fee.
+func (r *BookingRepo) UpdateStatusCharged(id int64, from, to string, c domain.BookingCharge) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	res, err := tx.Exec(`UPDATE bookings SET status=$1, revision=revision+1 WHERE id=$2 AND status=$3`, to, id, from)
+	if err != nil {
+		return 0, err
+	}
+	if n, err := res.RowsAffected(); err != nil {
 		return 0, err
+	} else if n == 0 {
+		return 0, domain.ErrInvalidTransition
+	}
+	err = tx.QueryRow(
+		`INSERT INTO booking_charges (booking_id, kind, percent, amount, from_deposit, created_at)
+		 VALUES ($1,$2,$3,$4,$5,$6) RETURNING id`,
+		id, c.Kind, c.Percent, c.Amount, c.FromDeposit, c.CreatedAt,
+	).Scan(&c.ID)
+	if err != nil {
+		return 0, dbError(err)
+	}
+	return c.ID, tx.Commit()
+}
+
+func (r *BookingRepo) ListCharges(bookingID int64) ([]domain.BookingCharge, error) {
+	rows, err := r.db.Query(
+		`SELECT id, booking_id, kind, percent, amount, from_deposit, created_at
+		 FROM booking_charges WHERE booking_id=$1 ORDER BY id`, bookingID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.BookingCharge
+	for rows.Next() {
+		var c domain.BookingCharge
+		if err := rows.Scan(&c.ID, &c.BookingID, &c.Kind, &c.Percent, &c.Amount, &c.FromDeposit, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+const (
+	serviceColumns = `s.id, s.name, s.description, s.price, s.duration_minutes, s.is_active, s.archived_at,
+		s.category_id, COALESCE(c.name, ''), s.image_key, s.image_url, s.sort_order, s.tags`
+	serviceFrom  = ` FROM services s LEFT JOIN categories c ON c.id = s.category_id`
+	serviceOrder = ` ORDER BY c.sort_order NULLS LAST, c.name, s.sort_order, s.name, s.id`
+	// serviceFilter matches domain.ServiceFilter's CategoryID ($1) and Tag ($2).
+	serviceFilter = `($1 = 0 OR s.category_id = $1) AND ($2 = '' OR $2 = ANY(s.tags))`
+)
+
+func scanService(row rowScanner) (domain.Service, error) {
+	var s domain.Service
+	var archivedAt sql.NullTime
+	var categoryID sql.NullInt64
+	err := row.Scan(&s.ID, &s.Name, &s.Description, &s.Price, &s.DurationMinutes, &s.IsActive, &archivedAt,
+		&categoryID, &s.CategoryName, &s.ImageKey, &s.ImageURL, &s.SortOrder, pq.Array(&s.Tags))
+	if archivedAt.Valid {
+		s.ArchivedAt = &archivedAt.Time
+	}
+	if categoryID.Valid {
+		s.CategoryID = &categoryID.Int64
+	}
+	return s, err
+}
+
+func (r *ServiceRepo) Create(s domain.Service) (int64, error) {
+	err := r.db.QueryRow(
+		`INSERT INTO services (name, description, price, duration_minutes, is_active, category_id, sort_order, tags)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING id`,
+		s.Name, s.Description, s.Price, s.DurationMinutes, s.IsActive, s.CategoryID, s.SortOrder, pq.Array(s.Tags),
+	).Scan(&s.ID)
+	if err != nil {
+		return 0, dbError(err)
 	}
 	return s.ID, nil
 }
 
-func (r *ServiceRepo) Delete(id int64) error {
-	_, err := r.db.Exec(`DELETE FROM services WHERE id=$1`, id)
-	return err
+func (r *ServiceRepo) GetByID(id int64) (*domain.Service, error) {
+	s, err := scanService(r.db.QueryRow(`SELECT `+serviceColumns+serviceFrom+` WHERE s.id=$1`, id))
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return &s, nil
+}
+
+func (r *ServiceRepo) Update(s domain.Service) error {
+	return affectedOne(r.db.Exec(
+		`UPDATE services SET name=$2, description=$3, price=$4, duration_minutes=$5, is_active=$6,
+		 category_id=$7, image_key=$8, image_url=$9, sort_order=$10, tags=$11 WHERE id=$1`,
+		s.ID, s.Name, s.Description, s.Price, s.DurationMinutes, s.IsActive,
+		s.CategoryID, s.ImageKey, s.ImageURL, s.SortOrder, pq.Array(s.Tags),
+	))
+}
+
+func (r *ServiceRepo) Archive(id int64, at time.Time) error {
+	return affectedOne(r.db.Exec(`UPDATE services SET archived_at=COALESCE(archived_at, $2) WHERE id=$1`, id, at))
+}
+
+func (r *ServiceRepo) Restore(id int64) error {
+	return affectedOne(r.db.Exec(`UPDATE services SET archived_at=NULL WHERE id=$1`, id))
 }
 
-func (r *ServiceRepo) ListActive() ([]domain.Service, error) {
-	rows, err := r.db.Query(`SELECT id, name, price, is_active FROM services WHERE is_active=TRUE ORDER BY id DESC`)
+func (r *ServiceRepo) ListActive(f domain.ServiceFilter) ([]domain.Service, error) {
+	return r.query(`SELECT `+serviceColumns+serviceFrom+` WHERE s.is_active=TRUE AND s.archived_at IS NULL AND `+serviceFilter+serviceOrder,
+		f.CategoryID, f.Tag)
+}
+
+func (r *ServiceRepo) List(f domain.ServiceFilter) ([]domain.Service, error) {
+	return r.query(`SELECT `+serviceColumns+serviceFrom+` WHERE ($3 OR s.archived_at IS NULL) AND `+serviceFilter+serviceOrder,
+		f.CategoryID, f.Tag, f.IncludeArchived)
+}
+
+func (r *ServiceRepo) Reorder(ids []int64) error {
+	return reorder(r.db, "services", ids)
+}
+
+func (r *ServiceRepo) query(q string, args ...any) ([]domain.Service, error) {
+	rows, err := r.db.Query(q, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 	var out []domain.Service
 	for rows.Next() {
-		var s domain.Service
-		err = rows.Scan(&s.ID, &s.Name, &s.Price, &s.IsActive)
+		s, err := scanService(rows)
 		if err != nil {
 			return nil, err
 		}
 		out = append(out, s)
 	}
-	return out, nil
+	return out, rows.Err()
 }
 
 var _ interface {
@@ -128,11 +653,30 @@ var _ interface {
 } = (*UserRepo)(nil)
 var _ interface {
 	Create(domain.Booking) (int64, error)
+	CreateInSlot(domain.Booking, int) (int64, error)
 	ListLatest(int) ([]domain.Booking, error)
-	CountOnDate(time.Time) (int, error)
+	CountCreatedBetween(time.Time, time.Time) (int, error)
+	GetByID(int64) (*domain.Booking, error)
+	Reschedule(int64, time.Time, time.Time, int, time.Time) (domain.BookingHistory, error)
+	ListHistory(int64) ([]domain.BookingHistory, error)
+	UpdateStatus(int64, string, string) error
+	CountOverlapping(time.Time, time.Time, int64) (int, error)
+	CreateSeries(domain.BookingSeries, []domain.Booking, int) (int64, []int64, error)
+	GetSeries(int64) (*domain.BookingSeries, error)
+	ListBySeries(int64) ([]domain.Booking, error)
+	ListCalendar(time.Time, time.Time, int64) ([]domain.Booking, error)
+	ListItems(int64) ([]domain.BookingLineItem, error)
+	CountByPhone(string) (int, error)
+	UpdateStatusCharged(int64, string, string, domain.BookingCharge) (int64, error)
+	ListCharges(int64) ([]domain.BookingCharge, error)
 } = (*BookingRepo)(nil)
 var _ interface {
 	Create(domain.Service) (int64, error)
-	Delete(int64) error
-	ListActive() ([]domain.Service, error)
+	GetByID(int64) (*domain.Service, error)
+	Update(domain.Service) error
+	Archive(int64, time.Time) error
+	Restore(int64) error
+	ListActive(domain.ServiceFilter) ([]domain.Service, error)
+	List(domain.ServiceFilter) ([]domain.Service, error)
+	Reorder([]int64) error
 } = (*ServiceRepo)(nil)
@@ -0,0 +1,195 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"be-golang/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+type PromotionRepo struct{ db *sql.DB }
+
+func (c *Connection) Promotions() *PromotionRepo { return &PromotionRepo{db: c.DB} }
+
+const promotionColumns = `id, code, description, discount_type, value, max_discount, min_spend, starts_at, ends_at,
+	usage_limit, per_customer_limit, first_visit_only, service_ids, is_active, created_at`
+
+func scanPromotion(row rowScanner) (domain.Promotion, error) {
+	var p domain.Promotion
+	var startsAt, endsAt sql.NullTime
+	err := row.Scan(&p.ID, &p.Code, &p.Description, &p.DiscountType, &p.Value, &p.MaxDiscount, &p.MinSpend, &startsAt, &endsAt,
+		&p.UsageLimit, &p.PerCustomerLimit, &p.FirstVisitOnly, pq.Array(&p.ServiceIDs), &p.IsActive, &p.CreatedAt)
+	if startsAt.Valid {
+		p.StartsAt = &startsAt.Time
+	}
+	if endsAt.Valid {
+		p.EndsAt = &endsAt.Time
+	}
+	return p, err
+}
+
+func (r *PromotionRepo) Create(p domain.Promotion) (int64, error) {
+	err := r.db.QueryRow(
+		`INSERT INTO promotions (code, description, discount_type, value, max_discount, min_spend, starts_at, ends_at,
+		   usage_limit, per_customer_limit, first_visit_only, service_ids, is_active, created_at)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,COALESCE($12::int[], '{}'),$13,$14) RETURNING id`,
+		p.Code, p.Description, p.DiscountType, p.Value, p.MaxDiscount, p.MinSpend, p.StartsAt, p.EndsAt,
+		p.UsageLimit, p.PerCustomerLimit, p.FirstVisitOnly, pq.Array(p.ServiceIDs), p.IsActive, p.CreatedAt,
+	).Scan(&p.ID)
+	if err != nil {
+		return 0, dbError(err)
+	}
+	return p.ID, nil
+}
+
+func (r *PromotionRepo) GetByID(id int64) (*domain.Promotion, error) {
+	p, err := scanPromotion(r.db.QueryRow(`SELECT `+promotionColumns+` FROM promotions WHERE id=$1`, id))
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return &p, nil
+}
+
+func (r *PromotionRepo) GetByCode(code string) (*domain.Promotion, error) {
+	p, err := scanPromotion(r.db.QueryRow(`SELECT `+promotionColumns+` FROM promotions WHERE code=$1`, code))
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return &p, nil
+}
+
+func (r *PromotionRepo) Update(p domain.Promotion) error {
+	return affectedOne(r.db.Exec(
+		`UPDATE promotions SET code=$2, description=$3, discount_type=$4, value=$5, max_discount=$6, min_spend=$7,
+		   starts_at=$8, ends_at=$9, usage_limit=$10, per_customer_limit=$11, first_visit_only=$12, service_ids=COALESCE($13::int[], '{}'), is_active=$14
+		 WHERE id=$1`,
+		p.ID, p.Code, p.Description, p.DiscountType, p.Value, p.MaxDiscount, p.MinSpend,
+		p.StartsAt, p.EndsAt, p.UsageLimit, p.PerCustomerLimit, p.FirstVisitOnly, pq.Array(p.ServiceIDs), p.IsActive,
+	))
+}
+
+func (r *PromotionRepo) Delete(id int64) error {
+	return affectedOne(r.db.Exec(`DELETE FROM promotions WHERE id=$1`, id))
+}
+
+func (r *PromotionRepo) List() ([]domain.Promotion, error) {
+	rows, err := r.db.Query(`SELECT ` + promotionColumns + ` FROM promotions ORDER BY created_at DESC, id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.Promotion
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (r *PromotionRepo) Usage(promotionID int64, phone string) (domain.PromotionUsage, error) {
+	return promotionUsage(r.db, promotionID, phone)
+}
+
+func promotionUsage(q queryRower, promotionID int64, phone string) (domain.PromotionUsage, error) {
+	var u domain.PromotionUsage
+	err := q.QueryRow(
+		`SELECT COUNT(*), COUNT(*) FILTER (WHERE r.customer_phone = $2)
+		 FROM promotion_redemptions r JOIN bookings b ON b.id = r.booking_id
+		 WHERE r.promotion_id = $1 AND b.status <> 'cancelled'`,
+		promotionID, phone,
+	).Scan(&u.Total, &u.Customer)
+	return u, err
+}
+
+// claimPromotion locks the promotion row until the transaction ends and
+// checks its usage limits, so concurrent bookings cannot both take the last
+// use.
+func claimPromotion(q queryRower, promotionID int64, phone string) error {
+	var limit, perCustomer int
+	err := q.QueryRow(
+		`SELECT usage_limit, per_customer_limit FROM promotions WHERE id=$1 AND is_active FOR UPDATE`, promotionID,
+	).Scan(&limit, &perCustomer)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrPromoUnavailable
+	}
+	if err != nil {
+		return err
+	}
+	u, err := promotionUsage(q, promotionID, phone)
+	if err != nil {
+		return err
+	}
+	if (limit > 0 && u.Total >= limit) || (perCustomer > 0 && u.Customer >= perCustomer) {
+		return domain.ErrPromoUnavailable
+	}
+	return nil
+}
+
+func (r *PromotionRepo) ListRedemptions(promotionID int64) ([]domain.PromotionRedemption, error) {
+	rows, err := r.db.Query(
+		`SELECT r.id, r.promotion_id, r.booking_id, r.code, r.customer_phone, r.discount, r.redeemed_at, b.status, b.total_price
+		 FROM promotion_redemptions r JOIN bookings b ON b.id = r.booking_id
+		 WHERE r.promotion_id = $1 ORDER BY r.redeemed_at DESC, r.id DESC`, promotionID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.PromotionRedemption
+	for rows.Next() {
+		var d domain.PromotionRedemption
+		err := rows.Scan(&d.ID, &d.PromotionID, &d.BookingID, &d.Code, &d.CustomerPhone, &d.Discount, &d.RedeemedAt, &d.BookingStatus, &d.BookingTotal)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func (r *PromotionRepo) Report(from, to time.Time) ([]domain.PromotionReport, error) {
+	rows, err := r.db.Query(
+		`SELECT p.id, p.code,
+		   COUNT(*) FILTER (WHERE b.status <> 'cancelled'),
+		   COUNT(*) FILTER (WHERE b.status = 'cancelled'),
+		   COALESCE(SUM(r.discount) FILTER (WHERE b.status <> 'cancelled'), 0),
+		   COALESCE(SUM(b.total_price) FILTER (WHERE b.status <> 'cancelled'), 0)
+		 FROM promotion_redemptions r
+		 JOIN bookings b ON b.id = r.booking_id
+		 JOIN promotions p ON p.id = r.promotion_id
+		 WHERE r.redeemed_at >= $1 AND r.redeemed_at < $2
+		 GROUP BY p.id, p.code ORDER BY p.code`,
+		from, to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.PromotionReport
+	for rows.Next() {
+		var p domain.PromotionReport
+		if err := rows.Scan(&p.PromotionID, &p.Code, &p.Redemptions, &p.Cancelled, &p.TotalDiscount, &p.Revenue); err != nil {
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+var _ interface {
+	Create(domain.Promotion) (int64, error)
+	GetByID(int64) (*domain.Promotion, error)
+	GetByCode(string) (*domain.Promotion, error)
+	Update(domain.Promotion) error
+	Delete(int64) error
+	List() ([]domain.Promotion, error)
+	Usage(int64, string) (domain.PromotionUsage, error)
+	ListRedemptions(int64) ([]domain.PromotionRedemption, error)
+	Report(time.Time, time.Time) ([]domain.PromotionReport, error)
+} = (*PromotionRepo)(nil)
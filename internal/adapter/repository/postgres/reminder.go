@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"be-golang/internal/domain"
+)
+
+type ReminderRepo struct{ db *sql.DB }
+
+func (c *Connection) Reminders() *ReminderRepo { return &ReminderRepo{db: c.DB} }
+
+// Enqueue persists a job for every active booking whose reminder at offset
+// falls in [from, to). The unique key on (booking_id, offset, start_at) keeps
+// this idempotent, and a rescheduled booking gets fresh jobs for its new time.
+func (r *ReminderRepo) Enqueue(offset time.Duration, from, to time.Time) (int, error) {
+	minutes := int(offset / time.Minute)
+	res, err := r.db.Exec(
+		`INSERT INTO reminder_jobs (booking_id, offset_minutes, start_at, run_at, status, created_at)
+		 SELECT id, $1, start_at, start_at - make_interval(mins => $1), 'pending', NOW()
+		 FROM bookings
+		 WHERE status IN ('pending', 'confirmed')
+		   AND start_at - make_interval(mins => $1) >= $2
+		   AND start_at - make_interval(mins => $1) < $3
+		 ON CONFLICT (booking_id, offset_minutes, start_at) DO NOTHING`,
+		minutes, from, to,
+	)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+func (r *ReminderRepo) Due(now time.Time, limit int) ([]domain.ReminderJob, error) {
+	rows, err := r.db.Query(
+		`SELECT id, booking_id, offset_minutes, start_at, run_at, status, sent_at, created_at
+		 FROM reminder_jobs WHERE status='pending' AND run_at <= $1 ORDER BY run_at LIMIT $2`,
+		now, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.ReminderJob
+	for rows.Next() {
+		var j domain.ReminderJob
+		var minutes int
+		var sentAt sql.NullTime
+		err = rows.Scan(&j.ID, &j.BookingID, &minutes, &j.StartAt, &j.RunAt, &j.Status, &sentAt, &j.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		j.Offset = time.Duration(minutes) * time.Minute
+		if sentAt.Valid {
+			j.SentAt = &sentAt.Time
+		}
+		out = append(out, j)
+	}
+	return out, nil
+}
+
+// Claim moves a pending job to sending as of now. Only one caller can win the
+// claim, so a job is never handed out twice.
+func (r *ReminderRepo) Claim(id int64, now time.Time) (bool, error) {
+	res, err := r.db.Exec(`UPDATE reminder_jobs SET status='sending', claimed_at=$2 WHERE id=$1 AND status='pending'`, id, now)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (r *ReminderRepo) Finish(id int64, status string, at time.Time) error {
+	_, err := r.db.Exec(`UPDATE reminder_jobs SET status=$1, sent_at=$2 WHERE id=$3`, status, at, id)
+	return err
+}
+
+// Abandon moves jobs claimed before staleBefore and never finished to
+// unknown, returning their booking IDs. A claim without claimed_at predates
+// the column and counts as stale.
+func (r *ReminderRepo) Abandon(staleBefore time.Time) ([]int64, error) {
+	rows, err := r.db.Query(
+		`UPDATE reminder_jobs SET status='unknown'
+		 WHERE status='sending' AND (claimed_at IS NULL OR claimed_at < $1)
+		 RETURNING booking_id`,
+		staleBefore,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+var _ interface {
+	Enqueue(time.Duration, time.Time, time.Time) (int, error)
+	Due(time.Time, int) ([]domain.ReminderJob, error)
+	Claim(int64, time.Time) (bool, error)
+	Finish(int64, string, time.Time) error
+	Abandon(time.Time) ([]int64, error)
+} = (*ReminderRepo)(nil)
+
+// AdvisoryLock is a session-level pg_try_advisory_lock held on a dedicated
+// connection. The instance holding it is the leader; if the connection drops
+// Postgres releases the lock and another instance can take over.
+type AdvisoryLock struct {
+	mu   sync.Mutex
+	db   *sql.DB
+	key  int64
+	conn *sql.Conn
+}
+
+func (c *Connection) AdvisoryLock(key int64) *AdvisoryLock {
+	return &AdvisoryLock{db: c.DB, key: key}
+}
+
+func (l *AdvisoryLock) IsLeader() (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ctx := context.Background()
+	if l.conn != nil {
+		if err := l.conn.PingContext(ctx); err == nil {
+			return true, nil
+		}
+		_ = l.conn.Close()
+		l.conn = nil
+	}
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	var ok bool
+	err = conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, l.key).Scan(&ok)
+	if err != nil || !ok {
+		_ = conn.Close()
+		return false, err
+	}
+	l.conn = conn
+	return true, nil
+}
+
+var _ interface {
+	IsLeader() (bool, error)
+} = (*AdvisoryLock)(nil)
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"be-golang/internal/domain"
+)
+
+type ScheduleRepo struct{ db *sql.DB }
+
+func (c *Connection) Schedule() *ScheduleRepo { return &ScheduleRepo{db: c.DB} }
+
+func (r *ScheduleRepo) ListWeekly() ([]domain.OpeningInterval, error) {
+	rows, err := r.db.Query(`SELECT id, weekday, opens, closes FROM business_hours ORDER BY weekday, opens`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.OpeningInterval
+	for rows.Next() {
+		var i domain.OpeningInterval
+		err = rows.Scan(&i.ID, &i.Weekday, &i.Opens, &i.Closes)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, i)
+	}
+	return out, nil
+}
+
+func (r *ScheduleRepo) CreateWeekly(i domain.OpeningInterval) (int64, error) {
+	err := r.db.QueryRow(`INSERT INTO business_hours (weekday, opens, closes) VALUES ($1,$2,$3) RETURNING id`, i.Weekday, i.Opens, i.Closes).Scan(&i.ID)
+	if err != nil {
+		return 0, err
+	}
+	return i.ID, nil
+}
+
+func (r *ScheduleRepo) DeleteWeekly(id int64) error {
+	return affectedOne(r.db.Exec(`DELETE FROM business_hours WHERE id=$1`, id))
+}
+
+func (r *ScheduleRepo) ListOverrides(from, to time.Time) ([]domain.ScheduleOverride, error) {
+	rows, err := r.db.Query(
+		`SELECT id, date, opens, closes FROM schedule_overrides
+		 WHERE date >= $1 AND date <= $2 ORDER BY date, opens`, from, to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.ScheduleOverride
+	for rows.Next() {
+		var o domain.ScheduleOverride
+		err = rows.Scan(&o.ID, &o.Date, &o.Opens, &o.Closes)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, o)
+	}
+	return out, nil
+}
+
+func (r *ScheduleRepo) CreateOverride(o domain.ScheduleOverride) (int64, error) {
+	err := r.db.QueryRow(`INSERT INTO schedule_overrides (date, opens, closes) VALUES ($1,$2,$3) RETURNING id`, o.Date, o.Opens, o.Closes).Scan(&o.ID)
+	if err != nil {
+		return 0, err
+	}
+	return o.ID, nil
+}
+
+func (r *ScheduleRepo) DeleteOverride(id int64) error {
+	return affectedOne(r.db.Exec(`DELETE FROM schedule_overrides WHERE id=$1`, id))
+}
+
+func (r *ScheduleRepo) ListClosures(from, to time.Time) ([]domain.Closure, error) {
+	rows, err := r.db.Query(
+		`SELECT id, start_date, end_date, reason, COALESCE(external_uid, '') FROM closures
+		 WHERE end_date >= $1 AND start_date <= $2 ORDER BY start_date`, from, to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.Closure
+	for rows.Next() {
+		var c domain.Closure
+		err = rows.Scan(&c.ID, &c.StartDate, &c.EndDate, &c.Reason, &c.ExternalUID)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+func (r *ScheduleRepo) CreateClosure(c domain.Closure) (int64, error) {
+	err := r.db.QueryRow(
+		`INSERT INTO closures (start_date, end_date, reason, external_uid) VALUES ($1,$2,$3,NULLIF($4, '')) RETURNING id`,
+		c.StartDate, c.EndDate, c.Reason, c.ExternalUID,
+	).Scan(&c.ID)
+	if err != nil {
+		return 0, err
+	}
+	return c.ID, nil
+}
+
+func (r *ScheduleRepo) UpsertClosureByUID(c domain.Closure) (int64, error) {
+	err := r.db.QueryRow(
+		`INSERT INTO closures (start_date, end_date, reason, external_uid) VALUES ($1,$2,$3,$4)
+		 ON CONFLICT (external_uid) DO UPDATE SET start_date=EXCLUDED.start_date, end_date=EXCLUDED.end_date, reason=EXCLUDED.reason
+		 RETURNING id`,
+		c.StartDate, c.EndDate, c.Reason, c.ExternalUID,
+	).Scan(&c.ID)
+	if err != nil {
+		return 0, err
+	}
+	return c.ID, nil
+}
+
+func (r *ScheduleRepo) DeleteClosure(id int64) error {
+	return affectedOne(r.db.Exec(`DELETE FROM closures WHERE id=$1`, id))
+}
+
+var _ interface {
+	ListWeekly() ([]domain.OpeningInterval, error)
+	CreateWeekly(domain.OpeningInterval) (int64, error)
+	DeleteWeekly(int64) error
+	ListOverrides(time.Time, time.Time) ([]domain.ScheduleOverride, error)
+	CreateOverride(domain.ScheduleOverride) (int64, error)
+	DeleteOverride(int64) error
+	ListClosures(time.Time, time.Time) ([]domain.Closure, error)
+	CreateClosure(domain.Closure) (int64, error)
+	UpsertClosureByUID(domain.Closure) (int64, error)
+	DeleteClosure(int64) error
+} = (*ScheduleRepo)(nil)
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"be-golang/internal/domain"
+)
+
+type ServiceOptionRepo struct{ db *sql.DB }
+
+func (c *Connection) ServiceOptions() *ServiceOptionRepo { return &ServiceOptionRepo{db: c.DB} }
+
+const serviceOptionColumns = `id, service_id, kind, name, price, duration_minutes, sort_order, is_active`
+
+func scanServiceOption(row rowScanner) (domain.ServiceOption, error) {
+	var o domain.ServiceOption
+	err := row.Scan(&o.ID, &o.ServiceID, &o.Kind, &o.Name, &o.Price, &o.DurationMinutes, &o.SortOrder, &o.IsActive)
+	return o, err
+}
+
+func (r *ServiceOptionRepo) Create(o domain.ServiceOption) (int64, error) {
+	err := r.db.QueryRow(
+		`INSERT INTO service_options (service_id, kind, name, price, duration_minutes, sort_order, is_active)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING id`,
+		o.ServiceID, o.Kind, o.Name, o.Price, o.DurationMinutes, o.SortOrder, o.IsActive,
+	).Scan(&o.ID)
+	if err != nil {
+		return 0, dbError(err)
+	}
+	return o.ID, nil
+}
+
+func (r *ServiceOptionRepo) GetByID(id int64) (*domain.ServiceOption, error) {
+	o, err := scanServiceOption(r.db.QueryRow(`SELECT `+serviceOptionColumns+` FROM service_options WHERE id=$1`, id))
+	if err != nil {
+		return nil, dbError(err)
+	}
+	return &o, nil
+}
+
+func (r *ServiceOptionRepo) Update(o domain.ServiceOption) error {
+	return affectedOne(r.db.Exec(
+		`UPDATE service_options SET name=$2, price=$3, duration_minutes=$4, sort_order=$5, is_active=$6 WHERE id=$1`,
+		o.ID, o.Name, o.Price, o.DurationMinutes, o.SortOrder, o.IsActive,
+	))
+}
+
+func (r *ServiceOptionRepo) Delete(id int64) error {
+	return affectedOne(r.db.Exec(`DELETE FROM service_options WHERE id=$1`, id))
+}
+
+func (r *ServiceOptionRepo) ListByService(serviceID int64) ([]domain.ServiceOption, error) {
+	rows, err := r.db.Query(
+		`SELECT `+serviceOptionColumns+` FROM service_options WHERE service_id=$1 ORDER BY kind DESC, sort_order, name, id`,
+		serviceID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.ServiceOption
+	for rows.Next() {
+		o, err := scanServiceOption(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, o)
+	}
+	return out, rows.Err()
+}
+
+var _ interface {
+	Create(domain.ServiceOption) (int64, error)
+	GetByID(int64) (*domain.ServiceOption, error)
+	Update(domain.ServiceOption) error
+	Delete(int64) error
+	ListByService(int64) ([]domain.ServiceOption, error)
+} = (*ServiceOptionRepo)(nil)
@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"database/sql"
+	"time"
+
+	"be-golang/internal/domain"
+)
+
+type WaitlistRepo struct{ db *sql.DB }
+
+func (c *Connection) Waitlist() *WaitlistRepo { return &WaitlistRepo{db: c.DB} }
+
+const waitlistColumns = `id, customer_name, customer_phone, service_id, date, window_start, window_end, status,
+	offer_start_at, offer_end_at, offer_expires_at, COALESCE(claim_token, ''), booking_id, created_at`
+
+func scanWaitlist(row rowScanner) (domain.WaitlistEntry, error) {
+	var e domain.WaitlistEntry
+	var offerStart, offerEnd, offerExpires sql.NullTime
+	var bookingID sql.NullInt64
+	err := row.Scan(&e.ID, &e.CustomerName, &e.CustomerPhone, &e.ServiceID, &e.Date, &e.WindowStart, &e.WindowEnd, &e.Status,
+		&offerStart, &offerEnd, &offerExpires, &e.ClaimToken, &bookingID, &e.CreatedAt)
+	if offerStart.Valid {
+		e.OfferStartAt = &offerStart.Time
+	}
+	if offerEnd.Valid {
+		e.OfferEndAt = &offerEnd.Time
+	}
+	if offerExpires.Valid {
+		e.OfferExpiresAt = &offerExpires.Time
+	}
+	if bookingID.Valid {
+		e.BookingID = &bookingID.Int64
+	}
+	return e, err
+}
+
+func (r *WaitlistRepo) list(query string, args ...any) ([]domain.WaitlistEntry, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.WaitlistEntry
+	for rows.Next() {
+		e, err := scanWaitlist(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func (r *WaitlistRepo) Create(e domain.WaitlistEntry) (int64, error) {
+	err := r.db.QueryRow(
+		`INSERT INTO waitlist_entries (customer_name, customer_phone, service_id, date, window_start, window_end, status, created_at)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8) RETURNING id`,
+		e.CustomerName, e.CustomerPhone, e.ServiceID, e.Date, e.WindowStart, e.WindowEnd, e.Status, e.CreatedAt,
+	).Scan(&e.ID)
+	if err != nil {
+		return 0, err
+	}
+	return e.ID, nil
+}
+
+func (r *WaitlistRepo) GetByID(id int64) (*domain.WaitlistEntry, error) {
+	e, err := scanWaitlist(r.db.QueryRow(`SELECT `+waitlistColumns+` FROM waitlist_entries WHERE id=$1`, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *WaitlistRepo) ListByDate(date time.Time) ([]domain.WaitlistEntry, error) {
+	return r.list(`SELECT `+waitlistColumns+` FROM waitlist_entries WHERE date=$1 ORDER BY created_at, id`, date)
+}
+
+func (r *WaitlistRepo) NextWaiting(serviceID int64, date time.Time, clock string) (*domain.WaitlistEntry, error) {
+	e, err := scanWaitlist(r.db.QueryRow(
+		`SELECT `+waitlistColumns+` FROM waitlist_entries
+		 WHERE status='waiting' AND service_id=$1 AND date=$2 AND window_start <= $3 AND window_end > $3
+		 ORDER BY created_at, id LIMIT 1`,
+		serviceID, date, clock,
+	))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *WaitlistRepo) MarkOffered(id int64, start, end, expiresAt time.Time, token string) (bool, error) {
+	res, err := r.db.Exec(
+		`UPDATE waitlist_entries SET status='offered', offer_start_at=$1, offer_end_at=$2, offer_expires_at=$3, claim_token=$4
+		 WHERE id=$5 AND status='waiting'`,
+		start, end, expiresAt, token, id,
+	)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+// Claim marks the offer claimed and books its slot in one transaction. The
+// entry is only claimed while the offer with token is open at now, so a
+// token books once; otherwise the result is domain.ErrOfferUnavailable.
+func (r *WaitlistRepo) Claim(id int64, token string, b domain.Booking, capacity int, now time.Time) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	res, err := tx.Exec(
+		`UPDATE waitlist_entries SET status='claimed'
+		 WHERE id=$1 AND status='offered' AND claim_token=$2 AND offer_expires_at > $3`,
+		id, token, now,
+	)
+	if err != nil {
+		return 0, err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return 0, err
+	} else if n == 0 {
+		return 0, domain.ErrOfferUnavailable
+	}
+	bookingID, err := insertInSlot(tx, b, capacity)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(`UPDATE waitlist_entries SET booking_id=$1 WHERE id=$2`, bookingID, id); err != nil {
+		return 0, err
+	}
+	return bookingID, tx.Commit()
+}
+
+func (r *WaitlistRepo) MarkExpired(id int64) error {
+	_, err := r.db.Exec(`UPDATE waitlist_entries SET status='expired' WHERE id=$1 AND status='offered'`, id)
+	return err
+}
+
+func (r *WaitlistRepo) ListExpiredOffers(now time.Time) ([]domain.WaitlistEntry, error) {
+	return r.list(`SELECT `+waitlistColumns+` FROM waitlist_entries WHERE status='offered' AND offer_expires_at <= $1 ORDER BY offer_expires_at`, now)
+}
+
+var _ interface {
+	Create(domain.WaitlistEntry) (int64, error)
+	GetByID(int64) (*domain.WaitlistEntry, error)
+	ListByDate(time.Time) ([]domain.WaitlistEntry, error)
+	NextWaiting(int64, time.Time, string) (*domain.WaitlistEntry, error)
+	MarkOffered(int64, time.Time, time.Time, time.Time, string) (bool, error)
+	Claim(int64, string, domain.Booking, int, time.Time) (int64, error)
+	MarkExpired(int64) error
+	ListExpiredOffers(time.Time) ([]domain.WaitlistEntry, error)
+} = (*WaitlistRepo)(nil)
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"be-golang/internal/domain"
+
+	"github.com/lib/pq"
+)
+
+type WebhookRepo struct{ db *sql.DB }
+
+func (c *Connection) Webhooks() *WebhookRepo { return &WebhookRepo{db: c.DB} }
+
+const webhookColumns = `id, url, event_types, secret, previous_secret, active, headers, created_at`
+
+func scanWebhook(row rowScanner) (domain.WebhookSubscriber, error) {
+	var s domain.WebhookSubscriber
+	var headers []byte
+	err := row.Scan(&s.ID, &s.URL, pq.Array(&s.EventTypes), &s.Secret, &s.PreviousSecret, &s.Active, &headers, &s.CreatedAt)
+	if err != nil {
+		return s, err
+	}
+	if len(headers) > 0 {
+		err = json.Unmarshal(headers, &s.Headers)
+	}
+	return s, err
+}
+
+func (r *WebhookRepo) list(query string, args ...any) ([]domain.WebhookSubscriber, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.WebhookSubscriber
+	for rows.Next() {
+		s, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func (r *WebhookRepo) List() ([]domain.WebhookSubscriber, error) {
+	return r.list(`SELECT ` + webhookColumns + ` FROM webhook_subscribers ORDER BY id`)
+}
+
+func (r *WebhookRepo) ListActive() ([]domain.WebhookSubscriber, error) {
+	return r.list(`SELECT ` + webhookColumns + ` FROM webhook_subscribers WHERE active=TRUE ORDER BY id`)
+}
+
+func (r *WebhookRepo) GetByID(id int64) (*domain.WebhookSubscriber, error) {
+	s, err := scanWebhook(r.db.QueryRow(`SELECT `+webhookColumns+` FROM webhook_subscribers WHERE id=$1`, id))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *WebhookRepo) Create(s domain.WebhookSubscriber) (int64, error) {
+	headers, err := json.Marshal(s.Headers)
+	if err != nil {
+		return 0, err
+	}
+	err = r.db.QueryRow(
+		`INSERT INTO webhook_subscribers (url, event_types, secret, previous_secret, active, headers, created_at)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING id`,
+		s.URL, pq.Array(s.EventTypes), s.Secret, s.PreviousSecret, s.Active, headers, s.CreatedAt,
+	).Scan(&s.ID)
+	if err != nil {
+		return 0, err
+	}
+	return s.ID, nil
+}
+
+func (r *WebhookRepo) Update(s domain.WebhookSubscriber) error {
+	headers, err := json.Marshal(s.Headers)
+	if err != nil {
+		return err
+	}
+	res, err := r.db.Exec(
+		`UPDATE webhook_subscribers SET url=$1, event_types=$2, secret=$3, previous_secret=$4, active=$5, headers=$6 WHERE id=$7`,
+		s.URL, pq.Array(s.EventTypes), s.Secret, s.PreviousSecret, s.Active, headers, s.ID,
+	)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err == nil && n == 0 {
+		return domain.ErrNotFound
+	}
+	return err
+}
+
+func (r *WebhookRepo) Delete(id int64) error {
+	return affectedOne(r.db.Exec(`DELETE FROM webhook_subscribers WHERE id=$1`, id))
+}
+
+func (r *WebhookRepo) LogDelivery(d domain.WebhookDelivery) error {
+	_, err := r.db.Exec(
+		`INSERT INTO webhook_deliveries (subscriber_id, event_id, event_type, status_code, latency_ms, response_snippet, error, created_at)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`,
+		d.SubscriberID, d.EventID, d.EventType, d.StatusCode, d.LatencyMS, d.ResponseSnippet, d.Error, d.CreatedAt,
+	)
+	return err
+}
+
+func (r *WebhookRepo) ListDeliveries(subscriberID int64, limit int) ([]domain.WebhookDelivery, error) {
+	rows, err := r.db.Query(
+		`SELECT id, subscriber_id, event_id, event_type, status_code, latency_ms, response_snippet, error, created_at
+		 FROM webhook_deliveries WHERE subscriber_id=$1 ORDER BY created_at DESC LIMIT $2`,
+		subscriberID, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []domain.WebhookDelivery
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		err = rows.Scan(&d.ID, &d.SubscriberID, &d.EventID, &d.EventType, &d.StatusCode, &d.LatencyMS, &d.ResponseSnippet, &d.Error, &d.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+var _ interface {
+	List() ([]domain.WebhookSubscriber, error)
+	ListActive() ([]domain.WebhookSubscriber, error)
+	GetByID(int64) (*domain.WebhookSubscriber, error)
+	Create(domain.WebhookSubscriber) (int64, error)
+	Update(domain.WebhookSubscriber) error
+	Delete(int64) error
+	LogDelivery(domain.WebhookDelivery) error
+	ListDeliveries(int64, int) ([]domain.WebhookDelivery, error)
+} = (*WebhookRepo)(nil)
@@ -2,12 +2,20 @@ package app
 
 import (
 	"log"
+	"strings"
+	"time"
 
+	"be-golang/internal/adapter/document/invoicepdf"
 	adapterfiber "be-golang/internal/adapter/http/fiber"
 	"be-golang/internal/adapter/logger/turso"
-	"be-golang/internal/adapter/notification/n8n"
+	"be-golang/internal/adapter/notification/fanout"
+	"be-golang/internal/adapter/notification/router"
+	"be-golang/internal/adapter/notification/webhook"
 	"be-golang/internal/adapter/repository/postgres"
+	"be-golang/internal/adapter/storage/local"
 	"be-golang/internal/config"
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
 	"be-golang/internal/usecase"
 	"be-golang/internal/util"
 
@@ -20,21 +28,229 @@ func Run(cfg config.Config) error {
 		return err
 	}
 	logAdapter := turso.New(cfg.TursoURL, cfg.TursoToken)
-	notifier := n8n.New(cfg.N8NWebhookURL)
+	var static []domain.WebhookSubscriber
+	if cfg.N8NWebhookURL != "" {
+		s := domain.WebhookSubscriber{URL: cfg.N8NWebhookURL, Active: true}
+		if len(cfg.N8NWebhookSecrets) > 0 {
+			s.Secret = cfg.N8NWebhookSecrets[0]
+		}
+		if len(cfg.N8NWebhookSecrets) > 1 {
+			s.PreviousSecret = cfg.N8NWebhookSecrets[1]
+		}
+		static = append(static, s)
+	}
+	dispatcher := webhook.New(conn.Webhooks(), static...)
+	renderer, err := invoicepdf.New(invoicepdf.Config{
+		BusinessName: cfg.InvoiceBusinessName,
+		Address:      cfg.InvoiceBusinessAddress,
+		Phone:        cfg.InvoiceBusinessPhone,
+		TaxID:        cfg.InvoiceTaxID,
+		Language:     cfg.InvoiceLanguage,
+	})
+	if err != nil {
+		return err
+	}
+	invoicePDF := usecase.NewInvoicePDF(conn.Invoices(), conn.Bookings(), renderer, cfg.Location)
+	mailer, err := newMailer(cfg, invoicePDF.Attachments)
+	if err != nil {
+		return err
+	}
+	messenger, err := newMessenger(cfg, conn.Messages())
+	if err != nil {
+		return err
+	}
+	routes := router.New(conn.NotificationPreferences(), conn.DeferredNotifications(), cfg.Location)
+	notifier := fanout.New(
+		routes.Channel(domain.NotifyChannelWebhook, dispatcher),
+		routes.Channel(domain.NotifyChannelEmail, mailer),
+		routes.Channel(domain.NotifyChannelMessage, messenger),
+	)
+	gateway, err := newPaymentGateway(cfg)
+	if err != nil {
+		return err
+	}
 	j := util.NewJWT(cfg.JWTSecret)
+	wa := usecase.NewWaitlistAdvance(conn.Waitlist(), conn.Bookings(), notifier, logAdapter, cfg.SlotCapacity, cfg.WaitlistOfferTTL, cfg.Location)
+	var (
+		payments      ports.PaymentRepository
+		paymentStart  *usecase.PaymentStart
+		paymentExpire *usecase.PaymentExpire
+		paymentRefund *usecase.PaymentRefund
+	)
+	if gateway != nil {
+		payments = conn.Payments()
+		deposits := domain.DepositPolicy{Percent: cfg.DepositPercent}
+		paymentStart = usecase.NewPaymentStart(conn.Payments(), gateway, logAdapter, deposits, cfg.PaymentMethod, cfg.PaymentTTL)
+		paymentExpire = usecase.NewPaymentExpire(conn.Payments(), conn.Bookings(), notifier, logAdapter, wa, cfg.Location)
+		paymentRefund = usecase.NewPaymentRefund(conn.Payments(), gateway, paymentExpire, notifier, logAdapter)
+	}
 
 	auth := usecase.NewAuthLogin(conn.Users(), logAdapter, j, cfg.TokenTTL)
 	reg := usecase.NewAdminRegister(conn.Users(), logAdapter)
-	bc := usecase.NewBookingCreate(conn.Bookings(), notifier, logAdapter)
-	bl := usecase.NewBookingList(conn.Bookings())
-	ds := usecase.NewDashboardStats(conn.Bookings())
-	sc := usecase.NewServiceCreate(conn.Services())
-	sd := usecase.NewServiceDelete(conn.Services())
-	sla := usecase.NewServiceListActive(conn.Services())
+	pricing := usecase.NewPriceCalculate(conn.Services(), conn.ServiceOptions())
+	promoApply := usecase.NewPromotionApply(conn.Promotions(), conn.Bookings())
+	bc := usecase.NewBookingCreate(conn.Bookings(), pricing, promoApply, paymentStart, notifier, logAdapter, conn.Schedule(), wa, cfg.SlotCapacity, cfg.Location)
+	bl := usecase.NewBookingList(conn.Bookings(), cfg.Location)
+	bg := usecase.NewBookingGet(conn.Bookings(), cfg.Location)
+	br := usecase.NewBookingReschedule(conn.Bookings(), notifier, logAdapter, conn.Schedule(), wa, cfg.SlotCapacity, cfg.Location)
+	bh := usecase.NewBookingHistoryList(conn.Bookings(), cfg.Location)
+	cancelResolve := usecase.NewCancellationPolicyResolve(conn.CancellationPolicies())
+	charges := usecase.NewCancellationCharge(cancelResolve, paymentRefund)
+	var invoiceLinks *usecase.InvoiceLinks
+	if cfg.InvoiceLinkBaseURL != "" {
+		invoiceLinks = usecase.NewInvoiceLinks(cfg.InvoiceLinkSecret, cfg.InvoiceLinkBaseURL, cfg.InvoiceLinkTTL)
+	}
+	invoiceIssue := usecase.NewInvoiceIssue(conn.Invoices(), conn.Bookings(), payments, invoiceLinks, notifier, logAdapter, cfg.InvoicePrefix, cfg.InvoiceTaxPercent, cfg.Location)
+	bss := usecase.NewBookingSetStatus(conn.Bookings(), notifier, logAdapter, wa, charges, invoiceIssue, cfg.Location)
+	ds := usecase.NewDashboardStats(conn.Bookings(), cfg.Location)
 
-	app := fb.New()
-	handlers := adapterfiber.NewHandlers(auth, reg, bc, bl, ds, sc, sd, sla, j)
+	app := fb.New(fb.Config{ErrorHandler: adapterfiber.ErrorHandler})
+	app.Use(adapterfiber.Idempotency(conn.Idempotency(), cfg.IdempotencyTTL))
+	handlers := adapterfiber.NewHandlers(auth, reg, bc, bl, bg, br, bh, bss, ds, j)
 	handlers.Register(app)
+	blobs := local.New(cfg.UploadDir, cfg.UploadBaseURL)
+	if strings.HasPrefix(cfg.UploadBaseURL, "/") {
+		app.Static(cfg.UploadBaseURL, cfg.UploadDir, fb.Static{MaxAge: 86400})
+	}
+	serviceHandlers := adapterfiber.NewServiceHandlers(
+		usecase.NewServiceCreate(conn.Services(), conn.Categories()),
+		usecase.NewServiceUpdate(conn.Services(), conn.Categories(), logAdapter),
+		usecase.NewServiceArchive(conn.Services(), logAdapter),
+		usecase.NewServiceRestore(conn.Services(), logAdapter),
+		usecase.NewServiceListActive(conn.Services()),
+		usecase.NewServiceList(conn.Services()),
+		usecase.NewServiceReorder(conn.Services(), logAdapter),
+		usecase.NewServiceSetImage(conn.Services(), blobs, logAdapter),
+		usecase.NewServiceRemoveImage(conn.Services(), blobs, logAdapter),
+	)
+	serviceHandlers.Register(app, adapterfiber.JWTMiddleware(j))
+	serviceOptionHandlers := adapterfiber.NewServiceOptionHandlers(
+		usecase.NewServiceOptionCreate(conn.Services(), conn.ServiceOptions(), logAdapter),
+		usecase.NewServiceOptionUpdate(conn.ServiceOptions(), logAdapter),
+		usecase.NewServiceOptionDelete(conn.ServiceOptions(), logAdapter),
+		usecase.NewServiceOptionList(conn.Services(), conn.ServiceOptions()),
+		pricing,
+		cancelResolve,
+	)
+	serviceOptionHandlers.Register(app, adapterfiber.JWTMiddleware(j))
+	cancellationHandlers := adapterfiber.NewCancellationHandlers(
+		usecase.NewCancellationPolicyGet(conn.CancellationPolicies()),
+		usecase.NewCancellationPolicyList(conn.CancellationPolicies()),
+		usecase.NewCancellationPolicySave(conn.CancellationPolicies(), conn.Services(), logAdapter),
+		usecase.NewCancellationPolicyDelete(conn.CancellationPolicies(), logAdapter),
+		cancelResolve,
+	)
+	cancellationHandlers.Register(app, adapterfiber.JWTMiddleware(j))
+	invoiceHandlers := adapterfiber.NewInvoiceHandlers(
+		invoiceIssue,
+		usecase.NewInvoiceList(conn.Invoices(), cfg.Location),
+		invoicePDF,
+		invoiceLinks,
+	)
+	invoiceHandlers.Register(app, adapterfiber.JWTMiddleware(j))
+	promotionHandlers := adapterfiber.NewPromotionHandlers(
+		usecase.NewPromotionCreate(conn.Promotions(), logAdapter, cfg.Location),
+		usecase.NewPromotionUpdate(conn.Promotions(), logAdapter, cfg.Location),
+		usecase.NewPromotionDelete(conn.Promotions(), logAdapter),
+		usecase.NewPromotionList(conn.Promotions()),
+		usecase.NewPromotionRedemptionList(conn.Promotions()),
+		usecase.NewPromotionReport(conn.Promotions(), cfg.Location),
+		promoApply,
+		pricing,
+		cfg.Location,
+	)
+	promotionHandlers.Register(app, adapterfiber.JWTMiddleware(j))
+	categoryHandlers := adapterfiber.NewCategoryHandlers(
+		usecase.NewCategoryCreate(conn.Categories(), logAdapter),
+		usecase.NewCategoryUpdate(conn.Categories(), logAdapter),
+		usecase.NewCategoryDelete(conn.Categories(), logAdapter),
+		usecase.NewCategoryList(conn.Categories()),
+		usecase.NewCategoryReorder(conn.Categories(), logAdapter),
+	)
+	categoryHandlers.Register(app, adapterfiber.JWTMiddleware(j))
+	scheduleHandlers := adapterfiber.NewScheduleHandlers(
+		usecase.NewScheduleCalendar(conn.Schedule()),
+		usecase.NewScheduleWeeklyList(conn.Schedule()),
+		usecase.NewScheduleWeeklyCreate(conn.Schedule()),
+		usecase.NewScheduleWeeklyDelete(conn.Schedule()),
+		usecase.NewScheduleOverrideList(conn.Schedule()),
+		usecase.NewScheduleOverrideCreate(conn.Schedule()),
+		usecase.NewScheduleOverrideDelete(conn.Schedule()),
+		usecase.NewScheduleClosureList(conn.Schedule()),
+		usecase.NewScheduleClosureCreate(conn.Schedule()),
+		usecase.NewScheduleClosureDelete(conn.Schedule()),
+		usecase.NewScheduleHolidayImport(conn.Schedule(), logAdapter),
+		cfg.Location,
+	)
+	scheduleHandlers.Register(app, adapterfiber.JWTMiddleware(j))
+	seriesHandlers := adapterfiber.NewSeriesHandlers(
+		usecase.NewBookingSeriesCreate(conn.Bookings(), conn.Services(), conn.Schedule(), logAdapter, cfg.SlotCapacity, cfg.Location),
+		usecase.NewBookingSeriesGet(conn.Bookings(), cfg.Location),
+		usecase.NewBookingSeriesCancel(conn.Bookings(), bss),
+		usecase.NewBookingSeriesReschedule(conn.Bookings(), notifier, logAdapter, conn.Schedule(), wa, cfg.SlotCapacity, cfg.Location),
+	)
+	seriesHandlers.Register(app, adapterfiber.JWTMiddleware(j))
+	waitlistHandlers := adapterfiber.NewWaitlistHandlers(
+		usecase.NewWaitlistJoin(conn.Waitlist(), conn.Services(), logAdapter, cfg.Location),
+		usecase.NewWaitlistClaim(conn.Waitlist(), notifier, logAdapter, cfg.SlotCapacity, cfg.Location),
+		usecase.NewWaitlistList(conn.Waitlist()),
+		cfg.Location,
+	)
+	waitlistHandlers.Register(app, adapterfiber.JWTMiddleware(j))
+	webhookHandlers := adapterfiber.NewWebhookHandlers(
+		usecase.NewWebhookList(conn.Webhooks()),
+		usecase.NewWebhookCreate(conn.Webhooks(), logAdapter),
+		usecase.NewWebhookUpdate(conn.Webhooks(), logAdapter),
+		usecase.NewWebhookDelete(conn.Webhooks(), logAdapter),
+		usecase.NewWebhookDeliveries(conn.Webhooks()),
+		usecase.NewWebhookTest(conn.Webhooks(), dispatcher),
+	)
+	webhookHandlers.Register(app, adapterfiber.JWTMiddleware(j))
+	inboundHandlers := adapterfiber.NewInboundHandlers(usecase.NewInboundCommand(conn.Inbound(), conn.Bookings(), bss, conn.Messages(), logAdapter))
+	inboundHandlers.Register(app, adapterfiber.InboundAuth(cfg.InboundSecrets, cfg.InboundAPIKeys))
+	messageHandlers := adapterfiber.NewMessageHandlers(
+		usecase.NewMessageList(conn.Messages()),
+		usecase.NewMessageStatusUpdate(conn.Messages()),
+		usecase.NewMessageOptOutList(conn.Messages()),
+		usecase.NewMessageOptOutCreate(conn.Messages(), logAdapter),
+		usecase.NewMessageOptOutDelete(conn.Messages(), logAdapter),
+	)
+	messageHandlers.Register(app, adapterfiber.JWTMiddleware(j), adapterfiber.TokenAuth(cfg.MessagingCallbackTokens))
+	notificationHandlers := adapterfiber.NewNotificationHandlers(
+		usecase.NewNotificationPreferenceGet(conn.NotificationPreferences()),
+		usecase.NewNotificationPreferenceList(conn.NotificationPreferences()),
+		usecase.NewNotificationPreferenceSave(conn.NotificationPreferences(), logAdapter),
+		usecase.NewNotificationPreferenceDelete(conn.NotificationPreferences(), logAdapter),
+	)
+	notificationHandlers.Register(app, adapterfiber.JWTMiddleware(j))
+	calendarHandlers := adapterfiber.NewCalendarHandlers(
+		usecase.NewCalendarFeed(conn.CalendarTokens(), conn.Bookings(), cfg.Location),
+		usecase.NewCalendarTokenCreate(conn.CalendarTokens(), conn.Services(), logAdapter),
+		usecase.NewCalendarTokenList(conn.CalendarTokens()),
+		usecase.NewCalendarTokenRevoke(conn.CalendarTokens(), logAdapter),
+	)
+	calendarHandlers.Register(app, adapterfiber.JWTMiddleware(j))
+	if gateway != nil {
+		paymentHandlers := adapterfiber.NewPaymentHandlers(
+			usecase.NewPaymentCallback(conn.Payments(), conn.Bookings(), gateway, paymentExpire, paymentRefund, invoiceIssue, notifier, logAdapter, cfg.Location),
+			usecase.NewPaymentList(conn.Payments(), conn.Bookings(), cfg.Location),
+			paymentRefund,
+		)
+		paymentHandlers.Register(app, adapterfiber.JWTMiddleware(j))
+	}
+
+	leader := conn.AdvisoryLock(schedulerLockKey)
+	reminders := usecase.NewReminderDispatch(conn.Reminders(), conn.Bookings(), notifier, logAdapter, cfg.ReminderOffsets, cfg.Location)
+	runEvery(leader, time.Minute, "waitlist_expire", wa.ExpireOffers)
+	runEvery(leader, time.Minute, "booking_reminders", reminders.Exec)
+	runEvery(leader, time.Minute, "notification_flush", routes.Flush)
+	if paymentExpire != nil {
+		runEvery(leader, time.Minute, "payment_expire", paymentExpire.Exec)
+	}
+	runEvery(leader, time.Hour, "idempotency_cleanup", func(now time.Time) error {
+		_, err := conn.Idempotency().DeleteExpired(now)
+		return err
+	})
 	log.Println("server listening on", cfg.ServerAddr)
 	return app.Listen(cfg.ServerAddr)
 }
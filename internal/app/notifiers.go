@@ -0,0 +1,66 @@
+package app
+
+import (
+	"errors"
+	"log"
+
+	"be-golang/internal/adapter/messaging/fake"
+	"be-golang/internal/adapter/messaging/httpgateway"
+	"be-golang/internal/adapter/notification/email"
+	"be-golang/internal/adapter/notification/message"
+	"be-golang/internal/config"
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+)
+
+// newMailer returns nil when SMTP is not configured. attach supplies files
+// sent with events, such as invoice PDFs.
+func newMailer(cfg config.Config, attach func(domain.Event) ([]domain.Attachment, error)) (ports.Notifier, error) {
+	if cfg.SMTPHost == "" {
+		return nil, nil
+	}
+	return email.New(email.Config{
+		Host:        cfg.SMTPHost,
+		Port:        cfg.SMTPPort,
+		Username:    cfg.SMTPUsername,
+		Password:    cfg.SMTPPassword,
+		From:        cfg.EmailFrom,
+		To:          cfg.EmailTo,
+		Language:    cfg.EmailLanguage,
+		Location:    cfg.Location,
+		Attachments: attach,
+	})
+}
+
+// newMessenger returns nil when MESSAGING_PROVIDER is empty.
+func newMessenger(cfg config.Config, messages ports.MessageRepository) (ports.Notifier, error) {
+	var sender ports.MessageSender
+	switch cfg.MessagingProvider {
+	case "":
+		return nil, nil
+	case "fake":
+		log.Println("messaging: using in-memory fake provider, nothing is delivered")
+		sender = fake.New()
+	case "http":
+		g, err := httpgateway.New(httpgateway.Config{
+			URL:         cfg.GatewayURL,
+			Token:       cfg.GatewayToken,
+			AuthHeader:  cfg.GatewayAuthHeader,
+			Body:        cfg.GatewayBody,
+			ContentType: cfg.GatewayContentType,
+			IDField:     cfg.GatewayIDField,
+		})
+		if err != nil {
+			return nil, err
+		}
+		sender = g
+	default:
+		return nil, errors.New("unknown MESSAGING_PROVIDER " + cfg.MessagingProvider)
+	}
+	return message.New(sender, messages, message.Config{
+		Channel:     cfg.MessagingChannel,
+		Language:    cfg.MessagingLanguage,
+		TemplateDir: cfg.MessagingTemplateDir,
+		Location:    cfg.Location,
+	})
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"errors"
+	"log"
+
+	"be-golang/internal/adapter/payment/fake"
+	"be-golang/internal/adapter/payment/httpgateway"
+	"be-golang/internal/config"
+	"be-golang/internal/ports"
+)
+
+// newPaymentGateway returns nil when PAYMENT_PROVIDER is empty, which turns
+// deposits off.
+func newPaymentGateway(cfg config.Config) (ports.PaymentGateway, error) {
+	switch cfg.PaymentProvider {
+	case "":
+		return nil, nil
+	case "fake":
+		log.Println("payments: using in-memory fake gateway, no money is collected")
+		var secret string
+		if len(cfg.PaymentCallbackSecrets) > 0 {
+			secret = cfg.PaymentCallbackSecrets[0]
+		}
+		return fake.New(secret), nil
+	case "http":
+		g, err := httpgateway.New(httpgateway.Config{
+			Provider:            cfg.PaymentGatewayName,
+			URL:                 cfg.PaymentGatewayURL,
+			RefundURL:           cfg.PaymentGatewayRefundURL,
+			Token:               cfg.PaymentGatewayToken,
+			AuthHeader:          cfg.PaymentGatewayAuthHeader,
+			Body:                cfg.PaymentGatewayBody,
+			RefundBody:          cfg.PaymentGatewayRefundBody,
+			ContentType:         cfg.PaymentGatewayContentType,
+			IDField:             cfg.PaymentGatewayIDField,
+			QRField:             cfg.PaymentGatewayQRField,
+			VAField:             cfg.PaymentGatewayVAField,
+			URLField:            cfg.PaymentGatewayURLField,
+			CallbackSecrets:     cfg.PaymentCallbackSecrets,
+			SignatureHeader:     cfg.PaymentSignatureHeader,
+			CallbackIDField:     cfg.PaymentCallbackIDField,
+			CallbackStatusField: cfg.PaymentCallbackStatus,
+			CallbackAmountField: cfg.PaymentCallbackAmount,
+			PaidStatuses:        cfg.PaymentPaidStatuses,
+			ExpiredStatuses:     cfg.PaymentExpiredStatuses,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return g, nil
+	}
+	return nil, errors.New("unknown PAYMENT_PROVIDER " + cfg.PaymentProvider)
+}
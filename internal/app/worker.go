@@ -0,0 +1,35 @@
+package app
+
+import (
+	"log"
+	"time"
+
+	"be-golang/internal/ports"
+)
+
+// schedulerLockKey is the Postgres advisory lock key that elects the single
+// instance allowed to run background jobs.
+const schedulerLockKey = 0x626b6e67
+
+// runEvery calls fn on a fixed interval in the background for the lifetime
+// of the process, but only while this instance is the elected leader. Errors
+// are logged and the next tick proceeds normally.
+func runEvery(leader ports.LeaderElector, interval time.Duration, name string, fn func(now time.Time) error) {
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for now := range t.C {
+			ok, err := leader.IsLeader()
+			if err != nil {
+				log.Println(name, "leader election failed:", err)
+				continue
+			}
+			if !ok {
+				continue
+			}
+			if err := fn(now.UTC()); err != nil {
+				log.Println(name, "failed:", err)
+			}
+		}
+	}()
+}
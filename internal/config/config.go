@@ -3,12 +3,83 @@ package config
 import "time"
 
 type Config struct {
-	PostgresDSN    string
-	JWTSecret      string
-	TursoURL       string
-	TursoToken     string
-	N8NWebhookURL  string
-	ServerAddr     string
-	TokenTTL       time.Duration
-	AdminOnlyPaths []string
+	PostgresDSN       string
+	JWTSecret         string
+	TursoURL          string
+	TursoToken        string
+	N8NWebhookURL     string
+	N8NWebhookSecrets []string
+	ServerAddr        string
+	TokenTTL          time.Duration
+	SlotCapacity      int
+	WaitlistOfferTTL  time.Duration
+	ReminderOffsets   []time.Duration
+	Location          *time.Location
+	AdminOnlyPaths    []string
+	InboundSecrets    []string
+	InboundAPIKeys    []string
+	SMTPHost          string
+	SMTPPort          int
+	SMTPUsername      string
+	SMTPPassword      string
+	EmailFrom         string
+	EmailTo           []string
+	EmailLanguage     string
+
+	// SMS/WhatsApp messaging
+	MessagingProvider       string
+	MessagingChannel        string
+	MessagingLanguage       string
+	MessagingTemplateDir    string
+	GatewayURL              string
+	GatewayToken            string
+	GatewayAuthHeader       string
+	GatewayBody             string
+	GatewayContentType      string
+	GatewayIDField          string
+	MessagingCallbackTokens []string
+
+	IdempotencyTTL time.Duration
+
+	// Uploaded files (service images)
+	UploadDir     string
+	UploadBaseURL string
+
+	// Payments (booking deposits)
+	PaymentProvider           string
+	PaymentMethod             string
+	DepositPercent            int
+	PaymentTTL                time.Duration
+	PaymentGatewayName        string
+	PaymentGatewayURL         string
+	PaymentGatewayRefundURL   string
+	PaymentGatewayToken       string
+	PaymentGatewayAuthHeader  string
+	PaymentGatewayBody        string
+	PaymentGatewayRefundBody  string
+	PaymentGatewayContentType string
+	PaymentGatewayIDField     string
+	PaymentGatewayQRField     string
+	PaymentGatewayVAField     string
+	PaymentGatewayURLField    string
+	PaymentCallbackSecrets    []string
+	PaymentSignatureHeader    string
+	PaymentCallbackIDField    string
+	PaymentCallbackStatus     string
+	PaymentCallbackAmount     string
+	PaymentPaidStatuses       []string
+	PaymentExpiredStatuses    []string
+
+	// Invoices for completed bookings
+	InvoicePrefix          string
+	InvoiceTaxPercent      int
+	InvoiceLanguage        string
+	InvoiceBusinessName    string
+	InvoiceBusinessAddress string
+	InvoiceBusinessPhone   string
+	InvoiceTaxID           string
+	// Signed PDF links for customers; disabled without a base URL.
+	InvoiceLinkBaseURL string
+	InvoiceLinkSecret  string
+	InvoiceLinkTTL     time.Duration
 }
@@ -2,13 +2,103 @@ package domain
 
 import "time"
 
+const (
+	StatusPending   = "pending"
+	StatusConfirmed = "confirmed"
+	StatusCancelled = "cancelled"
+	StatusCompleted = "completed"
+	StatusNoShow    = "no_show"
+	// StatusAwaitingPayment holds the slot until the deposit is paid or
+	// its payment expires.
+	StatusAwaitingPayment = "awaiting_payment"
+)
+
+var bookingTransitions = map[string][]string{
+	StatusPending:         {StatusConfirmed, StatusCancelled},
+	StatusAwaitingPayment: {StatusConfirmed, StatusCancelled},
+	StatusConfirmed:       {StatusCompleted, StatusCancelled, StatusNoShow},
+}
+
+// CanTransition reports whether a booking may move from one status to another.
+func CanTransition(from, to string) bool {
+	for _, s := range bookingTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckReschedulable returns nil when a booking in status may be moved to
+// another time: only pending and confirmed bookings can.
+func CheckReschedulable(status string) error {
+	switch status {
+	case StatusPending, StatusConfirmed:
+		return nil
+	case StatusCancelled:
+		return ErrBookingCancelled
+	}
+	return ErrNotReschedulable
+}
+
 type Booking struct {
 	ID            int64
 	CustomerName  string
 	CustomerPhone string
 	ServiceID     int64
+	SeriesID      *int64
+	StartAt       time.Time
+	EndAt         time.Time
 	BookingDate   time.Time
 	BookingTime   string
 	Status        string
 	CreatedAt     time.Time
+	// Revision counts the changes to the booking's time or status, so
+	// calendar apps pick them up.
+	Revision int
+
+	// ServiceName and ServicePrice snapshot the service when the booking
+	// was made, so later catalog edits do not rewrite history. ServicePrice
+	// is the chosen variant's price when the service has variants.
+	ServiceName  string
+	ServicePrice int64
+	// TotalPrice is the service price plus add-ons, less any discount.
+	// Items holds the priced lines; it is only filled when read through
+	// BookingGet.
+	TotalPrice int64
+	Items      []BookingLineItem
+	// Discount is the amount the promotion code PromoCode took off;
+	// TotalPrice already excludes it.
+	PromotionID *int64
+	PromoCode   string
+	Discount    int64
+	// Charges holds the late cancellation or no-show fee, if any; it is
+	// only filled when read through BookingGet.
+	Charges []BookingCharge
+}
+
+// Localize expresses the booking times in the business location and fills
+// the derived BookingDate and BookingTime fields.
+func (b *Booking) Localize(loc *time.Location) {
+	b.StartAt = b.StartAt.In(loc)
+	b.EndAt = b.EndAt.In(loc)
+	b.BookingDate = time.Date(b.StartAt.Year(), b.StartAt.Month(), b.StartAt.Day(), 0, 0, 0, 0, loc)
+	b.BookingTime = b.StartAt.Format("15:04")
+}
+
+type BookingHistory struct {
+	ID          int64
+	BookingID   int64
+	PrevStartAt time.Time
+	PrevEndAt   time.Time
+	NewStartAt  time.Time
+	NewEndAt    time.Time
+	ChangedAt   time.Time
+}
+
+func (h *BookingHistory) Localize(loc *time.Location) {
+	h.PrevStartAt = h.PrevStartAt.In(loc)
+	h.PrevEndAt = h.PrevEndAt.In(loc)
+	h.NewStartAt = h.NewStartAt.In(loc)
+	h.NewEndAt = h.NewEndAt.In(loc)
 }
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckReschedulable(t *testing.T) {
+	tests := []struct {
+		status string
+		want   error
+	}{
+		{StatusPending, nil},
+		{StatusConfirmed, nil},
+		{StatusCancelled, ErrBookingCancelled},
+		{StatusAwaitingPayment, ErrNotReschedulable},
+		{StatusCompleted, ErrNotReschedulable},
+		{StatusNoShow, ErrNotReschedulable},
+	}
+	for _, tt := range tests {
+		if err := CheckReschedulable(tt.status); !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+			t.Errorf("CheckReschedulable(%q) = %v, want %v", tt.status, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+// CalendarToken grants read access to an iCalendar feed of bookings. Only a
+// hash of the token is stored; the token itself is shown once on creation.
+// A nil ServiceID means the feed covers every service.
+type CalendarToken struct {
+	ID         int64
+	UserID     int64
+	Name       string
+	TokenHash  string
+	ServiceID  *int64
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// CalendarUID identifies a booking in calendar feeds and email invitations
+// alike, so calendar apps treat them as the same event. Booking.Revision is
+// its SEQUENCE.
+func CalendarUID(bookingID int64) string {
+	return "booking-" + strconv.FormatInt(bookingID, 10) + "@be-golang"
+}
+
+// CalendarStatuses maps booking statuses to iCalendar VEVENT STATUS values.
+var CalendarStatuses = map[string]string{
+	StatusPending:         "TENTATIVE",
+	StatusAwaitingPayment: "TENTATIVE",
+	StatusConfirmed:       "CONFIRMED",
+	StatusCompleted:       "CONFIRMED",
+	StatusCancelled:       "CANCELLED",
+	StatusNoShow:          "CANCELLED",
+}
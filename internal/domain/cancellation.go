@@ -0,0 +1,79 @@
+package domain
+
+import "time"
+
+const (
+	ChargeLateCancellation = "late_cancellation"
+	ChargeNoShow           = "no_show"
+)
+
+// MaxFreeCancelMinutes bounds the free cancellation window to 30 days.
+const MaxFreeCancelMinutes = 30 * 24 * 60
+
+// CancellationPolicy sets what a customer owes for cancelling late or not
+// showing up, as percentages of the booking total. ServiceID is zero for
+// the business policy, which covers services without a policy of their
+// own. The zero policy charges nothing.
+type CancellationPolicy struct {
+	ServiceID int64
+	// FreeCancelMinutes is how long before the start a booking can still
+	// be cancelled without a fee.
+	FreeCancelMinutes    int
+	LateCancelFeePercent int
+	NoShowFeePercent     int
+	UpdatedAt            time.Time
+}
+
+// Fee returns the charge for a booking with the given total that enters
+// status at now: a late cancellation inside the free window or a no-show.
+// A zero amount means the change is free.
+func (p CancellationPolicy) Fee(status string, total int64, start, now time.Time) (string, int64) {
+	switch status {
+	case StatusCancelled:
+		if start.Sub(now) >= time.Duration(p.FreeCancelMinutes)*time.Minute {
+			return "", 0
+		}
+		return ChargeLateCancellation, p.LateCancelFee(total)
+	case StatusNoShow:
+		return ChargeNoShow, p.NoShowFee(total)
+	}
+	return "", 0
+}
+
+// LateCancelFee and NoShowFee return the fees for a booking total, rounded
+// up to whole rupiah.
+func (p CancellationPolicy) LateCancelFee(total int64) int64 {
+	return percentOf(total, p.LateCancelFeePercent)
+}
+
+func (p CancellationPolicy) NoShowFee(total int64) int64 {
+	return percentOf(total, p.NoShowFeePercent)
+}
+
+// BookingCharge is a fee recorded on a booking under its cancellation
+// policy. FromDeposit is the part kept from a paid deposit; the rest is
+// still owed by the customer.
+type BookingCharge struct {
+	ID          int64
+	BookingID   int64
+	Kind        string
+	Percent     int
+	Amount      int64
+	FromDeposit int64
+	CreatedAt   time.Time
+}
+
+func (c BookingCharge) Outstanding() int64 {
+	return c.Amount - c.FromDeposit
+}
+
+// percentOf returns percent of amount rounded up to whole rupiah.
+func percentOf(amount int64, percent int) int64 {
+	if amount <= 0 || percent <= 0 {
+		return 0
+	}
+	if percent >= 100 {
+		return amount
+	}
+	return (amount*int64(percent) + 99) / 100
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPercentOf(t *testing.T) {
+	tests := []struct {
+		amount  int64
+		percent int
+		want    int64
+	}{
+		{100000, 50, 50000},
+		{99999, 50, 50000},
+		{10001, 10, 1001},
+		{1, 1, 1},
+		{150000, 0, 0},
+		{0, 50, 0},
+		{-5000, 50, 0},
+		{150000, 100, 150000},
+		{150000, 120, 150000},
+	}
+	for _, tt := range tests {
+		if got := percentOf(tt.amount, tt.percent); got != tt.want {
+			t.Errorf("percentOf(%d, %d) = %d, want %d", tt.amount, tt.percent, got, tt.want)
+		}
+	}
+}
+
+func TestCancellationPolicyFee(t *testing.T) {
+	p := CancellationPolicy{FreeCancelMinutes: 24 * 60, LateCancelFeePercent: 25, NoShowFeePercent: 100}
+	start := time.Date(2026, 5, 10, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		status   string
+		now      time.Time
+		wantKind string
+		wantFee  int64
+	}{
+		{"cancelled early", StatusCancelled, start.Add(-48 * time.Hour), "", 0},
+		{"cancelled at window edge", StatusCancelled, start.Add(-24 * time.Hour), "", 0},
+		{"cancelled late", StatusCancelled, start.Add(-24*time.Hour + time.Minute), ChargeLateCancellation, 37500},
+		{"cancelled after start", StatusCancelled, start.Add(time.Hour), ChargeLateCancellation, 37500},
+		{"no-show", StatusNoShow, start.Add(2 * time.Hour), ChargeNoShow, 150000},
+		{"completed", StatusCompleted, start.Add(2 * time.Hour), "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, fee := p.Fee(tt.status, 150000, start, tt.now)
+			if kind != tt.wantKind || fee != tt.wantFee {
+				t.Errorf("Fee = %q %d, want %q %d", kind, fee, tt.wantKind, tt.wantFee)
+			}
+		})
+	}
+}
+
+func TestCancellationPolicyZeroIsFree(t *testing.T) {
+	var p CancellationPolicy
+	start := time.Date(2026, 5, 10, 10, 0, 0, 0, time.UTC)
+	for _, status := range []string{StatusCancelled, StatusNoShow} {
+		if _, fee := p.Fee(status, 150000, start, start); fee != 0 {
+			t.Errorf("zero policy charged %d for %s", fee, status)
+		}
+	}
+}
+
+func TestBookingChargeOutstanding(t *testing.T) {
+	c := BookingCharge{Amount: 75000, FromDeposit: 50000}
+	if got := c.Outstanding(); got != 25000 {
+		t.Errorf("Outstanding = %d, want 25000", got)
+	}
+}
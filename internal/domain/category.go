@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// Category groups services in the catalog. Categories are listed by
+// SortOrder, then by name.
+type Category struct {
+	ID        int64
+	Name      string
+	SortOrder int
+	CreatedAt time.Time
+}
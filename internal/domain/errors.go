@@ -0,0 +1,110 @@
+package domain
+
+import "errors"
+
+// Kind classifies a failure so adapters can map it without knowing every
+// individual code, e.g. every KindNotFound becomes HTTP 404.
+type Kind string
+
+const (
+	KindInternal     Kind = "internal"
+	KindInvalid      Kind = "invalid"
+	KindUnauthorized Kind = "unauthorized"
+	KindForbidden    Kind = "forbidden"
+	KindNotFound     Kind = "not_found"
+	KindConflict     Kind = "conflict"
+	KindValidation   Kind = "validation"
+	KindRateLimited  Kind = "rate_limited"
+)
+
+// Error is a failure the caller can act on. Code is stable and safe to show
+// to clients; Err is the underlying cause, kept for logs only.
+type Error struct {
+	Kind Kind
+	Code string
+	Err  error
+}
+
+func NewError(kind Kind, code string) *Error {
+	return &Error{Kind: kind, Code: code}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Code + ": " + e.Err.Error()
+	}
+	return e.Code
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is matches any *Error with the same kind and code, so a wrapped copy of a
+// sentinel such as ErrNotFound still satisfies errors.Is(err, ErrNotFound).
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind && t.Code == e.Code
+}
+
+// Wrap returns a copy of e that records cause for logging.
+func (e *Error) Wrap(cause error) *Error {
+	return &Error{Kind: e.Kind, Code: e.Code, Err: cause}
+}
+
+// KindOf reports the kind of err. Errors that are not domain errors are
+// KindInternal.
+func KindOf(err error) Kind {
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return KindValidation
+	}
+	var de *Error
+	if errors.As(err, &de) {
+		return de.Kind
+	}
+	return KindInternal
+}
+
+// CodeOf returns the client-safe code of err: the domain code, or
+// "validation_failed" / "internal_error" for other errors.
+func CodeOf(err error) string {
+	var ve *ValidationError
+	if errors.As(err, &ve) {
+		return "validation_failed"
+	}
+	var de *Error
+	if errors.As(err, &de) {
+		return de.Code
+	}
+	return "internal_error"
+}
+
+var (
+	ErrNotFound          = NewError(KindNotFound, "not_found")
+	ErrSlotFull          = NewError(KindConflict, "slot_full")
+	ErrClosed            = NewError(KindValidation, "outside_business_hours")
+	ErrInvalidTransition = NewError(KindConflict, "invalid_status_transition")
+	ErrSeriesConflicts   = NewError(KindConflict, "series_conflicts")
+	ErrOfferUnavailable  = NewError(KindConflict, "offer_unavailable")
+	ErrUnknownCommand    = NewError(KindInvalid, "unknown_command")
+)
+
+var (
+	ErrInvalidInput       = NewError(KindInvalid, "invalid_input")
+	ErrInvalidService     = NewError(KindInvalid, "invalid_service")
+	ErrInvalidRange       = NewError(KindInvalid, "invalid_range")
+	ErrInvalidScope       = NewError(KindInvalid, "invalid_scope")
+	ErrNotInSeries        = NewError(KindInvalid, "not_in_series")
+	ErrInvalidURL         = NewError(KindInvalid, "invalid_url")
+	ErrInvalidEventType   = NewError(KindInvalid, "invalid_event_type")
+	ErrInvalidChannel     = NewError(KindInvalid, "invalid_channel")
+	ErrInvalidQuietHours  = NewError(KindInvalid, "invalid_quiet_hours")
+	ErrBookingCancelled   = NewError(KindConflict, "booking_cancelled")
+	ErrNotReschedulable   = NewError(KindConflict, "booking_not_reschedulable")
+	ErrEmailExists        = NewError(KindConflict, "email_exists")
+	ErrAlreadyExists      = NewError(KindConflict, "already_exists")
+	ErrInUse              = NewError(KindConflict, "in_use")
+	ErrUnauthorized       = NewError(KindUnauthorized, "unauthorized")
+	ErrInvalidCredentials = NewError(KindUnauthorized, "invalid_credentials")
+	ErrForbidden          = NewError(KindForbidden, "forbidden")
+	ErrRateLimited        = NewError(KindRateLimited, "rate_limited")
+)
@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventSchemaVersion is bumped whenever an event payload changes in a way
+// that is not backwards compatible. Schemas live in docs/events.
+const EventSchemaVersion = 1
+
+const (
+	EventBookingCreated     = "booking_created"
+	EventBookingConfirmed   = "booking_confirmed"
+	EventBookingCancelled   = "booking_cancelled"
+	EventBookingRescheduled = "booking_rescheduled"
+	EventBookingCompleted   = "booking_completed"
+	EventBookingNoShow      = "booking_no_show"
+	EventBookingReminder    = "booking_reminder"
+	EventWaitlistOffer      = "waitlist_offer"
+	EventPaymentPaid        = "payment_paid"
+	EventPaymentExpired     = "payment_expired"
+	EventPaymentRefunded    = "payment_refunded"
+	EventInvoiceIssued      = "invoice_issued"
+	EventWebhookTest        = "webhook_test"
+)
+
+var EventTypes = []string{
+	EventBookingCreated,
+	EventBookingConfirmed,
+	EventBookingCancelled,
+	EventBookingRescheduled,
+	EventBookingCompleted,
+	EventBookingNoShow,
+	EventBookingReminder,
+	EventWaitlistOffer,
+	EventPaymentPaid,
+	EventPaymentExpired,
+	EventPaymentRefunded,
+	EventInvoiceIssued,
+	EventWebhookTest,
+}
+
+// StatusEvents maps a booking status to the event emitted on entering it.
+var StatusEvents = map[string]string{
+	StatusConfirmed: EventBookingConfirmed,
+	StatusCancelled: EventBookingCancelled,
+	StatusCompleted: EventBookingCompleted,
+	StatusNoShow:    EventBookingNoShow,
+}
+
+type Event struct {
+	ID            string    `json:"id"`
+	Type          string    `json:"type"`
+	OccurredAt    time.Time `json:"occurred_at"`
+	SchemaVersion int       `json:"schema_version"`
+	Data          any       `json:"data"`
+}
+
+// UnmarshalEvent decodes an event encoded with json.Marshal, restoring Data
+// to the struct type its event type carries so it reads the same as before
+// encoding.
+func UnmarshalEvent(b []byte) (Event, error) {
+	var raw struct {
+		Event
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return Event{}, err
+	}
+	e := raw.Event
+	var err error
+	switch e.Type {
+	case EventBookingCreated, EventBookingConfirmed, EventBookingCancelled, EventBookingCompleted, EventBookingNoShow:
+		e.Data, err = decodeData[BookingData](raw.Data)
+	case EventBookingRescheduled:
+		e.Data, err = decodeData[BookingRescheduledData](raw.Data)
+	case EventBookingReminder:
+		e.Data, err = decodeData[BookingReminderData](raw.Data)
+	case EventWaitlistOffer:
+		e.Data, err = decodeData[WaitlistOfferData](raw.Data)
+	case EventWebhookTest:
+		e.Data, err = decodeData[WebhookTestData](raw.Data)
+	case EventPaymentPaid, EventPaymentExpired, EventPaymentRefunded:
+		e.Data, err = decodeData[PaymentData](raw.Data)
+	case EventInvoiceIssued:
+		e.Data, err = decodeData[InvoiceData](raw.Data)
+	default:
+		var v any
+		err = json.Unmarshal(raw.Data, &v)
+		e.Data = v
+	}
+	return e, err
+}
+
+func decodeData[T any](b []byte) (T, error) {
+	var v T
+	err := json.Unmarshal(b, &v)
+	return v, err
+}
+
+// EventPhone returns the customer phone carried by the event data, or "".
+func EventPhone(data any) string {
+	switch d := data.(type) {
+	case BookingData:
+		return d.CustomerPhone
+	case BookingRescheduledData:
+		return d.CustomerPhone
+	case BookingReminderData:
+		return d.CustomerPhone
+	case WaitlistOfferData:
+		return d.CustomerPhone
+	case InvoiceData:
+		return d.CustomerPhone
+	}
+	return ""
+}
+
+type BookingData struct {
+	BookingID     int64     `json:"booking_id"`
+	CustomerName  string    `json:"customer_name"`
+	CustomerPhone string    `json:"customer_phone"`
+	ServiceID     int64     `json:"service_id"`
+	SeriesID      *int64    `json:"series_id,omitempty"`
+	BookingDate   string    `json:"booking_date"`
+	BookingTime   string    `json:"booking_time"`
+	StartAt       time.Time `json:"start_at"`
+	EndAt         time.Time `json:"end_at"`
+	Status        string    `json:"status"`
+	Revision      int       `json:"revision"`
+}
+
+// NewBookingData expects b to be localized already.
+func NewBookingData(b Booking) BookingData {
+	return BookingData{
+		BookingID:     b.ID,
+		CustomerName:  b.CustomerName,
+		CustomerPhone: b.CustomerPhone,
+		ServiceID:     b.ServiceID,
+		SeriesID:      b.SeriesID,
+		BookingDate:   b.BookingDate.Format("2006-01-02"),
+		BookingTime:   b.BookingTime,
+		StartAt:       b.StartAt,
+		EndAt:         b.EndAt,
+		Status:        b.Status,
+		Revision:      b.Revision,
+	}
+}
+
+type BookingRescheduledData struct {
+	BookingData
+	PreviousStartAt time.Time `json:"previous_start_at"`
+	PreviousEndAt   time.Time `json:"previous_end_at"`
+}
+
+type BookingReminderData struct {
+	BookingData
+	RemindBeforeMinutes int `json:"remind_before_minutes"`
+}
+
+type WaitlistOfferData struct {
+	WaitlistID    int64     `json:"waitlist_id"`
+	CustomerName  string    `json:"customer_name"`
+	CustomerPhone string    `json:"customer_phone"`
+	ServiceID     int64     `json:"service_id"`
+	StartAt       time.Time `json:"start_at"`
+	EndAt         time.Time `json:"end_at"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	ClaimToken    string    `json:"claim_token"`
+}
+
+type WebhookTestData struct {
+	SubscriberID int64  `json:"subscriber_id"`
+	Message      string `json:"message"`
+}
+
+type PaymentData struct {
+	PaymentID int64  `json:"payment_id"`
+	BookingID int64  `json:"booking_id"`
+	Provider  string `json:"provider"`
+	Method    string `json:"method"`
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+	Status    string `json:"status"`
+}
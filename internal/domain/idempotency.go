@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord remembers the response to a request sent with an
+// Idempotency-Key. Scope separates identical keys sent to different
+// endpoints or by different callers. StatusCode is 0 while the first
+// request is still being processed.
+type IdempotencyRecord struct {
+	Key         string
+	Scope       string
+	Fingerprint string
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+func (r IdempotencyRecord) Completed() bool { return r.StatusCode != 0 }
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	InboundConfirm = "confirm"
+	InboundCancel  = "cancel"
+	InboundOptOut  = "opt_out"
+)
+
+const (
+	InboundProcessing = "processing"
+	InboundApplied    = "applied"
+	InboundRejected   = "rejected"
+	InboundFailed     = "failed"
+)
+
+// inboundKeywords maps customer reply keywords to commands.
+var inboundKeywords = map[string]string{
+	"YA":         InboundConfirm,
+	"YES":        InboundConfirm,
+	"OK":         InboundConfirm,
+	"KONFIRMASI": InboundConfirm,
+	"BATAL":      InboundCancel,
+	"CANCEL":     InboundCancel,
+	"STOP":       InboundOptOut,
+	"BERHENTI":   InboundOptOut,
+}
+
+// ParseInboundKeyword returns the command for a free-text reply such as "ya"
+// or "Batal", or "" when the reply is not recognised.
+func ParseInboundKeyword(text string) string {
+	fields := strings.Fields(strings.ToUpper(text))
+	if len(fields) == 0 {
+		return ""
+	}
+	return inboundKeywords[strings.Trim(fields[0], ".!,")]
+}
+
+// InboundMessage is the audit record of a command received from a messaging
+// channel. MessageID is the channel's own ID and makes processing idempotent.
+type InboundMessage struct {
+	MessageID   string
+	Source      string
+	Command     string
+	BookingID   int64
+	Phone       string
+	Text        string
+	Status      string
+	Error       string
+	CreatedAt   time.Time
+	ProcessedAt *time.Time
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvoiceUnavailable = NewError(KindConflict, "invoice_unavailable")
+	ErrInvalidInvoiceLink = NewError(KindForbidden, "invalid_invoice_link")
+)
+
+// InvoiceDue reports whether b can be invoiced: it was completed, or it is
+// confirmed and deposit, what was paid online, covers its whole total.
+func InvoiceDue(b Booking, deposit int64) bool {
+	switch b.Status {
+	case StatusCompleted:
+		return true
+	case StatusConfirmed:
+		return b.TotalPrice > 0 && deposit >= b.TotalPrice
+	}
+	return false
+}
+
+// Invoice is the numbered bill of a completed or fully paid booking.
+// Numbers run from 1
+// each calendar year in the business timezone without gaps. Amounts are
+// fixed when the invoice is issued; Items are the booking's line items and
+// are only filled when read through the invoice usecases.
+type Invoice struct {
+	ID            int64
+	Number        string
+	Year          int
+	Sequence      int
+	BookingID     int64
+	CustomerName  string
+	CustomerPhone string
+	ServiceName   string
+	Items         []BookingLineItem
+	Subtotal      int64
+	PromoCode     string
+	Discount      int64
+	// Prices include tax; Tax is the part of Total that is tax at
+	// TaxPercent.
+	TaxPercent int
+	Tax        int64
+	Total      int64
+	// Deposit is what the customer paid online before the appointment, net
+	// of refunds; the rest was settled at the venue.
+	Deposit  int64
+	IssuedAt time.Time
+}
+
+// NewInvoice prices b for invoicing. items are its line items; a booking
+// made before line items existed is billed as its service alone.
+func NewInvoice(b Booking, items []BookingLineItem, deposit int64, taxPercent int, issuedAt time.Time) Invoice {
+	if len(items) == 0 {
+		items = []BookingLineItem{{
+			BookingID: b.ID,
+			Kind:      LineItemService,
+			RefID:     b.ServiceID,
+			Name:      b.ServiceName,
+			Quantity:  1,
+			UnitPrice: b.ServicePrice,
+		}}
+	}
+	inv := Invoice{
+		BookingID:     b.ID,
+		CustomerName:  b.CustomerName,
+		CustomerPhone: b.CustomerPhone,
+		ServiceName:   b.ServiceName,
+		Items:         items,
+		PromoCode:     b.PromoCode,
+		Discount:      b.Discount,
+		TaxPercent:    taxPercent,
+		Total:         b.TotalPrice,
+		Deposit:       min(deposit, b.TotalPrice),
+		IssuedAt:      issuedAt,
+	}
+	for _, it := range items {
+		inv.Subtotal += it.Amount()
+	}
+	if taxPercent > 0 {
+		// Round half up so the taxable base plus tax adds up to the total.
+		base := (inv.Total*100*2 + int64(100+taxPercent)) / (int64(100+taxPercent) * 2)
+		inv.Tax = inv.Total - base
+	}
+	return inv
+}
+
+// SetItems attaches the line items of an issued invoice's booking, standing
+// in the service line for bookings made before line items existed.
+func (inv *Invoice) SetItems(items []BookingLineItem) {
+	if len(items) == 0 {
+		items = []BookingLineItem{{
+			BookingID: inv.BookingID,
+			Kind:      LineItemService,
+			Name:      inv.ServiceName,
+			Quantity:  1,
+			UnitPrice: inv.Subtotal,
+		}}
+	}
+	inv.Items = items
+}
+
+// Balance is the part of the total paid at the venue.
+func (inv Invoice) Balance() int64 {
+	return inv.Total - inv.Deposit
+}
+
+// InvoiceNumber formats a number such as "INV/2026/00042".
+func InvoiceNumber(prefix string, year, seq int) string {
+	return fmt.Sprintf("%s/%d/%05d", prefix, year, seq)
+}
+
+func (inv *Invoice) Localize(loc *time.Location) {
+	inv.IssuedAt = inv.IssuedAt.In(loc)
+}
+
+type InvoiceData struct {
+	InvoiceID      int64     `json:"invoice_id"`
+	Number         string    `json:"number"`
+	BookingID      int64     `json:"booking_id"`
+	CustomerName   string    `json:"customer_name"`
+	CustomerPhone  string    `json:"customer_phone"`
+	Total          int64     `json:"total"`
+	TotalFormatted string    `json:"total_formatted"`
+	Currency       string    `json:"currency"`
+	IssuedAt       time.Time `json:"issued_at"`
+	// DownloadURL is a signed link to the PDF for the customer, empty when
+	// links are not configured.
+	DownloadURL string `json:"download_url,omitempty"`
+}
+
+// NewInvoiceData expects inv to be localized already. TotalFormatted is left
+// to the caller.
+func NewInvoiceData(inv Invoice) InvoiceData {
+	return InvoiceData{
+		InvoiceID:     inv.ID,
+		Number:        inv.Number,
+		BookingID:     inv.BookingID,
+		CustomerName:  inv.CustomerName,
+		CustomerPhone: inv.CustomerPhone,
+		Total:         inv.Total,
+		Currency:      PaymentCurrency,
+		IssuedAt:      inv.IssuedAt,
+	}
+}
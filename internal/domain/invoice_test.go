@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewInvoiceTax(t *testing.T) {
+	tests := []struct {
+		total   int64
+		percent int
+		wantTax int64
+	}{
+		{111000, 11, 11000},
+		{150000, 11, 14865},
+		{100000, 11, 9910},
+		{1, 11, 0},
+		{150000, 0, 0},
+		{0, 11, 0},
+	}
+	for _, tt := range tests {
+		inv := NewInvoice(Booking{TotalPrice: tt.total}, nil, 0, tt.percent, time.Now())
+		if inv.Tax != tt.wantTax {
+			t.Errorf("total %d at %d%%: tax = %d, want %d", tt.total, tt.percent, inv.Tax, tt.wantTax)
+		}
+		if inv.Total != tt.total {
+			t.Errorf("total %d at %d%%: Total changed to %d", tt.total, tt.percent, inv.Total)
+		}
+	}
+}
+
+// The taxable base rounds half up, so base plus tax is always the total and
+// the base is the nearest rupiah to total/(1+rate).
+func TestNewInvoiceTaxBaseRoundsHalfUp(t *testing.T) {
+	for total := int64(1); total <= 5000; total++ {
+		inv := NewInvoice(Booking{TotalPrice: total}, nil, 0, 11, time.Now())
+		base := inv.Total - inv.Tax
+		// |base*111 - total*100| <= 111/2, with ties going up.
+		diff := base*111 - total*100
+		if diff < -55 || diff > 55 {
+			t.Fatalf("total %d: base %d is not the nearest rupiah", total, base)
+		}
+	}
+}
+
+func TestNewInvoiceItems(t *testing.T) {
+	b := Booking{ID: 7, ServiceID: 3, ServiceName: "Potong rambut", ServicePrice: 80000, TotalPrice: 70000, PromoCode: "HEMAT", Discount: 10000}
+	inv := NewInvoice(b, nil, 100000, 0, time.Now())
+	if len(inv.Items) != 1 || inv.Items[0].Name != "Potong rambut" || inv.Subtotal != 80000 {
+		t.Fatalf("service line = %+v, subtotal %d", inv.Items, inv.Subtotal)
+	}
+	if inv.Deposit != 70000 || inv.Balance() != 0 {
+		t.Errorf("deposit %d balance %d, want the deposit capped at the total", inv.Deposit, inv.Balance())
+	}
+
+	items := []BookingLineItem{
+		{Kind: LineItemService, Name: "Potong rambut", Quantity: 1, UnitPrice: 80000},
+		{Name: "Vitamin", Quantity: 2, UnitPrice: 15000},
+	}
+	inv = NewInvoice(b, items, 20000, 0, time.Now())
+	if inv.Subtotal != 110000 {
+		t.Errorf("subtotal = %d, want 110000", inv.Subtotal)
+	}
+	if inv.Balance() != 50000 {
+		t.Errorf("balance = %d, want 50000", inv.Balance())
+	}
+}
+
+func TestInvoiceNumber(t *testing.T) {
+	if got := InvoiceNumber("INV", 2026, 42); got != "INV/2026/00042" {
+		t.Errorf("InvoiceNumber = %q", got)
+	}
+}
+
+func TestInvoiceDue(t *testing.T) {
+	tests := []struct {
+		status  string
+		total   int64
+		deposit int64
+		want    bool
+	}{
+		{StatusCompleted, 150000, 0, true},
+		{StatusConfirmed, 150000, 150000, true},
+		{StatusConfirmed, 150000, 75000, false},
+		{StatusConfirmed, 0, 0, false},
+		{StatusPending, 150000, 150000, false},
+		{StatusCancelled, 150000, 150000, false},
+	}
+	for _, tt := range tests {
+		b := Booking{Status: tt.status, TotalPrice: tt.total}
+		if got := InvoiceDue(b, tt.deposit); got != tt.want {
+			t.Errorf("InvoiceDue(%s, total %d, deposit %d) = %v, want %v", tt.status, tt.total, tt.deposit, got, tt.want)
+		}
+	}
+}
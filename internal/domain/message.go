@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	ChannelWhatsApp = "whatsapp"
+	ChannelSMS      = "sms"
+)
+
+const (
+	MessageQueued    = "queued"
+	MessageSent      = "sent"
+	MessageDelivered = "delivered"
+	MessageRead      = "read"
+	MessageFailed    = "failed"
+)
+
+// messageStatusRank orders delivery statuses so late or duplicate provider
+// callbacks cannot move a message backwards.
+var messageStatusRank = map[string]int{
+	MessageQueued:    0,
+	MessageSent:      1,
+	MessageDelivered: 2,
+	MessageRead:      3,
+}
+
+// CanAdvanceMessage reports whether a message may move from one delivery
+// status to another. Failed is final and only follows queued or sent: a
+// failure reported after delivery is a late callback, not a lost message.
+func CanAdvanceMessage(from, to string) bool {
+	if from == MessageFailed {
+		return false
+	}
+	if to == MessageFailed {
+		return from == MessageQueued || from == MessageSent
+	}
+	next, ok := messageStatusRank[to]
+	return ok && next > messageStatusRank[from]
+}
+
+// OutboundMessage is the log entry of an SMS or WhatsApp message sent to a
+// customer. ProviderID is the gateway's message ID used by status callbacks.
+type OutboundMessage struct {
+	ID         int64
+	Channel    string
+	To         string
+	Body       string
+	EventID    string
+	EventType  string
+	Status     string
+	ProviderID string
+	Error      string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type MessageOptOut struct {
+	Phone     string
+	Reason    string
+	CreatedAt time.Time
+}
+
+// NormalizePhone keeps digits only and rewrites the local 0 prefix to the
+// Indonesian country code, so "0812-3456" and "+62 8123456" compare equal.
+func NormalizePhone(p string) string {
+	var sb strings.Builder
+	for _, r := range p {
+		if r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		}
+	}
+	d := sb.String()
+	if strings.HasPrefix(d, "0") {
+		d = "62" + d[1:]
+	}
+	return d
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "testing"
+
+func TestCanAdvanceMessage(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{MessageQueued, MessageSent, true},
+		{MessageQueued, MessageDelivered, true},
+		{MessageSent, MessageDelivered, true},
+		{MessageDelivered, MessageRead, true},
+		{MessageSent, MessageRead, true},
+		{MessageQueued, MessageFailed, true},
+		{MessageSent, MessageFailed, true},
+
+		{MessageSent, MessageSent, false},
+		{MessageRead, MessageSent, false},
+		{MessageRead, MessageDelivered, false},
+		{MessageDelivered, MessageSent, false},
+		{MessageDelivered, MessageFailed, false},
+		{MessageRead, MessageFailed, false},
+		{MessageFailed, MessageFailed, false},
+		{MessageFailed, MessageDelivered, false},
+		{MessageFailed, MessageRead, false},
+		{MessageSent, "bounced", false},
+	}
+	for _, tt := range tests {
+		if got := CanAdvanceMessage(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanAdvanceMessage(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
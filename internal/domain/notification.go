@@ -0,0 +1,85 @@
+package domain
+
+import "time"
+
+const (
+	NotifyChannelWebhook = "webhook"
+	NotifyChannelEmail   = "email"
+	NotifyChannelMessage = "message"
+)
+
+var NotifyChannels = []string{NotifyChannelWebhook, NotifyChannelEmail, NotifyChannelMessage}
+
+const (
+	PreferenceBusiness = "business"
+	PreferenceCustomer = "customer"
+)
+
+// urgentEvents are delivered even during quiet hours because waiting would
+// make them useless.
+var urgentEvents = map[string]bool{
+	EventBookingReminder: true,
+	EventWaitlistOffer:   true,
+	EventWebhookTest:     true,
+}
+
+func IsUrgentEvent(typ string) bool { return urgentEvents[typ] }
+
+// Attachment is a file sent along with a notification, e.g. an invoice PDF.
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// NotificationPreference is stored once for the business (Subject empty) and
+// optionally per customer (Subject is the normalized phone). Empty Channels
+// or EventTypes mean everything is allowed. QuietStart and QuietEnd are
+// "HH:MM" in the business timezone; the window may wrap past midnight.
+type NotificationPreference struct {
+	Scope      string
+	Subject    string
+	Channels   []string
+	EventTypes []string
+	QuietStart string
+	QuietEnd   string
+	UpdatedAt  time.Time
+}
+
+// Allows reports whether events of type typ may go out on channel.
+func (p NotificationPreference) Allows(channel, typ string) bool {
+	return allowed(p.Channels, channel) && allowed(p.EventTypes, typ)
+}
+
+func (p NotificationPreference) HasQuietHours() bool {
+	return p.QuietStart != "" && p.QuietEnd != "" && p.QuietStart != p.QuietEnd
+}
+
+func allowed(list []string, v string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	DeferredPending = "pending"
+	DeferredSent    = "sent"
+	DeferredFailed  = "failed"
+)
+
+// DeferredNotification holds an event for one channel until quiet hours end.
+type DeferredNotification struct {
+	ID        int64
+	Channel   string
+	Event     Event
+	DeliverAt time.Time
+	Status    string
+	Error     string
+	CreatedAt time.Time
+}
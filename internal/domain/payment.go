@@ -0,0 +1,117 @@
+package domain
+
+import "time"
+
+const (
+	PaymentMethodQRIS = "qris"
+	PaymentMethodVA   = "va"
+)
+
+const (
+	PaymentPending   = "pending"
+	PaymentPaid      = "paid"
+	PaymentExpired   = "expired"
+	PaymentRefunding = "refunding"
+	PaymentRefunded  = "refunded"
+)
+
+// PaymentCurrency is the only currency bookings are priced in.
+const PaymentCurrency = "IDR"
+
+var (
+	ErrPaymentRequired = NewError(KindConflict, "payment_required")
+	ErrPaymentFailed   = NewError(KindInternal, "payment_failed")
+)
+
+// Payment is a deposit collected for a booking through a payment gateway.
+// ExternalID is the gateway's reference; QRString, VANumber and PaymentURL
+// are whatever the gateway returned for the customer to pay with.
+type Payment struct {
+	ID         int64
+	BookingID  int64
+	Provider   string
+	Method     string
+	Bank       string
+	Amount     int64
+	Status     string
+	ExternalID string
+	QRString   string
+	VANumber   string
+	PaymentURL string
+	ExpiresAt  time.Time
+	PaidAt     *time.Time
+	// Kept is the part of a paid deposit kept as a cancellation fee; a
+	// refund returns the rest.
+	Kept int64
+	// RefundedAmount is set once the deposit has been returned.
+	RefundedAmount int64
+	RefundedAt     *time.Time
+	CreatedAt      time.Time
+}
+
+// Localize expresses the payment times in the business location.
+func (p *Payment) Localize(loc *time.Location) {
+	p.ExpiresAt = p.ExpiresAt.In(loc)
+	p.CreatedAt = p.CreatedAt.In(loc)
+	if p.PaidAt != nil {
+		t := p.PaidAt.In(loc)
+		p.PaidAt = &t
+	}
+	if p.RefundedAt != nil {
+		t := p.RefundedAt.In(loc)
+		p.RefundedAt = &t
+	}
+}
+
+// PaymentIntent is what a gateway returns when asked to collect a payment.
+type PaymentIntent struct {
+	ExternalID string
+	QRString   string
+	VANumber   string
+	PaymentURL string
+}
+
+// PaymentCallback is a gateway notification about one payment, already
+// verified and mapped to a payment status. Amount is what the gateway
+// reports as paid; a missing amount reads as zero. NoAmount is set only by
+// gateways configured as never reporting one, and skips the amount check.
+type PaymentCallback struct {
+	ExternalID string
+	Status     string
+	Amount     int64
+	NoAmount   bool
+}
+
+// PaymentChoice is how the customer wants to pay. An empty Method uses the
+// configured default; Bank picks the virtual account bank where the gateway
+// needs one.
+type PaymentChoice struct {
+	Method string
+	Bank   string
+}
+
+// DepositPolicy decides how much of a booking is paid up front. What comes
+// back on cancellation follows the booking's CancellationPolicy.
+type DepositPolicy struct {
+	// Percent of the booking total collected as a deposit; zero disables
+	// deposits and 100 collects the full price.
+	Percent int
+}
+
+// Amount returns the deposit for a booking total, rounded up to whole
+// rupiah.
+func (d DepositPolicy) Amount(total int64) int64 {
+	return percentOf(total, d.Percent)
+}
+
+func NewPaymentData(p Payment) PaymentData {
+	return PaymentData{
+		PaymentID: p.ID,
+		BookingID: p.BookingID,
+		Provider:  p.Provider,
+		Method:    p.Method,
+		Amount:    p.Amount,
+		Currency:  PaymentCurrency,
+		Status:    p.Status,
+	}
+}
@@ -0,0 +1,108 @@
+package domain
+
+// Option kinds. A variant replaces the service's base price and duration
+// (e.g. short or long hair); an add-on is an extra on top (e.g. hair wash).
+const (
+	OptionVariant = "variant"
+	OptionAddon   = "addon"
+)
+
+type ServiceOption struct {
+	ID        int64
+	ServiceID int64
+	Kind      string
+	Name      string
+	Price     int64
+	// DurationMinutes of a variant replaces the service duration; zero
+	// keeps it. For an add-on it is added to the booking.
+	DurationMinutes int
+	SortOrder       int
+	IsActive        bool
+}
+
+// OptionSelection is what the customer picked for a service.
+type OptionSelection struct {
+	VariantID *int64
+	AddonIDs  []int64
+}
+
+// Line item kinds.
+const (
+	LineItemService = "service"
+	LineItemVariant = "variant"
+	LineItemAddon   = "addon"
+)
+
+// BookingLineItem is one priced part of a booking. Name and UnitPrice are
+// copied when the booking is made; RefID points to the service or option
+// it came from.
+type BookingLineItem struct {
+	ID              int64
+	BookingID       int64
+	Kind            string
+	RefID           int64
+	Name            string
+	Quantity        int
+	UnitPrice       int64
+	DurationMinutes int
+}
+
+func (l BookingLineItem) Amount() int64 {
+	return l.UnitPrice * int64(l.Quantity)
+}
+
+// PriceQuote is the priced result of a selection.
+type PriceQuote struct {
+	Items           []BookingLineItem
+	Total           int64
+	DurationMinutes int
+}
+
+// Quote prices sel for service s given all of its options. A service with
+// active variants requires one; inactive or foreign options are rejected
+// as not found. Failures are *ValidationError values.
+func Quote(s Service, options []ServiceOption, sel OptionSelection) (PriceQuote, error) {
+	byID := make(map[int64]ServiceOption, len(options))
+	hasVariants := false
+	for _, o := range options {
+		if !o.IsActive || o.ServiceID != s.ID {
+			continue
+		}
+		byID[o.ID] = o
+		hasVariants = hasVariants || o.Kind == OptionVariant
+	}
+
+	main := BookingLineItem{Kind: LineItemService, RefID: s.ID, Name: s.Name, Quantity: 1, UnitPrice: s.Price, DurationMinutes: s.DurationMinutes}
+	switch {
+	case sel.VariantID != nil:
+		v, ok := byID[*sel.VariantID]
+		if !ok || v.Kind != OptionVariant {
+			return PriceQuote{}, Invalid("variant_id", "not_found")
+		}
+		main = BookingLineItem{Kind: LineItemVariant, RefID: v.ID, Name: s.Name + " (" + v.Name + ")", Quantity: 1, UnitPrice: v.Price, DurationMinutes: v.DurationMinutes}
+		if main.DurationMinutes == 0 {
+			main.DurationMinutes = s.DurationMinutes
+		}
+	case hasVariants:
+		return PriceQuote{}, Invalid("variant_id", "required")
+	}
+
+	q := PriceQuote{Items: []BookingLineItem{main}}
+	seen := make(map[int64]bool, len(sel.AddonIDs))
+	for _, id := range sel.AddonIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		a, ok := byID[id]
+		if !ok || a.Kind != OptionAddon {
+			return PriceQuote{}, Invalid("addon_ids", "not_found")
+		}
+		q.Items = append(q.Items, BookingLineItem{Kind: LineItemAddon, RefID: a.ID, Name: a.Name, Quantity: 1, UnitPrice: a.Price, DurationMinutes: a.DurationMinutes})
+	}
+	for _, it := range q.Items {
+		q.Total += it.Amount()
+		q.DurationMinutes += it.DurationMinutes * it.Quantity
+	}
+	return q, nil
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQuote(t *testing.T) {
+	haircut := Service{ID: 1, Name: "Haircut", Price: 50000, DurationMinutes: 30}
+	id := func(v int64) *int64 { return &v }
+	options := []ServiceOption{
+		{ID: 10, ServiceID: 1, Kind: OptionVariant, Name: "Short", Price: 60000, IsActive: true},
+		{ID: 11, ServiceID: 1, Kind: OptionVariant, Name: "Long", Price: 90000, DurationMinutes: 60, IsActive: true},
+		{ID: 12, ServiceID: 1, Kind: OptionVariant, Name: "Retired", Price: 40000, IsActive: false},
+		{ID: 20, ServiceID: 1, Kind: OptionAddon, Name: "Wash", Price: 15000, DurationMinutes: 15, IsActive: true},
+		{ID: 21, ServiceID: 1, Kind: OptionAddon, Name: "Mask", Price: 25000, DurationMinutes: 10, IsActive: true},
+		{ID: 30, ServiceID: 2, Kind: OptionAddon, Name: "Other service", Price: 1000, IsActive: true},
+	}
+	addons := []ServiceOption{options[3], options[4]}
+
+	tests := []struct {
+		name         string
+		options      []ServiceOption
+		sel          OptionSelection
+		wantTotal    int64
+		wantDuration int
+		wantItems    []string
+		wantErr      string
+	}{
+		{
+			name:         "plain service",
+			wantTotal:    50000,
+			wantDuration: 30,
+			wantItems:    []string{"service:Haircut"},
+		},
+		{
+			name:         "variant keeps service duration",
+			options:      options,
+			sel:          OptionSelection{VariantID: id(10)},
+			wantTotal:    60000,
+			wantDuration: 30,
+			wantItems:    []string{"variant:Haircut (Short)"},
+		},
+		{
+			name:         "variant with add-ons, duplicates counted once",
+			options:      options,
+			sel:          OptionSelection{VariantID: id(11), AddonIDs: []int64{20, 21, 20}},
+			wantTotal:    130000,
+			wantDuration: 85,
+			wantItems:    []string{"variant:Haircut (Long)", "addon:Wash", "addon:Mask"},
+		},
+		{
+			name:         "add-ons without variants",
+			options:      addons,
+			sel:          OptionSelection{AddonIDs: []int64{21}},
+			wantTotal:    75000,
+			wantDuration: 40,
+			wantItems:    []string{"service:Haircut", "addon:Mask"},
+		},
+		{name: "variant required", options: options, sel: OptionSelection{AddonIDs: []int64{20}}, wantErr: "variant_id required"},
+		{name: "inactive variant", options: options, sel: OptionSelection{VariantID: id(12)}, wantErr: "variant_id not_found"},
+		{name: "add-on as variant", options: options, sel: OptionSelection{VariantID: id(20)}, wantErr: "variant_id not_found"},
+		{name: "variant as add-on", options: options, sel: OptionSelection{VariantID: id(10), AddonIDs: []int64{11}}, wantErr: "addon_ids not_found"},
+		{name: "foreign add-on", options: addons, sel: OptionSelection{AddonIDs: []int64{30}}, wantErr: "addon_ids not_found"},
+		{name: "unknown add-on", options: addons, sel: OptionSelection{AddonIDs: []int64{99}}, wantErr: "addon_ids not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Quote(haircut, tt.options, tt.sel)
+			if tt.wantErr != "" {
+				var ve *ValidationError
+				if !errors.As(err, &ve) || len(ve.Fields) != 1 || ve.Fields[0].Field+" "+ve.Fields[0].Code != tt.wantErr {
+					t.Fatalf("err = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if q.Total != tt.wantTotal || q.DurationMinutes != tt.wantDuration {
+				t.Errorf("total %d for %d min, want %d for %d min", q.Total, q.DurationMinutes, tt.wantTotal, tt.wantDuration)
+			}
+			var items []string
+			var sum int64
+			for _, it := range q.Items {
+				items = append(items, it.Kind+":"+it.Name)
+				sum += it.Amount()
+			}
+			if len(items) != len(tt.wantItems) {
+				t.Fatalf("items = %v, want %v", items, tt.wantItems)
+			}
+			for i := range items {
+				if items[i] != tt.wantItems[i] {
+					t.Errorf("item %d = %q, want %q", i, items[i], tt.wantItems[i])
+				}
+			}
+			if sum != q.Total {
+				t.Errorf("line items sum to %d, total is %d", sum, q.Total)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
+// Promotion is a discount code such as "DISKON20". Zero limits mean
+// unlimited; an empty ServiceIDs applies to every service.
+type Promotion struct {
+	ID           int64
+	Code         string
+	Description  string
+	DiscountType string
+	// Value is a percentage (1–100) for DiscountPercent and an amount in
+	// rupiah for DiscountFixed.
+	Value int64
+	// MaxDiscount caps a percentage discount; zero means no cap.
+	MaxDiscount      int64
+	MinSpend         int64
+	StartsAt         *time.Time
+	EndsAt           *time.Time
+	UsageLimit       int
+	PerCustomerLimit int
+	// FirstVisitOnly limits the code to customers without earlier
+	// bookings.
+	FirstVisitOnly bool
+	ServiceIDs     []int64
+	IsActive       bool
+	CreatedAt      time.Time
+}
+
+// PromotionUsage counts the redemptions of a promotion on bookings that are
+// not cancelled, in total and for one customer.
+type PromotionUsage struct {
+	Total    int
+	Customer int
+}
+
+// PromotionRedemption records a discount given on a booking.
+type PromotionRedemption struct {
+	ID            int64
+	PromotionID   int64
+	BookingID     int64
+	Code          string
+	CustomerPhone string
+	Discount      int64
+	RedeemedAt    time.Time
+	// BookingStatus and BookingTotal are filled on reads.
+	BookingStatus string
+	BookingTotal  int64
+}
+
+// PromotionReport sums the redemptions of one promotion over a period.
+// Cancelled bookings are counted separately and excluded from the amounts.
+type PromotionReport struct {
+	PromotionID   int64
+	Code          string
+	Redemptions   int
+	Cancelled     int
+	TotalDiscount int64
+	Revenue       int64
+}
+
+// ErrPromoUnavailable is returned when a code's usage limit was reached
+// between validating it and storing the booking.
+var ErrPromoUnavailable = NewError(KindConflict, "promo_unavailable")
+
+// NormalizePromoCode makes codes case-insensitive: " diskon20" is "DISKON20".
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Discount returns the discount on subtotal. It never exceeds subtotal.
+func (p Promotion) Discount(subtotal int64) int64 {
+	d := p.Value
+	if p.DiscountType == DiscountPercent {
+		d = subtotal * p.Value / 100
+		if p.MaxDiscount > 0 && d > p.MaxDiscount {
+			d = p.MaxDiscount
+		}
+	}
+	if d > subtotal {
+		d = subtotal
+	}
+	return d
+}
+
+func (p Promotion) appliesTo(serviceID int64) bool {
+	if len(p.ServiceIDs) == 0 {
+		return true
+	}
+	for _, id := range p.ServiceIDs {
+		if id == serviceID {
+			return true
+		}
+	}
+	return false
+}
+
+// Check reports why the promotion cannot be used for a booking of
+// serviceID worth subtotal at now, as a *ValidationError on "promo_code".
+// firstVisit tells whether the customer has no earlier bookings.
+func (p Promotion) Check(now time.Time, serviceID, subtotal int64, usage PromotionUsage, firstVisit bool) error {
+	fail := func(code string, params map[string]any) error {
+		return &ValidationError{Fields: []FieldError{{Field: "promo_code", Code: code, Params: params}}}
+	}
+	switch {
+	case !p.IsActive:
+		return fail("promo_not_found", nil)
+	case p.StartsAt != nil && now.Before(*p.StartsAt):
+		return fail("promo_not_started", nil)
+	case p.EndsAt != nil && !now.Before(*p.EndsAt):
+		return fail("promo_expired", nil)
+	case p.UsageLimit > 0 && usage.Total >= p.UsageLimit:
+		return fail("promo_exhausted", nil)
+	case p.PerCustomerLimit > 0 && usage.Customer >= p.PerCustomerLimit:
+		return fail("promo_customer_limit", nil)
+	case p.FirstVisitOnly && !firstVisit:
+		return fail("promo_first_visit", nil)
+	case !p.appliesTo(serviceID):
+		return fail("promo_not_applicable", nil)
+	case subtotal < p.MinSpend:
+		return fail("promo_min_spend", map[string]any{"min": p.MinSpend})
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+const (
+	ReminderPending = "pending"
+	ReminderSending = "sending"
+	ReminderSent    = "sent"
+	ReminderSkipped = "skipped"
+	ReminderFailed  = "failed"
+	// ReminderUnknown marks a job whose send was interrupted; it may or may
+	// not have reached the customer and is left for an operator.
+	ReminderUnknown = "unknown"
+)
+
+type ReminderJob struct {
+	ID        int64
+	BookingID int64
+	Offset    time.Duration
+	StartAt   time.Time
+	RunAt     time.Time
+	Status    string
+	SentAt    *time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+type TimeRange struct {
+	Opens  string
+	Closes string
+}
+
+type OpeningInterval struct {
+	ID      int64
+	Weekday int
+	Opens   string
+	Closes  string
+}
+
+type ScheduleOverride struct {
+	ID     int64
+	Date   time.Time
+	Opens  string
+	Closes string
+}
+
+type Closure struct {
+	ID          int64
+	StartDate   time.Time
+	EndDate     time.Time
+	Reason      string
+	ExternalUID string
+}
+
+type DaySchedule struct {
+	Date      time.Time
+	Open      bool
+	Intervals []TimeRange
+	Reason    string
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ScopeThis      = "this"
+	ScopeFollowing = "following"
+	ScopeAll       = "all"
+)
+
+const MaxOccurrences = 52
+
+var ErrInvalidRule = NewError(KindInvalid, "invalid_rule")
+
+type BookingSeries struct {
+	ID            int64
+	CustomerName  string
+	CustomerPhone string
+	ServiceID     int64
+	Rule          string
+	CreatedAt     time.Time
+}
+
+// RecurrenceRule is the subset of RFC 5545 RRULE we support: FREQ=WEEKLY
+// with an optional INTERVAL and either COUNT or UNTIL.
+type RecurrenceRule struct {
+	Interval int
+	Count    int
+	Until    time.Time
+}
+
+func ParseRecurrenceRule(s string) (RecurrenceRule, error) {
+	r := RecurrenceRule{Interval: 1}
+	freq := ""
+	for _, part := range strings.Split(strings.TrimPrefix(strings.TrimSpace(s), "RRULE:"), ";") {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			return r, ErrInvalidRule
+		}
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			freq = strings.ToUpper(v)
+		case "INTERVAL":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return r, ErrInvalidRule
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return r, ErrInvalidRule
+			}
+			r.Count = n
+		case "UNTIL":
+			t, err := time.Parse("20060102", v[:min(len(v), 8)])
+			if err != nil {
+				return r, ErrInvalidRule
+			}
+			r.Until = t
+		default:
+			return r, ErrInvalidRule
+		}
+	}
+	if freq != "WEEKLY" || (r.Count == 0 && r.Until.IsZero()) || (r.Count > 0 && !r.Until.IsZero()) {
+		return r, ErrInvalidRule
+	}
+	return r, nil
+}
+
+// Occurrences returns the start times generated from first, keeping the wall
+// clock time in loc. UNTIL is an inclusive calendar date. At most
+// MaxOccurrences are returned.
+func (r RecurrenceRule) Occurrences(first time.Time, loc *time.Location) []time.Time {
+	first = first.In(loc)
+	var out []time.Time
+	for i := 0; len(out) < MaxOccurrences; i++ {
+		t := first.AddDate(0, 0, 7*r.Interval*i)
+		if r.Count > 0 && i >= r.Count {
+			break
+		}
+		if !r.Until.IsZero() && time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).After(r.Until) {
+			break
+		}
+		out = append(out, t)
+	}
+	return out
+}
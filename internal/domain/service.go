@@ -1,8 +1,58 @@
 package domain
 
+import (
+	"strings"
+	"time"
+)
+
 type Service struct {
-	ID       int64
-	Name     string
-	Price    int64
-	IsActive bool
+	ID              int64
+	Name            string
+	Description     string
+	Price           int64
+	DurationMinutes int
+	IsActive        bool
+	// ArchivedAt is set when the service was deleted. Archived services stay
+	// in the table so historical bookings keep their reference.
+	ArchivedAt *time.Time
+
+	// CategoryID is nil for uncategorised services. CategoryName is filled
+	// on reads.
+	CategoryID   *int64
+	CategoryName string
+	// ImageKey locates the image in blob storage; ImageURL is where clients
+	// fetch it. Both are empty when the service has no image.
+	ImageKey  string
+	ImageURL  string
+	SortOrder int
+	Tags      []string
+}
+
+// Bookable reports whether new bookings may use the service.
+func (s Service) Bookable() bool {
+	return s.IsActive && s.ArchivedAt == nil
+}
+
+// ServiceFilter narrows a service listing. Zero values match everything.
+type ServiceFilter struct {
+	CategoryID int64
+	Tag        string
+	// IncludeArchived only applies to the admin listing.
+	IncludeArchived bool
+}
+
+// NormalizeTags lowercases and trims tags, dropping empty and repeated ones
+// while keeping the original order.
+func NormalizeTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out
 }
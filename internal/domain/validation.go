@@ -0,0 +1,48 @@
+package domain
+
+import "strings"
+
+// Input limits shared by the request validators and usecases.
+const (
+	MinNameLength      = 2
+	MaxNameLength      = 100
+	MinPhoneDigits     = 8
+	MaxPhoneDigits     = 15
+	MinServicePrice    = 0
+	MaxServicePrice    = 100_000_000
+	MinServiceDuration = 5
+	MaxServiceDuration = 720
+	MaxDescription     = 5000
+	MaxTags            = 10
+	MaxTagLength       = 30
+	MaxImageBytes      = 2 << 20
+	MinPromoCodeLength = 3
+	MaxPromoCodeLength = 30
+)
+
+// FieldError describes why one input field was rejected. Code is a stable
+// machine readable reason such as "required" or "too_long"; Params carries
+// the values needed to phrase it, e.g. {"max": 100}.
+type FieldError struct {
+	Field  string
+	Code   string
+	Params map[string]any
+}
+
+// ValidationError collects every rejected field of one request.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	codes := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		codes = append(codes, f.Field+": "+f.Code)
+	}
+	return "validation_failed: " + strings.Join(codes, ", ")
+}
+
+// Invalid returns a ValidationError for a single field.
+func Invalid(field, code string) *ValidationError {
+	return &ValidationError{Fields: []FieldError{{Field: field, Code: code}}}
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+const (
+	WaitlistWaiting   = "waiting"
+	WaitlistOffered   = "offered"
+	WaitlistClaimed   = "claimed"
+	WaitlistExpired   = "expired"
+	WaitlistCancelled = "cancelled"
+)
+
+type WaitlistEntry struct {
+	ID             int64
+	CustomerName   string
+	CustomerPhone  string
+	ServiceID      int64
+	Date           time.Time
+	WindowStart    string
+	WindowEnd      string
+	Status         string
+	OfferStartAt   *time.Time
+	OfferEndAt     *time.Time
+	OfferExpiresAt *time.Time
+	ClaimToken     string
+	BookingID      *int64
+	CreatedAt      time.Time
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// ErrWebhookFailed reports a delivery the subscriber did not accept.
+var ErrWebhookFailed = NewError(KindInternal, "webhook_failed")
+
+type WebhookSubscriber struct {
+	ID             int64
+	URL            string
+	EventTypes     []string
+	Secret         string
+	PreviousSecret string
+	Active         bool
+	Headers        map[string]string
+	CreatedAt      time.Time
+}
+
+// Wants reports whether the subscriber receives events of type typ. An empty
+// filter subscribes to everything.
+func (s WebhookSubscriber) Wants(typ string) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range s.EventTypes {
+		if t == typ || t == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDelivery struct {
+	ID              int64
+	SubscriberID    int64
+	EventID         string
+	EventType       string
+	StatusCode      int
+	LatencyMS       int64
+	ResponseSnippet string
+	Error           string
+	CreatedAt       time.Time
+}
@@ -13,14 +13,266 @@ type UserRepository interface {
 
 type BookingRepository interface {
 	Create(b domain.Booking) (int64, error)
+	CreateInSlot(b domain.Booking, capacity int) (int64, error)
 	ListLatest(limit int) ([]domain.Booking, error)
-	CountOnDate(day time.Time) (int, error)
+	CountCreatedBetween(start, end time.Time) (int, error)
+	GetByID(id int64) (*domain.Booking, error)
+	Reschedule(id int64, start, end time.Time, capacity int, changedAt time.Time) (domain.BookingHistory, error)
+	ListHistory(bookingID int64) ([]domain.BookingHistory, error)
+	// UpdateStatus moves a booking from one status to another and returns
+	// domain.ErrInvalidTransition when it is no longer in status from.
+	UpdateStatus(id int64, from, to string) error
+	CountOverlapping(start, end time.Time, excludeID int64) (int, error)
+	CreateSeries(s domain.BookingSeries, items []domain.Booking, capacity int) (int64, []int64, error)
+	GetSeries(id int64) (*domain.BookingSeries, error)
+	ListBySeries(seriesID int64) ([]domain.Booking, error)
+	ListCalendar(from, to time.Time, serviceID int64) ([]domain.Booking, error)
+	ListItems(bookingID int64) ([]domain.BookingLineItem, error)
+	// CountByPhone counts bookings that are not cancelled for a phone number
+	// in domain.NormalizePhone form.
+	CountByPhone(phone string) (int, error)
+	// UpdateStatusCharged is UpdateStatus that also records c, atomically.
+	UpdateStatusCharged(id int64, from, to string, c domain.BookingCharge) (int64, error)
+	ListCharges(bookingID int64) ([]domain.BookingCharge, error)
 }
 
 type ServiceRepository interface {
 	Create(s domain.Service) (int64, error)
+	GetByID(id int64) (*domain.Service, error)
+	Update(s domain.Service) error
+	// Archive marks the service deleted at the given time; archiving an
+	// archived service keeps the original time.
+	Archive(id int64, at time.Time) error
+	Restore(id int64) error
+	// ListActive and List return services in display order: by category
+	// order, then by the service's own sort order and name.
+	ListActive(f domain.ServiceFilter) ([]domain.Service, error)
+	// List returns active and inactive services, and archived ones too when
+	// f.IncludeArchived is set.
+	List(f domain.ServiceFilter) ([]domain.Service, error)
+	// Reorder sets the sort order of the given services to their position
+	// in ids.
+	Reorder(ids []int64) error
+}
+
+type ServiceOptionRepository interface {
+	Create(o domain.ServiceOption) (int64, error)
+	GetByID(id int64) (*domain.ServiceOption, error)
+	Update(o domain.ServiceOption) error
+	Delete(id int64) error
+	// ListByService returns variants and add-ons, active or not, by sort
+	// order.
+	ListByService(serviceID int64) ([]domain.ServiceOption, error)
+}
+
+// PromotionRepository stores promotions and their redemptions. Bookings
+// created with a PromotionID record the redemption in the same transaction
+// and fail with domain.ErrPromoUnavailable once a limit is reached.
+type PromotionRepository interface {
+	Create(p domain.Promotion) (int64, error)
+	GetByID(id int64) (*domain.Promotion, error)
+	GetByCode(code string) (*domain.Promotion, error)
+	Update(p domain.Promotion) error
+	Delete(id int64) error
+	List() ([]domain.Promotion, error)
+	// Usage counts redemptions on bookings that are not cancelled; phone is
+	// in domain.NormalizePhone form.
+	Usage(promotionID int64, phone string) (domain.PromotionUsage, error)
+	ListRedemptions(promotionID int64) ([]domain.PromotionRedemption, error)
+	// Report sums redemptions made in [from, to) per promotion.
+	Report(from, to time.Time) ([]domain.PromotionReport, error)
+}
+
+// CancellationPolicyRepository stores policies keyed by service ID, zero
+// for the business policy. Get returns domain.ErrNotFound when none is
+// stored.
+type CancellationPolicyRepository interface {
+	Get(serviceID int64) (*domain.CancellationPolicy, error)
+	List() ([]domain.CancellationPolicy, error)
+	Save(p domain.CancellationPolicy) error
+	Delete(serviceID int64) error
+}
+
+// InvoiceRepository stores invoices, at most one per booking. Create takes
+// the next number of inv.Year in the same transaction as the insert, so a
+// failed insert does not use up a number; it fills inv.ID, inv.Sequence and
+// inv.Number, and returns domain.ErrAlreadyExists when the booking already
+// has an invoice.
+type InvoiceRepository interface {
+	Create(inv *domain.Invoice, prefix string) error
+	GetByBooking(bookingID int64) (*domain.Invoice, error)
+	ListByYear(year int) ([]domain.Invoice, error)
+}
+
+// PaymentRepository stores booking payments. The status updates only apply
+// while the payment is still in the expected status and report false
+// otherwise, so concurrent callbacks and the expiry job act once. MarkPaid
+// also accepts an expired payment, since a gateway may report a payment
+// that raced its expiry. A refund claims a paid payment with
+// MarkRefunding before the gateway is asked, and either completes it with
+// MarkRefunded or hands it back with ReleaseRefund.
+type PaymentRepository interface {
+	Create(p domain.Payment) (int64, error)
+	SetIntent(id int64, in domain.PaymentIntent) error
+	GetByID(id int64) (*domain.Payment, error)
+	GetByExternalID(provider, externalID string) (*domain.Payment, error)
+	ListByBooking(bookingID int64) ([]domain.Payment, error)
+	MarkPaid(id int64, at time.Time) (bool, error)
+	MarkExpired(id int64) (bool, error)
+	// Keep records the part of a paid deposit kept as a fee.
+	Keep(id int64, amount int64) (bool, error)
+	MarkRefunding(id int64) (bool, error)
+	MarkRefunded(id int64, amount int64, at time.Time) (bool, error)
+	ReleaseRefund(id int64) error
+	// ListExpired returns pending payments whose expiry is not after now.
+	ListExpired(now time.Time, limit int) ([]domain.Payment, error)
+}
+
+type CategoryRepository interface {
+	Create(c domain.Category) (int64, error)
+	GetByID(id int64) (*domain.Category, error)
+	Update(c domain.Category) error
+	// Delete fails with domain.ErrInUse while services still reference the
+	// category.
 	Delete(id int64) error
-	ListActive() ([]domain.Service, error)
+	List() ([]domain.Category, error)
+	Reorder(ids []int64) error
+}
+
+type ScheduleRepository interface {
+	ListWeekly() ([]domain.OpeningInterval, error)
+	CreateWeekly(i domain.OpeningInterval) (int64, error)
+	DeleteWeekly(id int64) error
+	ListOverrides(from, to time.Time) ([]domain.ScheduleOverride, error)
+	CreateOverride(o domain.ScheduleOverride) (int64, error)
+	DeleteOverride(id int64) error
+	ListClosures(from, to time.Time) ([]domain.Closure, error)
+	CreateClosure(c domain.Closure) (int64, error)
+	UpsertClosureByUID(c domain.Closure) (int64, error)
+	DeleteClosure(id int64) error
+}
+
+type WaitlistRepository interface {
+	Create(e domain.WaitlistEntry) (int64, error)
+	GetByID(id int64) (*domain.WaitlistEntry, error)
+	ListByDate(date time.Time) ([]domain.WaitlistEntry, error)
+	NextWaiting(serviceID int64, date time.Time, clock string) (*domain.WaitlistEntry, error)
+	// MarkOffered reports false when the entry is no longer waiting.
+	MarkOffered(id int64, start, end, expiresAt time.Time, token string) (bool, error)
+	// Claim books b for the open offer of entry id with token and marks the
+	// entry claimed, atomically.
+	Claim(id int64, token string, b domain.Booking, capacity int, now time.Time) (int64, error)
+	MarkExpired(id int64) error
+	ListExpiredOffers(now time.Time) ([]domain.WaitlistEntry, error)
+}
+
+// ReminderRepository stores reminder jobs. Abandon marks jobs left in
+// sending since before staleBefore, e.g. by a crash mid-send, as unknown
+// instead of sending them again, since the reminder may have gone out.
+type ReminderRepository interface {
+	Enqueue(offset time.Duration, from, to time.Time) (int, error)
+	Due(now time.Time, limit int) ([]domain.ReminderJob, error)
+	Claim(id int64, now time.Time) (bool, error)
+	Finish(id int64, status string, at time.Time) error
+	Abandon(staleBefore time.Time) ([]int64, error)
+}
+
+type LeaderElector interface {
+	IsLeader() (bool, error)
+}
+
+type WebhookRepository interface {
+	List() ([]domain.WebhookSubscriber, error)
+	ListActive() ([]domain.WebhookSubscriber, error)
+	GetByID(id int64) (*domain.WebhookSubscriber, error)
+	Create(s domain.WebhookSubscriber) (int64, error)
+	Update(s domain.WebhookSubscriber) error
+	Delete(id int64) error
+	LogDelivery(d domain.WebhookDelivery) error
+	ListDeliveries(subscriberID int64, limit int) ([]domain.WebhookDelivery, error)
+}
+
+// InboundMessageRepository stores inbound commands keyed by message ID.
+// Record returns false without storing anything when the ID was seen before,
+// unless the earlier attempt ended as failed.
+type InboundMessageRepository interface {
+	Record(m domain.InboundMessage) (bool, error)
+	Get(messageID string) (*domain.InboundMessage, error)
+	Finish(messageID, status, errCode string, at time.Time) error
+}
+
+// MessageRepository logs outbound customer messages and keeps the opt-out
+// list. Phones are stored normalized (see domain.NormalizePhone).
+type MessageRepository interface {
+	Create(m domain.OutboundMessage) (int64, error)
+	Finish(id int64, status, providerID, errText string, at time.Time) error
+	GetByProviderID(providerID string) (*domain.OutboundMessage, error)
+	UpdateStatus(id int64, status, errText string, at time.Time) error
+	ListRecent(limit int) ([]domain.OutboundMessage, error)
+	IsOptedOut(phone string) (bool, error)
+	OptOut(o domain.MessageOptOut) error
+	OptIn(phone string) error
+	ListOptOuts() ([]domain.MessageOptOut, error)
+}
+
+// NotificationPreferenceRepository stores preferences keyed by scope and
+// subject. Get returns domain.ErrNotFound when none is stored.
+type NotificationPreferenceRepository interface {
+	Get(scope, subject string) (*domain.NotificationPreference, error)
+	List(scope string) ([]domain.NotificationPreference, error)
+	Save(p domain.NotificationPreference) error
+	Delete(scope, subject string) error
+}
+
+type DeferredNotificationRepository interface {
+	Defer(d domain.DeferredNotification) error
+	Due(now time.Time, limit int) ([]domain.DeferredNotification, error)
+	Finish(id int64, status, errText string, at time.Time) error
+}
+
+// CalendarTokenRepository stores feed tokens. GetByHash returns
+// domain.ErrNotFound for unknown and revoked tokens.
+type CalendarTokenRepository interface {
+	Create(t domain.CalendarToken) (int64, error)
+	ListByUser(userID int64) ([]domain.CalendarToken, error)
+	GetByHash(hash string) (*domain.CalendarToken, error)
+	Revoke(id, userID int64, at time.Time) error
+	Touch(id int64, at time.Time) error
+}
+
+// IdempotencyStore keeps responses for Idempotency-Key requests. Begin
+// claims r's key and scope and returns nil, or returns the record already
+// holding them. Records that expired, or were left unfinished before
+// staleBefore, are claimed again.
+type IdempotencyStore interface {
+	Begin(r domain.IdempotencyRecord, staleBefore time.Time) (*domain.IdempotencyRecord, error)
+	Complete(r domain.IdempotencyRecord) error
+	Release(key, scope string) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+// BlobStore keeps uploaded files such as service images. Put returns the
+// URL clients use to fetch the file.
+type BlobStore interface {
+	Put(key, contentType string, data []byte) (string, error)
+	Delete(key string) error
+}
+
+// PaymentGateway collects payments through a provider such as a QRIS or
+// virtual account aggregator. CreateIntent asks the provider to collect
+// p.Amount with p.Method; p.ID is the merchant reference. ParseCallback
+// verifies the signature of a provider notification and maps it to a
+// payment status; it returns domain.ErrUnauthorized for bad signatures.
+type PaymentGateway interface {
+	Name() string
+	CreateIntent(p domain.Payment, b domain.Booking) (domain.PaymentIntent, error)
+	Refund(p domain.Payment, amount int64) error
+	ParseCallback(header func(key string) string, body []byte) (domain.PaymentCallback, error)
+}
+
+// InvoiceRenderer turns an invoice into a PDF document.
+type InvoiceRenderer interface {
+	Render(inv domain.Invoice) ([]byte, error)
 }
 
 type Logger interface {
@@ -28,5 +280,15 @@ type Logger interface {
 }
 
 type Notifier interface {
-	NotifyBookingCreated(b domain.Booking) error
+	Notify(e domain.Event) error
+}
+
+type WebhookSender interface {
+	Deliver(s domain.WebhookSubscriber, e domain.Event) (domain.WebhookDelivery, error)
+}
+
+// MessageSender hands a message to an SMS or WhatsApp provider and returns
+// the provider's message ID.
+type MessageSender interface {
+	Send(channel, to, body string) (string, error)
 }
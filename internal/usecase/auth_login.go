@@ -4,6 +4,7 @@ import (
 	"errors"
 	"time"
 
+	"be-golang/internal/domain"
 	"be-golang/internal/ports"
 	"be-golang/internal/util"
 
@@ -23,12 +24,15 @@ func NewAuthLogin(users ports.UserRepository, logger ports.Logger, jwt *util.JWT
 
 func (a *AuthLogin) Exec(email, password string) (string, error) {
 	u, err := a.users.GetByEmail(email)
-	if err != nil || u == nil {
-		return "", errors.New("invalid credentials")
+	if errors.Is(err, domain.ErrNotFound) {
+		return "", domain.ErrInvalidCredentials
+	}
+	if err != nil {
+		return "", err
 	}
 	err = bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password))
 	if err != nil {
-		return "", errors.New("invalid credentials")
+		return "", domain.ErrInvalidCredentials
 	}
 	claims := map[string]any{"sub": u.ID, "email": u.Email}
 	token, err := a.jwt.Generate(claims, a.tokenTTL)
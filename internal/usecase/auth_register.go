@@ -20,11 +20,14 @@ func NewAdminRegister(users ports.UserRepository, logger ports.Logger) *AdminReg
 
 func (u *AdminRegister) Exec(email, password string) (int64, error) {
 	if email == "" || password == "" {
-		return 0, errors.New("invalid_input")
+		return 0, domain.ErrInvalidInput
 	}
-	existing, _ := u.users.GetByEmail(email)
-	if existing != nil {
-		return 0, errors.New("email_exists")
+	_, err := u.users.GetByEmail(email)
+	if err == nil {
+		return 0, domain.ErrEmailExists
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return 0, err
 	}
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
@@ -36,6 +39,9 @@ func (u *AdminRegister) Exec(email, password string) (int64, error) {
 		PasswordHash: string(hash),
 		CreatedAt:    now,
 	})
+	if errors.Is(err, domain.ErrAlreadyExists) {
+		return 0, domain.ErrEmailExists
+	}
 	if err != nil {
 		return 0, err
 	}
@@ -1,32 +1,101 @@
 package usecase
 
 import (
+	"strings"
 	"time"
 
 	"be-golang/internal/domain"
 	"be-golang/internal/ports"
+	"be-golang/internal/util"
 )
 
 type BookingCreate struct {
 	bookings ports.BookingRepository
+	pricing  *PriceCalculate
+	promos   *PromotionApply
+	payments *PaymentStart
 	notifier ports.Notifier
 	logger   ports.Logger
+	schedule ports.ScheduleRepository
+	waitlist *WaitlistAdvance
+	capacity int
+	loc      *time.Location
 }
 
-func NewBookingCreate(b ports.BookingRepository, n ports.Notifier, l ports.Logger) *BookingCreate {
-	return &BookingCreate{bookings: b, notifier: n, logger: l}
+// NewBookingCreate takes a nil ps when deposits are not collected.
+func NewBookingCreate(b ports.BookingRepository, p *PriceCalculate, pa *PromotionApply, ps *PaymentStart, n ports.Notifier, l ports.Logger, sch ports.ScheduleRepository, w *WaitlistAdvance, capacity int, loc *time.Location) *BookingCreate {
+	return &BookingCreate{bookings: b, pricing: p, promos: pa, payments: ps, notifier: n, logger: l, schedule: sch, waitlist: w, capacity: capacity, loc: loc}
 }
 
-func (u *BookingCreate) Exec(input domain.Booking) (int64, error) {
+// Exec creates a pending booking. input.BookingDate is read as a calendar
+// date and input.BookingTime as an "HH:MM" wall clock time in the business
+// location. The start must lie in the future and the service must exist and
+// be active; otherwise a *domain.ValidationError is returned. The price and
+// duration come from the selected variant and add-ons, which are stored as
+// line items. A non-empty input.PromoCode is checked and its discount taken
+// off the total; the redemption is recorded with the booking. When a
+// deposit is due the booking starts as awaiting_payment and the returned
+// payment tells the customer how to pay; if the gateway fails the booking
+// is cancelled again and the slot offered to the waitlist.
+func (u *BookingCreate) Exec(input domain.Booking, sel domain.OptionSelection, choice domain.PaymentChoice) (int64, *domain.Payment, error) {
+	start, err := util.AtClock(input.BookingDate, input.BookingTime, u.loc)
+	if err != nil {
+		return 0, nil, err
+	}
 	now := time.Now().UTC()
-	input.Status = "pending"
+	if !start.After(now) {
+		return 0, nil, domain.Invalid("booking_date", "must_be_future")
+	}
+	svc, quote, err := u.pricing.Exec(input.ServiceID, sel)
+	if err != nil {
+		return 0, nil, err
+	}
+	input.CustomerName = strings.TrimSpace(input.CustomerName)
+	input.CustomerPhone = strings.TrimSpace(input.CustomerPhone)
+	input.ServiceName = svc.Name
+	input.ServicePrice = quote.Items[0].UnitPrice
+	input.TotalPrice = quote.Total
+	input.Items = quote.Items
+	if input.PromoCode != "" {
+		p, discount, err := u.promos.Exec(input.PromoCode, svc.ID, quote.Total, input.CustomerPhone)
+		if err != nil {
+			return 0, nil, err
+		}
+		input.PromotionID, input.PromoCode, input.Discount = &p.ID, p.Code, discount
+		input.TotalPrice -= discount
+	}
+	input.StartAt = start
+	input.EndAt = start.Add(time.Duration(quote.DurationMinutes) * time.Minute)
+	if err := checkOpen(u.schedule, input.StartAt, input.EndAt, u.loc); err != nil {
+		return 0, nil, err
+	}
+	var deposit int64
+	if u.payments != nil {
+		deposit = u.payments.Deposit(input.TotalPrice)
+	}
+	input.Status = domain.StatusPending
+	if deposit > 0 {
+		input.Status = domain.StatusAwaitingPayment
+	}
 	input.CreatedAt = now
-	id, err := u.bookings.Create(input)
+	id, err := u.bookings.CreateInSlot(input, u.capacity)
 	if err != nil {
-		return 0, err
+		return 0, nil, err
 	}
 	input.ID = id
-	_ = u.notifier.NotifyBookingCreated(input)
+	var payment *domain.Payment
+	if deposit > 0 {
+		payment, err = u.payments.Exec(input, deposit, choice)
+		if err != nil {
+			if cerr := u.bookings.UpdateStatus(id, domain.StatusAwaitingPayment, domain.StatusCancelled); cerr == nil && u.waitlist != nil {
+				_ = u.waitlist.SlotFreed(input.ServiceID, input.StartAt, input.EndAt)
+			}
+			return 0, nil, err
+		}
+		payment.Localize(u.loc)
+	}
+	input.Localize(u.loc)
+	_ = u.notifier.Notify(bookingEvent(domain.EventBookingCreated, input))
 	_ = u.logger.Log("booking_created", input.CustomerName, now)
-	return id, nil
+	return id, payment, nil
 }
@@ -1,18 +1,53 @@
 package usecase
 
 import (
+	"time"
+
 	"be-golang/internal/domain"
 	"be-golang/internal/ports"
 )
 
 type BookingList struct {
 	bookings ports.BookingRepository
+	loc      *time.Location
 }
 
-func NewBookingList(b ports.BookingRepository) *BookingList {
-	return &BookingList{bookings: b}
+func NewBookingList(b ports.BookingRepository, loc *time.Location) *BookingList {
+	return &BookingList{bookings: b, loc: loc}
 }
 
 func (u *BookingList) Exec(limit int) ([]domain.Booking, error) {
-	return u.bookings.ListLatest(limit)
+	items, err := u.bookings.ListLatest(limit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Localize(u.loc)
+	}
+	return items, nil
+}
+
+type BookingGet struct {
+	bookings ports.BookingRepository
+	loc      *time.Location
+}
+
+func NewBookingGet(b ports.BookingRepository, loc *time.Location) *BookingGet {
+	return &BookingGet{bookings: b, loc: loc}
+}
+
+// Exec returns the booking with its line items and charges.
+func (u *BookingGet) Exec(id int64) (*domain.Booking, error) {
+	b, err := u.bookings.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if b.Items, err = u.bookings.ListItems(id); err != nil {
+		return nil, err
+	}
+	if b.Charges, err = u.bookings.ListCharges(id); err != nil {
+		return nil, err
+	}
+	b.Localize(u.loc)
+	return b, nil
 }
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"strconv"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+	"be-golang/internal/util"
+)
+
+type BookingReschedule struct {
+	bookings ports.BookingRepository
+	notifier ports.Notifier
+	logger   ports.Logger
+	schedule ports.ScheduleRepository
+	waitlist *WaitlistAdvance
+	capacity int
+	loc      *time.Location
+}
+
+func NewBookingReschedule(b ports.BookingRepository, n ports.Notifier, l ports.Logger, s ports.ScheduleRepository, w *WaitlistAdvance, capacity int, loc *time.Location) *BookingReschedule {
+	return &BookingReschedule{bookings: b, notifier: n, logger: l, schedule: s, waitlist: w, capacity: capacity, loc: loc}
+}
+
+// Exec moves the booking to date at the "HH:MM" time in the business
+// location, keeping its duration. The new start must lie in the future and
+// only pending or confirmed bookings can move.
+func (u *BookingReschedule) Exec(id int64, date time.Time, at string) (*domain.Booking, error) {
+	start, err := util.AtClock(date, at, u.loc)
+	if err != nil {
+		return nil, err
+	}
+	if !start.After(time.Now()) {
+		return nil, domain.Invalid("booking_date", "must_be_future")
+	}
+	current, err := u.bookings.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.CheckReschedulable(current.Status); err != nil {
+		return nil, err
+	}
+	end := start.Add(current.EndAt.Sub(current.StartAt))
+	if err := checkOpen(u.schedule, start, end, u.loc); err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	h, err := u.bookings.Reschedule(id, start, end, u.capacity, now)
+	if err != nil {
+		return nil, err
+	}
+	b, err := u.bookings.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if u.waitlist != nil {
+		_ = u.waitlist.SlotFreed(b.ServiceID, h.PrevStartAt, h.PrevEndAt)
+	}
+	b.Localize(u.loc)
+	h.Localize(u.loc)
+	_ = u.notifier.Notify(rescheduledEvent(*b, h))
+	_ = u.logger.Log("booking_rescheduled", strconv.FormatInt(id, 10)+": "+h.PrevStartAt.Format(time.RFC3339)+" -> "+h.NewStartAt.Format(time.RFC3339), now)
+	return b, nil
+}
+
+type BookingHistoryList struct {
+	bookings ports.BookingRepository
+	loc      *time.Location
+}
+
+func NewBookingHistoryList(b ports.BookingRepository, loc *time.Location) *BookingHistoryList {
+	return &BookingHistoryList{bookings: b, loc: loc}
+}
+
+func (u *BookingHistoryList) Exec(bookingID int64) ([]domain.BookingHistory, error) {
+	items, err := u.bookings.ListHistory(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Localize(u.loc)
+	}
+	return items, nil
+}
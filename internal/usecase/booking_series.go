@@ -0,0 +1,297 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+	"be-golang/internal/util"
+)
+
+type SeriesConflict struct {
+	BookingID int64
+	StartAt   time.Time
+	Reason    string
+}
+
+type SeriesResult struct {
+	SeriesID   int64
+	BookingIDs []int64
+	Conflicts  []SeriesConflict
+}
+
+type SeriesDetail struct {
+	Series      domain.BookingSeries
+	Occurrences []domain.Booking
+}
+
+type BookingSeriesCreate struct {
+	bookings ports.BookingRepository
+	services ports.ServiceRepository
+	schedule ports.ScheduleRepository
+	logger   ports.Logger
+	capacity int
+	loc      *time.Location
+}
+
+func NewBookingSeriesCreate(b ports.BookingRepository, s ports.ServiceRepository, sch ports.ScheduleRepository, l ports.Logger, capacity int, loc *time.Location) *BookingSeriesCreate {
+	return &BookingSeriesCreate{bookings: b, services: s, schedule: sch, logger: l, capacity: capacity, loc: loc}
+}
+
+// Exec expands rule from the first occurrence in input and books every
+// occurrence. The first occurrence must lie in the future. Occurrences that
+// are closed or full are reported as conflicts; unless skipConflicts is set
+// nothing is created when there are any.
+func (u *BookingSeriesCreate) Exec(input domain.Booking, rule string, skipConflicts bool) (SeriesResult, error) {
+	var res SeriesResult
+	rr, err := domain.ParseRecurrenceRule(rule)
+	if err != nil {
+		return res, err
+	}
+	first, err := util.AtClock(input.BookingDate, input.BookingTime, u.loc)
+	if err != nil {
+		return res, err
+	}
+	now := time.Now().UTC()
+	if !first.After(now) {
+		return res, domain.Invalid("booking_date", "must_be_future")
+	}
+	input.CustomerName = strings.TrimSpace(input.CustomerName)
+	input.CustomerPhone = strings.TrimSpace(input.CustomerPhone)
+	svc, err := u.services.GetByID(input.ServiceID)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && !svc.Bookable()) {
+		return res, domain.ErrInvalidService
+	}
+	if err != nil {
+		return res, err
+	}
+	duration := time.Duration(svc.DurationMinutes) * time.Minute
+	var items []domain.Booking
+	for _, start := range rr.Occurrences(first, u.loc) {
+		end := start.Add(duration)
+		reason, err := u.availability(start, end)
+		if err != nil {
+			return res, err
+		}
+		if reason != "" {
+			res.Conflicts = append(res.Conflicts, SeriesConflict{StartAt: start, Reason: reason})
+			continue
+		}
+		items = append(items, domain.Booking{
+			CustomerName:  input.CustomerName,
+			CustomerPhone: input.CustomerPhone,
+			ServiceID:     input.ServiceID,
+			StartAt:       start,
+			EndAt:         end,
+			Status:        domain.StatusPending,
+			CreatedAt:     now,
+		})
+	}
+	if len(items) == 0 || (len(res.Conflicts) > 0 && !skipConflicts) {
+		return res, domain.ErrSeriesConflicts
+	}
+	res.SeriesID, res.BookingIDs, err = u.bookings.CreateSeries(domain.BookingSeries{
+		CustomerName:  input.CustomerName,
+		CustomerPhone: input.CustomerPhone,
+		ServiceID:     input.ServiceID,
+		Rule:          rule,
+		CreatedAt:     now,
+	}, items, u.capacity)
+	if err != nil {
+		return res, err
+	}
+	_ = u.logger.Log("booking_series_created", strconv.FormatInt(res.SeriesID, 10)+": "+input.CustomerName, now)
+	return res, nil
+}
+
+func (u *BookingSeriesCreate) availability(start, end time.Time) (string, error) {
+	err := checkOpen(u.schedule, start, end, u.loc)
+	if errors.Is(err, domain.ErrClosed) {
+		return err.Error(), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if u.capacity > 0 {
+		taken, err := u.bookings.CountOverlapping(start, end, 0)
+		if err != nil {
+			return "", err
+		}
+		if taken >= u.capacity {
+			return domain.ErrSlotFull.Error(), nil
+		}
+	}
+	return "", nil
+}
+
+type BookingSeriesGet struct {
+	bookings ports.BookingRepository
+	loc      *time.Location
+}
+
+func NewBookingSeriesGet(b ports.BookingRepository, loc *time.Location) *BookingSeriesGet {
+	return &BookingSeriesGet{bookings: b, loc: loc}
+}
+
+func (u *BookingSeriesGet) Exec(id int64) (SeriesDetail, error) {
+	s, err := u.bookings.GetSeries(id)
+	if err != nil {
+		return SeriesDetail{}, err
+	}
+	items, err := u.bookings.ListBySeries(id)
+	if err != nil {
+		return SeriesDetail{}, err
+	}
+	for i := range items {
+		items[i].Localize(u.loc)
+	}
+	return SeriesDetail{Series: *s, Occurrences: items}, nil
+}
+
+// seriesTargets returns the still-open occurrences of b's series selected by
+// scope. Bookings outside a series are rejected for every scope; they are
+// changed through the single-booking endpoints.
+func seriesTargets(bookings ports.BookingRepository, b *domain.Booking, scope string) ([]domain.Booking, error) {
+	switch scope {
+	case domain.ScopeThis, domain.ScopeFollowing, domain.ScopeAll:
+	default:
+		return nil, domain.ErrInvalidScope
+	}
+	if b.SeriesID == nil {
+		return nil, domain.ErrNotInSeries
+	}
+	if scope == domain.ScopeThis {
+		return []domain.Booking{*b}, nil
+	}
+	items, err := bookings.ListBySeries(*b.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+	var out []domain.Booking
+	for _, it := range items {
+		if it.Status != domain.StatusPending && it.Status != domain.StatusConfirmed {
+			continue
+		}
+		if scope == domain.ScopeFollowing && it.StartAt.Before(b.StartAt) {
+			continue
+		}
+		out = append(out, it)
+	}
+	return out, nil
+}
+
+type BookingSeriesCancel struct {
+	bookings ports.BookingRepository
+	status   *BookingSetStatus
+}
+
+func NewBookingSeriesCancel(b ports.BookingRepository, s *BookingSetStatus) *BookingSeriesCancel {
+	return &BookingSeriesCancel{bookings: b, status: s}
+}
+
+// Exec cancels the occurrences selected by scope the way a single cancel
+// does: each one is charged under the cancellation policy, unless waiveFee
+// is set, and its deposit settled.
+func (u *BookingSeriesCancel) Exec(bookingID int64, scope string, waiveFee bool) ([]int64, error) {
+	b, err := u.bookings.GetByID(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := seriesTargets(u.bookings, b, scope)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int64
+	for i := range targets {
+		if err := u.status.apply(&targets[i], domain.StatusCancelled, waiveFee); err != nil {
+			if errors.Is(err, domain.ErrInvalidTransition) && scope != domain.ScopeThis {
+				continue
+			}
+			return ids, err
+		}
+		ids = append(ids, targets[i].ID)
+	}
+	return ids, nil
+}
+
+type BookingSeriesReschedule struct {
+	bookings ports.BookingRepository
+	notifier ports.Notifier
+	logger   ports.Logger
+	schedule ports.ScheduleRepository
+	waitlist *WaitlistAdvance
+	capacity int
+	loc      *time.Location
+}
+
+func NewBookingSeriesReschedule(b ports.BookingRepository, n ports.Notifier, l ports.Logger, s ports.ScheduleRepository, w *WaitlistAdvance, capacity int, loc *time.Location) *BookingSeriesReschedule {
+	return &BookingSeriesReschedule{bookings: b, notifier: n, logger: l, schedule: s, waitlist: w, capacity: capacity, loc: loc}
+}
+
+// Exec moves the selected occurrence to date at the given time. With a wider
+// scope every other selected occurrence is shifted by the same number of days
+// and gets the same wall clock time. The selected occurrence must move to the
+// future; other occurrences that would land in the past, or cannot move for
+// another reason, are reported as conflicts and left untouched.
+func (u *BookingSeriesReschedule) Exec(bookingID int64, scope string, date time.Time, at string) (SeriesResult, error) {
+	var res SeriesResult
+	target, err := util.AtClock(date, at, u.loc)
+	if err != nil {
+		return res, err
+	}
+	now := time.Now().UTC()
+	if !target.After(now) {
+		return res, domain.Invalid("booking_date", "must_be_future")
+	}
+	b, err := u.bookings.GetByID(bookingID)
+	if err != nil {
+		return res, err
+	}
+	targets, err := seriesTargets(u.bookings, b, scope)
+	if err != nil {
+		return res, err
+	}
+	if b.SeriesID != nil {
+		res.SeriesID = *b.SeriesID
+	}
+	shift := util.CalendarDate(date, time.UTC).Sub(util.CalendarDate(b.StartAt, u.loc))
+	for _, t := range targets {
+		day := util.CalendarDate(t.StartAt, u.loc).Add(shift)
+		start, _ := util.AtClock(day, at, u.loc)
+		end := start.Add(t.EndAt.Sub(t.StartAt))
+		if !start.After(now) {
+			res.Conflicts = append(res.Conflicts, SeriesConflict{BookingID: t.ID, StartAt: start, Reason: "must_be_future"})
+			continue
+		}
+		if err := checkOpen(u.schedule, start, end, u.loc); err != nil {
+			if !errors.Is(err, domain.ErrClosed) {
+				return res, err
+			}
+			res.Conflicts = append(res.Conflicts, SeriesConflict{BookingID: t.ID, StartAt: start, Reason: err.Error()})
+			continue
+		}
+		h, err := u.bookings.Reschedule(t.ID, start, end, u.capacity, time.Now().UTC())
+		if errors.Is(err, domain.ErrSlotFull) || errors.Is(err, domain.ErrBookingCancelled) || errors.Is(err, domain.ErrNotReschedulable) {
+			res.Conflicts = append(res.Conflicts, SeriesConflict{BookingID: t.ID, StartAt: start, Reason: err.Error()})
+			continue
+		}
+		if err != nil {
+			return res, err
+		}
+		if u.waitlist != nil {
+			_ = u.waitlist.SlotFreed(t.ServiceID, h.PrevStartAt, h.PrevEndAt)
+		}
+		moved := t
+		moved.StartAt, moved.EndAt = start, end
+		moved.Revision++
+		moved.Localize(u.loc)
+		h.Localize(u.loc)
+		_ = u.notifier.Notify(rescheduledEvent(moved, h))
+		_ = u.logger.Log("booking_rescheduled", strconv.FormatInt(t.ID, 10)+": "+h.PrevStartAt.Format(time.RFC3339)+" -> "+h.NewStartAt.Format(time.RFC3339), h.ChangedAt)
+		res.BookingIDs = append(res.BookingIDs, t.ID)
+	}
+	return res, nil
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"strconv"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+)
+
+type BookingSetStatus struct {
+	bookings ports.BookingRepository
+	notifier ports.Notifier
+	logger   ports.Logger
+	waitlist *WaitlistAdvance
+	charges  *CancellationCharge
+	invoices *InvoiceIssue
+	loc      *time.Location
+}
+
+func NewBookingSetStatus(b ports.BookingRepository, n ports.Notifier, l ports.Logger, w *WaitlistAdvance, cc *CancellationCharge, ii *InvoiceIssue, loc *time.Location) *BookingSetStatus {
+	return &BookingSetStatus{bookings: b, notifier: n, logger: l, waitlist: w, charges: cc, invoices: ii, loc: loc}
+}
+
+// Exec moves booking id to status. Cancelling and marking a no-show apply
+// the cancellation policy, unless waiveFee is set; the charge, if any, is
+// recorded with the status change and returned in Charges. The deposit is
+// settled afterwards; a refund that fails stays open for an admin to retry.
+// Completing a booking issues its invoice.
+func (u *BookingSetStatus) Exec(id int64, status string, waiveFee bool) (*domain.Booking, error) {
+	b, err := u.bookings.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.apply(b, status, waiveFee); err != nil {
+		return nil, err
+	}
+	b.Localize(u.loc)
+	return b, nil
+}
+
+// apply is Exec for a booking already loaded; b is updated in place.
+func (u *BookingSetStatus) apply(b *domain.Booking, status string, waiveFee bool) error {
+	settle := status == domain.StatusCancelled || status == domain.StatusNoShow
+	var charge *domain.BookingCharge
+	if settle {
+		var err error
+		if charge, err = u.charges.Quote(*b, status, waiveFee, time.Now().UTC()); err != nil {
+			return err
+		}
+	}
+	if err := transitionCharged(u.bookings, u.notifier, u.logger, u.loc, b, status, charge); err != nil {
+		return err
+	}
+	if settle {
+		if err := u.charges.Settle(*b, charge); err != nil {
+			_ = u.logger.Log("payment_settle_failed", strconv.FormatInt(b.ID, 10)+": "+err.Error(), time.Now().UTC())
+		}
+		if charge != nil {
+			b.Charges = []domain.BookingCharge{*charge}
+		}
+	}
+	if status == domain.StatusCompleted {
+		if _, err := u.invoices.Exec(b.ID); err != nil {
+			_ = u.logger.Log("invoice_failed", strconv.FormatInt(b.ID, 10)+": "+err.Error(), time.Now().UTC())
+		}
+	}
+	if status == domain.StatusCancelled && u.waitlist != nil {
+		_ = u.waitlist.SlotFreed(b.ServiceID, b.StartAt, b.EndAt)
+	}
+	return nil
+}
+
+// transition moves b to status if the state machine allows it and updates b
+// in place. The update is conditional on the status b was read with, so a
+// concurrent change makes it fail with domain.ErrInvalidTransition.
+func transition(bookings ports.BookingRepository, notifier ports.Notifier, logger ports.Logger, loc *time.Location, b *domain.Booking, status string) error {
+	return transitionCharged(bookings, notifier, logger, loc, b, status, nil)
+}
+
+// transitionCharged is transition that records charge, if not nil, along
+// with the status change and sets its ID.
+func transitionCharged(bookings ports.BookingRepository, notifier ports.Notifier, logger ports.Logger, loc *time.Location, b *domain.Booking, status string, charge *domain.BookingCharge) error {
+	if !domain.CanTransition(b.Status, status) {
+		return domain.ErrInvalidTransition
+	}
+	if charge == nil {
+		if err := bookings.UpdateStatus(b.ID, b.Status, status); err != nil {
+			return err
+		}
+	} else {
+		id, err := bookings.UpdateStatusCharged(b.ID, b.Status, status, *charge)
+		if err != nil {
+			return err
+		}
+		charge.ID = id
+		_ = logger.Log("booking_charged", strconv.FormatInt(b.ID, 10)+": "+charge.Kind+" "+strconv.FormatInt(charge.Amount, 10), time.Now().UTC())
+	}
+	_ = logger.Log("booking_status_changed", strconv.FormatInt(b.ID, 10)+": "+b.Status+" -> "+status, time.Now().UTC())
+	b.Status = status
+	b.Revision++
+	if typ, ok := domain.StatusEvents[status]; ok {
+		local := *b
+		local.Localize(loc)
+		_ = notifier.Notify(bookingEvent(typ, local))
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+	"be-golang/internal/util"
+)
+
+// The feed covers a window around today; calendar apps keep events they
+// have already seen, so older history is not needed.
+const (
+	calendarFeedPast   = 30 * 24 * time.Hour
+	calendarFeedFuture = 180 * 24 * time.Hour
+)
+
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+type CalendarTokenCreate struct {
+	tokens   ports.CalendarTokenRepository
+	services ports.ServiceRepository
+	logger   ports.Logger
+}
+
+func NewCalendarTokenCreate(t ports.CalendarTokenRepository, s ports.ServiceRepository, l ports.Logger) *CalendarTokenCreate {
+	return &CalendarTokenCreate{tokens: t, services: s, logger: l}
+}
+
+// Exec issues a feed token for userID, optionally limited to one service.
+// The plain token is only returned here.
+func (u *CalendarTokenCreate) Exec(userID int64, name string, serviceID *int64) (string, *domain.CalendarToken, error) {
+	if userID == 0 {
+		return "", nil, domain.ErrInvalidInput
+	}
+	if serviceID != nil {
+		if _, err := u.services.GetByID(*serviceID); err != nil {
+			return "", nil, domain.ErrInvalidService
+		}
+	}
+	token, err := util.RandomToken(24)
+	if err != nil {
+		return "", nil, err
+	}
+	t := domain.CalendarToken{
+		UserID:    userID,
+		Name:      name,
+		TokenHash: hashCalendarToken(token),
+		ServiceID: serviceID,
+		CreatedAt: time.Now().UTC(),
+	}
+	id, err := u.tokens.Create(t)
+	if err != nil {
+		return "", nil, err
+	}
+	t.ID = id
+	_ = u.logger.Log("calendar_token_created", strconv.FormatInt(userID, 10)+": "+strconv.FormatInt(id, 10), t.CreatedAt)
+	return token, &t, nil
+}
+
+type CalendarTokenList struct {
+	tokens ports.CalendarTokenRepository
+}
+
+func NewCalendarTokenList(t ports.CalendarTokenRepository) *CalendarTokenList {
+	return &CalendarTokenList{tokens: t}
+}
+
+func (u *CalendarTokenList) Exec(userID int64) ([]domain.CalendarToken, error) {
+	return u.tokens.ListByUser(userID)
+}
+
+type CalendarTokenRevoke struct {
+	tokens ports.CalendarTokenRepository
+	logger ports.Logger
+}
+
+func NewCalendarTokenRevoke(t ports.CalendarTokenRepository, l ports.Logger) *CalendarTokenRevoke {
+	return &CalendarTokenRevoke{tokens: t, logger: l}
+}
+
+func (u *CalendarTokenRevoke) Exec(userID, id int64) error {
+	now := time.Now().UTC()
+	if err := u.tokens.Revoke(id, userID, now); err != nil {
+		return err
+	}
+	_ = u.logger.Log("calendar_token_revoked", strconv.FormatInt(userID, 10)+": "+strconv.FormatInt(id, 10), now)
+	return nil
+}
+
+type CalendarFeed struct {
+	tokens   ports.CalendarTokenRepository
+	bookings ports.BookingRepository
+	loc      *time.Location
+}
+
+func NewCalendarFeed(t ports.CalendarTokenRepository, b ports.BookingRepository, loc *time.Location) *CalendarFeed {
+	return &CalendarFeed{tokens: t, bookings: b, loc: loc}
+}
+
+// Exec builds the feed for token. serviceID narrows the feed further when
+// the token itself is not limited to a service; 0 means no filter. The
+// token's owner only owns the token: bookings carry no staff assignment, so
+// there is nothing to scope the feed to beyond the service.
+func (u *CalendarFeed) Exec(token string, serviceID int64) (*util.ICalendar, error) {
+	t, err := u.tokens.GetByHash(hashCalendarToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if t.ServiceID != nil {
+		serviceID = *t.ServiceID
+	}
+	now := time.Now().UTC()
+	bookings, err := u.bookings.ListCalendar(now.Add(-calendarFeedPast), now.Add(calendarFeedFuture), serviceID)
+	if err != nil {
+		return nil, err
+	}
+	_ = u.tokens.Touch(t.ID, now)
+
+	cal := &util.ICalendar{
+		ProdID:   "-//be-golang//booking//EN",
+		Name:     "Booking",
+		Location: u.loc,
+	}
+	if t.Name != "" {
+		cal.Name = t.Name
+	}
+	for _, e := range bookings {
+		summary := e.CustomerName
+		if e.ServiceName != "" {
+			summary = e.ServiceName + " - " + e.CustomerName
+		}
+		cal.Events = append(cal.Events, util.ICalEvent{
+			UID:         domain.CalendarUID(e.ID),
+			Summary:     summary,
+			Description: "Booking #" + strconv.FormatInt(e.ID, 10) + "\nPhone: " + e.CustomerPhone + "\nStatus: " + e.Status,
+			Start:       e.StartAt,
+			End:         e.EndAt,
+			Sequence:    e.Revision,
+			Status:      domain.CalendarStatuses[e.Status],
+			Stamp:       now,
+		})
+	}
+	return cal, nil
+}
@@ -0,0 +1,182 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+)
+
+// CancellationPolicyResolve returns the policy that applies to a service:
+// its own, else the business policy, else the zero policy that charges
+// nothing.
+type CancellationPolicyResolve struct {
+	policies ports.CancellationPolicyRepository
+}
+
+func NewCancellationPolicyResolve(p ports.CancellationPolicyRepository) *CancellationPolicyResolve {
+	return &CancellationPolicyResolve{policies: p}
+}
+
+func (u *CancellationPolicyResolve) Exec(serviceID int64) (domain.CancellationPolicy, error) {
+	for _, id := range []int64{serviceID, 0} {
+		p, err := u.policies.Get(id)
+		if err == nil {
+			return *p, nil
+		}
+		if !errors.Is(err, domain.ErrNotFound) {
+			return domain.CancellationPolicy{}, err
+		}
+	}
+	return domain.CancellationPolicy{}, nil
+}
+
+type CancellationPolicyGet struct {
+	policies ports.CancellationPolicyRepository
+}
+
+func NewCancellationPolicyGet(p ports.CancellationPolicyRepository) *CancellationPolicyGet {
+	return &CancellationPolicyGet{policies: p}
+}
+
+// Exec returns the policy stored for serviceID, zero for the business. The
+// business policy defaults to the zero policy; a service without its own
+// policy is domain.ErrNotFound.
+func (u *CancellationPolicyGet) Exec(serviceID int64) (*domain.CancellationPolicy, error) {
+	p, err := u.policies.Get(serviceID)
+	if serviceID == 0 && errors.Is(err, domain.ErrNotFound) {
+		return &domain.CancellationPolicy{}, nil
+	}
+	return p, err
+}
+
+type CancellationPolicyList struct {
+	policies ports.CancellationPolicyRepository
+}
+
+func NewCancellationPolicyList(p ports.CancellationPolicyRepository) *CancellationPolicyList {
+	return &CancellationPolicyList{policies: p}
+}
+
+// Exec lists the service policies, without the business policy.
+func (u *CancellationPolicyList) Exec() ([]domain.CancellationPolicy, error) {
+	items, err := u.policies.List()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]domain.CancellationPolicy, 0, len(items))
+	for _, p := range items {
+		if p.ServiceID != 0 {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+type CancellationPolicySave struct {
+	policies ports.CancellationPolicyRepository
+	services ports.ServiceRepository
+	logger   ports.Logger
+}
+
+func NewCancellationPolicySave(p ports.CancellationPolicyRepository, s ports.ServiceRepository, l ports.Logger) *CancellationPolicySave {
+	return &CancellationPolicySave{policies: p, services: s, logger: l}
+}
+
+// Exec replaces the policy of p.ServiceID, zero for the business. The
+// service must exist; archived services may still get a policy so their
+// existing bookings follow it.
+func (u *CancellationPolicySave) Exec(p domain.CancellationPolicy) (*domain.CancellationPolicy, error) {
+	if p.ServiceID != 0 {
+		if _, err := u.services.GetByID(p.ServiceID); err != nil {
+			return nil, err
+		}
+	}
+	p.UpdatedAt = time.Now().UTC()
+	if err := u.policies.Save(p); err != nil {
+		return nil, err
+	}
+	_ = u.logger.Log("cancellation_policy_saved", "service "+strconv.FormatInt(p.ServiceID, 10), p.UpdatedAt)
+	return &p, nil
+}
+
+type CancellationPolicyDelete struct {
+	policies ports.CancellationPolicyRepository
+	logger   ports.Logger
+}
+
+func NewCancellationPolicyDelete(p ports.CancellationPolicyRepository, l ports.Logger) *CancellationPolicyDelete {
+	return &CancellationPolicyDelete{policies: p, logger: l}
+}
+
+func (u *CancellationPolicyDelete) Exec(serviceID int64) error {
+	if err := u.policies.Delete(serviceID); err != nil {
+		return err
+	}
+	_ = u.logger.Log("cancellation_policy_deleted", "service "+strconv.FormatInt(serviceID, 10), time.Now().UTC())
+	return nil
+}
+
+// CancellationCharge applies the cancellation policy to a booking that was
+// just cancelled or marked as a no-show.
+type CancellationCharge struct {
+	policies *CancellationPolicyResolve
+	payments *PaymentRefund
+}
+
+// NewCancellationCharge takes a nil pr when deposits are not collected.
+func NewCancellationCharge(p *CancellationPolicyResolve, pr *PaymentRefund) *CancellationCharge {
+	return &CancellationCharge{policies: p, payments: pr}
+}
+
+// Quote returns the fee owed if b, as read before the change, moves to
+// status at now, or nil when nothing is charged. Bookings that were never
+// secured (still awaiting payment) and waived changes are free. FromDeposit
+// is the part of the fee a paid deposit covers.
+func (u *CancellationCharge) Quote(b domain.Booking, status string, waive bool, now time.Time) (*domain.BookingCharge, error) {
+	if waive || b.Status == domain.StatusAwaitingPayment {
+		return nil, nil
+	}
+	policy, err := u.policies.Exec(b.ServiceID)
+	if err != nil {
+		return nil, err
+	}
+	kind, fee := policy.Fee(status, b.TotalPrice, b.StartAt, now)
+	if fee == 0 {
+		return nil, nil
+	}
+	c := domain.BookingCharge{
+		BookingID: b.ID,
+		Kind:      kind,
+		Percent:   policy.LateCancelFeePercent,
+		Amount:    fee,
+		CreatedAt: now,
+	}
+	if kind == domain.ChargeNoShow {
+		c.Percent = policy.NoShowFeePercent
+	}
+	if u.payments != nil {
+		held, err := u.payments.Held(b.ID)
+		if err != nil {
+			return nil, err
+		}
+		c.FromDeposit = min(fee, held)
+	}
+	return &c, nil
+}
+
+// Settle closes the deposit of b after the change was recorded: the part of
+// charge covered by the deposit is kept and the rest refunded. A nil charge
+// refunds the deposit in full.
+func (u *CancellationCharge) Settle(b domain.Booking, charge *domain.BookingCharge) error {
+	if u.payments == nil {
+		return nil
+	}
+	var fee int64
+	if charge != nil {
+		fee = charge.FromDeposit
+	}
+	return u.payments.Settle(b, fee)
+}
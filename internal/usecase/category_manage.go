@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"strconv"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+)
+
+type CategoryCreate struct {
+	categories ports.CategoryRepository
+	logger     ports.Logger
+}
+
+func NewCategoryCreate(c ports.CategoryRepository, l ports.Logger) *CategoryCreate {
+	return &CategoryCreate{categories: c, logger: l}
+}
+
+func (u *CategoryCreate) Exec(c domain.Category) (int64, error) {
+	id, err := u.categories.Create(c)
+	if err != nil {
+		return 0, err
+	}
+	_ = u.logger.Log("category_created", strconv.FormatInt(id, 10)+": "+c.Name, time.Now().UTC())
+	return id, nil
+}
+
+// CategoryUpdateInput holds the fields to change; nil fields are kept.
+type CategoryUpdateInput struct {
+	Name      *string
+	SortOrder *int
+}
+
+type CategoryUpdate struct {
+	categories ports.CategoryRepository
+	logger     ports.Logger
+}
+
+func NewCategoryUpdate(c ports.CategoryRepository, l ports.Logger) *CategoryUpdate {
+	return &CategoryUpdate{categories: c, logger: l}
+}
+
+func (u *CategoryUpdate) Exec(id int64, in CategoryUpdateInput) (*domain.Category, error) {
+	c, err := u.categories.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if in.Name != nil {
+		c.Name = *in.Name
+	}
+	if in.SortOrder != nil {
+		c.SortOrder = *in.SortOrder
+	}
+	if err := u.categories.Update(*c); err != nil {
+		return nil, err
+	}
+	_ = u.logger.Log("category_updated", strconv.FormatInt(id, 10)+": "+c.Name, time.Now().UTC())
+	return c, nil
+}
+
+type CategoryDelete struct {
+	categories ports.CategoryRepository
+	logger     ports.Logger
+}
+
+func NewCategoryDelete(c ports.CategoryRepository, l ports.Logger) *CategoryDelete {
+	return &CategoryDelete{categories: c, logger: l}
+}
+
+// Exec removes an empty category. Categories that still hold services,
+// archived ones included, fail with domain.ErrInUse.
+func (u *CategoryDelete) Exec(id int64) error {
+	if err := u.categories.Delete(id); err != nil {
+		return err
+	}
+	_ = u.logger.Log("category_deleted", strconv.FormatInt(id, 10), time.Now().UTC())
+	return nil
+}
+
+type CategoryList struct {
+	categories ports.CategoryRepository
+}
+
+func NewCategoryList(c ports.CategoryRepository) *CategoryList {
+	return &CategoryList{categories: c}
+}
+
+func (u *CategoryList) Exec() ([]domain.Category, error) {
+	return u.categories.List()
+}
+
+type CategoryReorder struct {
+	categories ports.CategoryRepository
+	logger     ports.Logger
+}
+
+func NewCategoryReorder(c ports.CategoryRepository, l ports.Logger) *CategoryReorder {
+	return &CategoryReorder{categories: c, logger: l}
+}
+
+// Exec sets the display order of categories to the order of ids.
+func (u *CategoryReorder) Exec(ids []int64) error {
+	if err := u.categories.Reorder(ids); err != nil {
+		return err
+	}
+	_ = u.logger.Log("categories_reordered", strconv.Itoa(len(ids))+" categories", time.Now().UTC())
+	return nil
+}
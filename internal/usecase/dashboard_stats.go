@@ -5,10 +5,12 @@ import (
 
 	"be-golang/internal/domain"
 	"be-golang/internal/ports"
+	"be-golang/internal/util"
 )
 
 type DashboardStats struct {
 	bookings ports.BookingRepository
+	loc      *time.Location
 }
 
 type DashboardResult struct {
@@ -16,12 +18,13 @@ type DashboardResult struct {
 	Latest     []domain.Booking
 }
 
-func NewDashboardStats(b ports.BookingRepository) *DashboardStats {
-	return &DashboardStats{bookings: b}
+func NewDashboardStats(b ports.BookingRepository, loc *time.Location) *DashboardStats {
+	return &DashboardStats{bookings: b, loc: loc}
 }
 
-func (u *DashboardStats) Exec(today time.Time) (DashboardResult, error) {
-	count, err := u.bookings.CountOnDate(today)
+func (u *DashboardStats) Exec(now time.Time) (DashboardResult, error) {
+	start, end := util.DayBounds(now, u.loc)
+	count, err := u.bookings.CountCreatedBetween(start, end)
 	if err != nil {
 		return DashboardResult{}, err
 	}
@@ -29,5 +32,8 @@ func (u *DashboardStats) Exec(today time.Time) (DashboardResult, error) {
 	if err != nil {
 		return DashboardResult{}, err
 	}
+	for i := range latest {
+		latest[i].Localize(u.loc)
+	}
 	return DashboardResult{TotalToday: count, Latest: latest}, nil
 }
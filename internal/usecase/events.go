@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/util"
+)
+
+func newEvent(typ string, data any) domain.Event {
+	id, _ := util.RandomToken(12)
+	return domain.Event{
+		ID:            "evt_" + id,
+		Type:          typ,
+		OccurredAt:    time.Now().UTC(),
+		SchemaVersion: domain.EventSchemaVersion,
+		Data:          data,
+	}
+}
+
+func bookingEvent(typ string, b domain.Booking) domain.Event {
+	return newEvent(typ, domain.NewBookingData(b))
+}
+
+func rescheduledEvent(b domain.Booking, h domain.BookingHistory) domain.Event {
+	return newEvent(domain.EventBookingRescheduled, domain.BookingRescheduledData{
+		BookingData:     domain.NewBookingData(b),
+		PreviousStartAt: h.PrevStartAt,
+		PreviousEndAt:   h.PrevEndAt,
+	})
+}
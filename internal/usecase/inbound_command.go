@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"strconv"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+)
+
+type InboundCommand struct {
+	messages ports.InboundMessageRepository
+	bookings ports.BookingRepository
+	status   *BookingSetStatus
+	optOuts  ports.MessageRepository
+	logger   ports.Logger
+}
+
+func NewInboundCommand(m ports.InboundMessageRepository, b ports.BookingRepository, s *BookingSetStatus, o ports.MessageRepository, l ports.Logger) *InboundCommand {
+	return &InboundCommand{messages: m, bookings: b, status: s, optOuts: o, logger: l}
+}
+
+// Exec applies a confirm, cancel or opt-out command from a messaging channel. The
+// command comes from m.Command or, when empty, from a reply keyword in m.Text.
+// A message ID that was already handled returns the stored outcome with
+// replayed set instead of running the command again. Rejections such as an
+// unknown booking are stored and returned in the message; only internal
+// errors are returned as err, and those leave the message open for a retry.
+func (u *InboundCommand) Exec(m domain.InboundMessage) (msg *domain.InboundMessage, replayed bool, err error) {
+	if m.MessageID == "" {
+		return nil, false, domain.ErrInvalidInput
+	}
+	if m.Command == "" {
+		m.Command = domain.ParseInboundKeyword(m.Text)
+	}
+	m.Status = domain.InboundProcessing
+	m.Error = ""
+	m.CreatedAt = time.Now().UTC()
+	created, err := u.messages.Record(m)
+	if err != nil {
+		return nil, false, err
+	}
+	if !created {
+		prev, err := u.messages.Get(m.MessageID)
+		if err != nil {
+			return nil, false, err
+		}
+		return prev, true, nil
+	}
+
+	applyErr := u.apply(m)
+	m.Status = domain.InboundApplied
+	switch {
+	case applyErr == nil:
+	case domain.KindOf(applyErr) != domain.KindInternal:
+		m.Status = domain.InboundRejected
+		m.Error = domain.CodeOf(applyErr)
+	default:
+		m.Status = domain.InboundFailed
+		m.Error = "internal_error"
+	}
+	now := time.Now().UTC()
+	m.ProcessedAt = &now
+	if err := u.messages.Finish(m.MessageID, m.Status, m.Error, now); err != nil {
+		return nil, false, err
+	}
+	detail := m.Source + " " + m.MessageID + ": " + m.Command + " booking " + strconv.FormatInt(m.BookingID, 10) + " -> " + m.Status
+	if m.Error != "" {
+		detail += " (" + m.Error + ")"
+	}
+	_ = u.logger.Log("inbound_message", detail, now)
+	if m.Status == domain.InboundFailed {
+		return nil, false, applyErr
+	}
+	return &m, false, nil
+}
+
+func (u *InboundCommand) apply(m domain.InboundMessage) error {
+	var status string
+	switch m.Command {
+	case domain.InboundConfirm:
+		status = domain.StatusConfirmed
+	case domain.InboundCancel:
+		status = domain.StatusCancelled
+	case domain.InboundOptOut:
+		phone := domain.NormalizePhone(m.Phone)
+		if phone == "" {
+			return domain.ErrInvalidInput
+		}
+		return u.optOuts.OptOut(domain.MessageOptOut{Phone: phone, Reason: "reply:" + m.Source, CreatedAt: time.Now().UTC()})
+	default:
+		return domain.ErrUnknownCommand
+	}
+	if m.BookingID <= 0 || m.Phone == "" {
+		return domain.ErrInvalidInput
+	}
+	b, err := u.bookings.GetByID(m.BookingID)
+	if err != nil {
+		return err
+	}
+	// Only the customer who made the booking may act on it; a mismatch looks
+	// the same as an unknown booking.
+	if domain.NormalizePhone(b.CustomerPhone) != domain.NormalizePhone(m.Phone) {
+		return domain.ErrNotFound
+	}
+	// A reply cannot stand in for the deposit; the payment callback
+	// confirms these bookings.
+	if status == domain.StatusConfirmed && b.Status == domain.StatusAwaitingPayment {
+		return domain.ErrPaymentRequired
+	}
+	_, err = u.status.Exec(m.BookingID, status, false)
+	return err
+}
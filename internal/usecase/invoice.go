@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+	"be-golang/internal/util"
+)
+
+func invoiceEvent(inv domain.Invoice, downloadURL string) domain.Event {
+	data := domain.NewInvoiceData(inv)
+	data.TotalFormatted = util.FormatIDR(inv.Total)
+	data.DownloadURL = downloadURL
+	return newEvent(domain.EventInvoiceIssued, data)
+}
+
+// InvoiceLinks signs download links for invoice PDFs, so customers can
+// fetch theirs from a message without an account.
+type InvoiceLinks struct {
+	secret  []byte
+	baseURL string
+	ttl     time.Duration
+}
+
+// NewInvoiceLinks builds links under baseURL, the public address of this
+// server, that stay valid for ttl.
+func NewInvoiceLinks(secret, baseURL string, ttl time.Duration) *InvoiceLinks {
+	return &InvoiceLinks{secret: []byte(secret), baseURL: strings.TrimRight(baseURL, "/"), ttl: ttl}
+}
+
+// URL returns the download link of a booking's invoice, valid from now.
+func (l *InvoiceLinks) URL(bookingID int64, now time.Time) string {
+	expires := now.Add(l.ttl).Unix()
+	return l.baseURL + "/invoices/" + strconv.FormatInt(bookingID, 10) + ".pdf?expires=" +
+		strconv.FormatInt(expires, 10) + "&signature=" + l.sign(bookingID, expires)
+}
+
+// Verify checks a link's signature and expiry; a bad or expired link is
+// domain.ErrInvalidInvoiceLink.
+func (l *InvoiceLinks) Verify(bookingID, expires int64, signature string, now time.Time) error {
+	if now.Unix() > expires || !hmac.Equal([]byte(signature), []byte(l.sign(bookingID, expires))) {
+		return domain.ErrInvalidInvoiceLink
+	}
+	return nil
+}
+
+func (l *InvoiceLinks) sign(bookingID, expires int64) string {
+	mac := hmac.New(sha256.New, l.secret)
+	mac.Write([]byte("invoice:" + strconv.FormatInt(bookingID, 10) + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// loadInvoice returns the invoice of a booking with its line items.
+func loadInvoice(invoices ports.InvoiceRepository, bookings ports.BookingRepository, bookingID int64) (*domain.Invoice, error) {
+	inv, err := invoices.GetByBooking(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := bookings.ListItems(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	inv.SetItems(items)
+	return inv, nil
+}
+
+// InvoiceIssue bills completed and fully paid bookings. Each booking gets
+// one invoice; its number is taken when it is first issued.
+type InvoiceIssue struct {
+	invoices   ports.InvoiceRepository
+	bookings   ports.BookingRepository
+	payments   ports.PaymentRepository
+	links      *InvoiceLinks
+	notifier   ports.Notifier
+	logger     ports.Logger
+	prefix     string
+	taxPercent int
+	loc        *time.Location
+}
+
+// NewInvoiceIssue takes a nil p when deposits are not collected, and a nil
+// links when invoice_issued carries no download link. Numbers start with
+// prefix, and prices are taken to include tax at taxPercent.
+func NewInvoiceIssue(i ports.InvoiceRepository, b ports.BookingRepository, p ports.PaymentRepository, links *InvoiceLinks, n ports.Notifier, l ports.Logger, prefix string, taxPercent int, loc *time.Location) *InvoiceIssue {
+	return &InvoiceIssue{invoices: i, bookings: b, payments: p, links: links, notifier: n, logger: l, prefix: prefix, taxPercent: taxPercent, loc: loc}
+}
+
+// Exec returns the invoice of booking id, issuing it first if the booking
+// has none yet and domain.InvoiceDue allows it. Other bookings are
+// domain.ErrInvoiceUnavailable. A new invoice is announced with
+// invoice_issued.
+func (u *InvoiceIssue) Exec(bookingID int64) (*domain.Invoice, error) {
+	inv, err := loadInvoice(u.invoices, u.bookings, bookingID)
+	if err == nil {
+		inv.Localize(u.loc)
+		return inv, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	b, err := u.bookings.GetByID(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	deposit, err := u.deposit(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.InvoiceDue(*b, deposit) {
+		return nil, domain.ErrInvoiceUnavailable
+	}
+	items, err := u.bookings.ListItems(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	issued := domain.NewInvoice(*b, items, deposit, u.taxPercent, now)
+	issued.Year = now.In(u.loc).Year()
+	if err := u.invoices.Create(&issued, u.prefix); err != nil {
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			// Issued concurrently; return the one that won.
+			return u.Exec(bookingID)
+		}
+		return nil, err
+	}
+	issued.Localize(u.loc)
+	var link string
+	if u.links != nil {
+		link = u.links.URL(bookingID, now)
+	}
+	_ = u.notifier.Notify(invoiceEvent(issued, link))
+	_ = u.logger.Log("invoice_issued", issued.Number+" booking "+strconv.FormatInt(bookingID, 10), now)
+	return &issued, nil
+}
+
+// deposit sums what was paid online for the booking and not refunded.
+func (u *InvoiceIssue) deposit(bookingID int64) (int64, error) {
+	if u.payments == nil {
+		return 0, nil
+	}
+	payments, err := u.payments.ListByBooking(bookingID)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, p := range payments {
+		switch p.Status {
+		case domain.PaymentPaid:
+			total += p.Amount
+		case domain.PaymentRefunded:
+			total += p.Amount - p.RefundedAmount
+		}
+	}
+	return total, nil
+}
+
+// InvoiceList returns the invoices of a year in number order, without line
+// items. Year zero is the current year in the business timezone.
+type InvoiceList struct {
+	invoices ports.InvoiceRepository
+	loc      *time.Location
+}
+
+func NewInvoiceList(i ports.InvoiceRepository, loc *time.Location) *InvoiceList {
+	return &InvoiceList{invoices: i, loc: loc}
+}
+
+func (u *InvoiceList) Exec(year int) ([]domain.Invoice, error) {
+	if year == 0 {
+		year = time.Now().In(u.loc).Year()
+	}
+	items, err := u.invoices.ListByYear(year)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Localize(u.loc)
+	}
+	return items, nil
+}
+
+// InvoicePDF renders invoices that have been issued.
+type InvoicePDF struct {
+	invoices ports.InvoiceRepository
+	bookings ports.BookingRepository
+	renderer ports.InvoiceRenderer
+	loc      *time.Location
+}
+
+func NewInvoicePDF(i ports.InvoiceRepository, b ports.BookingRepository, r ports.InvoiceRenderer, loc *time.Location) *InvoicePDF {
+	return &InvoicePDF{invoices: i, bookings: b, renderer: r, loc: loc}
+}
+
+func (u *InvoicePDF) Render(inv domain.Invoice) ([]byte, error) {
+	inv.Localize(u.loc)
+	return u.renderer.Render(inv)
+}
+
+// Exec renders the invoice already issued for a booking.
+func (u *InvoicePDF) Exec(bookingID int64) (*domain.Invoice, []byte, error) {
+	inv, err := loadInvoice(u.invoices, u.bookings, bookingID)
+	if err != nil {
+		return nil, nil, err
+	}
+	inv.Localize(u.loc)
+	pdf, err := u.renderer.Render(*inv)
+	if err != nil {
+		return nil, nil, err
+	}
+	return inv, pdf, nil
+}
+
+// Attachments returns the invoice PDF for invoice_issued events, so the
+// notifier can send it along. The invoice is read again when the event is
+// delivered, which also covers events held back by quiet hours.
+func (u *InvoicePDF) Attachments(e domain.Event) ([]domain.Attachment, error) {
+	d, ok := e.Data.(domain.InvoiceData)
+	if !ok || e.Type != domain.EventInvoiceIssued {
+		return nil, nil
+	}
+	inv, err := loadInvoice(u.invoices, u.bookings, d.BookingID)
+	if err != nil {
+		return nil, err
+	}
+	pdf, err := u.Render(*inv)
+	if err != nil {
+		return nil, err
+	}
+	return []domain.Attachment{{
+		Name:        InvoiceFilename(inv.Number),
+		ContentType: "application/pdf",
+		Data:        pdf,
+	}}, nil
+}
+
+// InvoiceFilename turns an invoice number into a file name, e.g.
+// "INV-2026-00042.pdf".
+func InvoiceFilename(number string) string {
+	return strings.NewReplacer("/", "-", "\\", "-", `"`, "").Replace(number) + ".pdf"
+}
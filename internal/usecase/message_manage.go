@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"strings"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+)
+
+const messageListLimit = 100
+
+type MessageList struct {
+	messages ports.MessageRepository
+}
+
+func NewMessageList(m ports.MessageRepository) *MessageList {
+	return &MessageList{messages: m}
+}
+
+func (u *MessageList) Exec() ([]domain.OutboundMessage, error) {
+	return u.messages.ListRecent(messageListLimit)
+}
+
+// gatewayStatuses maps the delivery states reported by common gateways to
+// ours.
+var gatewayStatuses = map[string]string{
+	"queued":      domain.MessageQueued,
+	"pending":     domain.MessageQueued,
+	"sent":        domain.MessageSent,
+	"delivered":   domain.MessageDelivered,
+	"read":        domain.MessageRead,
+	"failed":      domain.MessageFailed,
+	"undelivered": domain.MessageFailed,
+	"rejected":    domain.MessageFailed,
+	"error":       domain.MessageFailed,
+}
+
+type MessageStatusUpdate struct {
+	messages ports.MessageRepository
+}
+
+func NewMessageStatusUpdate(m ports.MessageRepository) *MessageStatusUpdate {
+	return &MessageStatusUpdate{messages: m}
+}
+
+// Exec records a provider delivery callback. Callbacks that would move the
+// message backwards, e.g. "sent" arriving after "read", are ignored.
+func (u *MessageStatusUpdate) Exec(providerID, status, errText string) (*domain.OutboundMessage, error) {
+	mapped, ok := gatewayStatuses[strings.ToLower(strings.TrimSpace(status))]
+	if providerID == "" || !ok {
+		return nil, domain.ErrInvalidInput
+	}
+	m, err := u.messages.GetByProviderID(providerID)
+	if err != nil {
+		return nil, err
+	}
+	if !domain.CanAdvanceMessage(m.Status, mapped) {
+		return m, nil
+	}
+	now := time.Now().UTC()
+	if err := u.messages.UpdateStatus(m.ID, mapped, errText, now); err != nil {
+		return nil, err
+	}
+	m.Status, m.Error, m.UpdatedAt = mapped, errText, now
+	return m, nil
+}
+
+type MessageOptOutList struct {
+	messages ports.MessageRepository
+}
+
+func NewMessageOptOutList(m ports.MessageRepository) *MessageOptOutList {
+	return &MessageOptOutList{messages: m}
+}
+
+func (u *MessageOptOutList) Exec() ([]domain.MessageOptOut, error) {
+	return u.messages.ListOptOuts()
+}
+
+type MessageOptOutCreate struct {
+	messages ports.MessageRepository
+	logger   ports.Logger
+}
+
+func NewMessageOptOutCreate(m ports.MessageRepository, l ports.Logger) *MessageOptOutCreate {
+	return &MessageOptOutCreate{messages: m, logger: l}
+}
+
+func (u *MessageOptOutCreate) Exec(phone, reason string) (*domain.MessageOptOut, error) {
+	o := domain.MessageOptOut{Phone: domain.NormalizePhone(phone), Reason: reason, CreatedAt: time.Now().UTC()}
+	if o.Phone == "" {
+		return nil, domain.ErrInvalidInput
+	}
+	if err := u.messages.OptOut(o); err != nil {
+		return nil, err
+	}
+	_ = u.logger.Log("message_opt_out", o.Phone, o.CreatedAt)
+	return &o, nil
+}
+
+type MessageOptOutDelete struct {
+	messages ports.MessageRepository
+	logger   ports.Logger
+}
+
+func NewMessageOptOutDelete(m ports.MessageRepository, l ports.Logger) *MessageOptOutDelete {
+	return &MessageOptOutDelete{messages: m, logger: l}
+}
+
+func (u *MessageOptOutDelete) Exec(phone string) error {
+	p := domain.NormalizePhone(phone)
+	if p == "" {
+		return domain.ErrInvalidInput
+	}
+	if err := u.messages.OptIn(p); err != nil {
+		return err
+	}
+	_ = u.logger.Log("message_opt_in", p, time.Now().UTC())
+	return nil
+}
@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"be-golang/internal/adapter/messaging/fake"
+	"be-golang/internal/adapter/notification/message"
+	"be-golang/internal/domain"
+)
+
+// memMessages is an in-memory ports.MessageRepository.
+type memMessages struct {
+	mu       sync.Mutex
+	log      []domain.OutboundMessage
+	optedOut map[string]domain.MessageOptOut
+}
+
+func newMemMessages() *memMessages {
+	return &memMessages{optedOut: map[string]domain.MessageOptOut{}}
+}
+
+func (r *memMessages) Create(m domain.OutboundMessage) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m.ID = int64(len(r.log) + 1)
+	r.log = append(r.log, m)
+	return m.ID, nil
+}
+
+func (r *memMessages) Finish(id int64, status, providerID, errText string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := &r.log[id-1]
+	m.Status, m.ProviderID, m.Error, m.UpdatedAt = status, providerID, errText, at
+	return nil
+}
+
+func (r *memMessages) GetByProviderID(providerID string) (*domain.OutboundMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, m := range r.log {
+		if m.ProviderID == providerID {
+			return &m, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *memMessages) UpdateStatus(id int64, status, errText string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m := &r.log[id-1]
+	m.Status, m.Error, m.UpdatedAt = status, errText, at
+	return nil
+}
+
+func (r *memMessages) ListRecent(limit int) ([]domain.OutboundMessage, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.OutboundMessage(nil), r.log...), nil
+}
+
+func (r *memMessages) IsOptedOut(phone string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.optedOut[phone]
+	return ok, nil
+}
+
+func (r *memMessages) OptOut(o domain.MessageOptOut) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.optedOut[o.Phone] = o
+	return nil
+}
+
+func (r *memMessages) OptIn(phone string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.optedOut, phone)
+	return nil
+}
+
+func (r *memMessages) ListOptOuts() ([]domain.MessageOptOut, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.MessageOptOut
+	for _, o := range r.optedOut {
+		out = append(out, o)
+	}
+	return out, nil
+}
+
+// memInbound is an in-memory ports.InboundMessageRepository.
+type memInbound struct {
+	seen map[string]domain.InboundMessage
+}
+
+func (r *memInbound) Record(m domain.InboundMessage) (bool, error) {
+	if _, ok := r.seen[m.MessageID]; ok {
+		return false, nil
+	}
+	r.seen[m.MessageID] = m
+	return true, nil
+}
+
+func (r *memInbound) Get(messageID string) (*domain.InboundMessage, error) {
+	m, ok := r.seen[messageID]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &m, nil
+}
+
+func (r *memInbound) Finish(messageID, status, errCode string, at time.Time) error {
+	m := r.seen[messageID]
+	m.Status, m.Error, m.ProcessedAt = status, errCode, &at
+	r.seen[messageID] = m
+	return nil
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Log(action, detail string, at time.Time) error { return nil }
+
+func confirmedEvent(id, phone string) domain.Event {
+	return domain.Event{
+		ID:         id,
+		Type:       domain.EventBookingConfirmed,
+		OccurredAt: time.Now().UTC(),
+		Data: domain.BookingData{
+			BookingID:     7,
+			CustomerName:  "Budi",
+			CustomerPhone: phone,
+			StartAt:       time.Date(2026, 5, 10, 3, 0, 0, 0, time.UTC),
+			EndAt:         time.Date(2026, 5, 10, 4, 0, 0, 0, time.UTC),
+			Status:        domain.StatusConfirmed,
+		},
+	}
+}
+
+func TestMessageOptOutAndStatusCallbacks(t *testing.T) {
+	repo := newMemMessages()
+	provider := fake.New()
+	n, err := message.New(provider, repo, message.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	updates := NewMessageStatusUpdate(repo)
+
+	if err := n.Notify(confirmedEvent("evt-1", "0812-3456-789")); err != nil {
+		t.Fatal(err)
+	}
+	sent := provider.Sent()
+	if len(sent) != 1 || sent[0].To != "628123456789" {
+		t.Fatalf("sent = %+v, want one message to 628123456789", sent)
+	}
+	providerID := sent[0].ID
+
+	// Callbacks arrive out of order; the status only moves forward and a
+	// late failure does not undo a delivery.
+	for _, step := range []struct{ status, want string }{
+		{"sent", domain.MessageSent},
+		{"read", domain.MessageRead},
+		{"delivered", domain.MessageRead},
+		{"failed", domain.MessageRead},
+	} {
+		m, err := updates.Exec(providerID, step.status, "")
+		if err != nil {
+			t.Fatalf("callback %q: %v", step.status, err)
+		}
+		if m.Status != step.want {
+			t.Errorf("after %q: status = %q, want %q", step.status, m.Status, step.want)
+		}
+	}
+	if _, err := updates.Exec(providerID, "bounced", ""); err != domain.ErrInvalidInput {
+		t.Errorf("unknown status: err = %v, want invalid input", err)
+	}
+
+	// A customer replying STOP is opted out and gets nothing further.
+	inbound := NewInboundCommand(&memInbound{seen: map[string]domain.InboundMessage{}}, nil, nil, repo, nopLogger{})
+	in, _, err := inbound.Exec(domain.InboundMessage{MessageID: "wa-1", Source: "whatsapp", Phone: "+62 812 3456 789", Text: "stop."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if in.Command != domain.InboundOptOut || in.Status != domain.InboundApplied {
+		t.Fatalf("inbound = %+v, want an applied opt-out", in)
+	}
+	if err := n.Notify(confirmedEvent("evt-2", "08123456789")); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(provider.Sent()); got != 1 {
+		t.Errorf("opted-out phone was messaged: %d sent", got)
+	}
+	if got := len(repo.log); got != 1 {
+		t.Errorf("opted-out message was logged: %d entries", got)
+	}
+
+	// Opting back in resumes messages.
+	if err := NewMessageOptOutDelete(repo, nopLogger{}).Exec("08123456789"); err != nil {
+		t.Fatal(err)
+	}
+	if err := n.Notify(confirmedEvent("evt-3", "08123456789")); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(provider.Sent()); got != 2 {
+		t.Errorf("after opt-in: %d sent, want 2", got)
+	}
+}
+
+func TestMessageSendFailure(t *testing.T) {
+	repo := newMemMessages()
+	provider := fake.New()
+	n, err := message.New(provider, repo, message.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider.FailNext(nil)
+	if err := n.Notify(confirmedEvent("evt-1", "08123456789")); err == nil {
+		t.Fatal("want the send error")
+	}
+	if m := repo.log[0]; m.Status != domain.MessageFailed || m.Error == "" {
+		t.Errorf("logged %+v, want failed with the error", m)
+	}
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+	"be-golang/internal/util"
+)
+
+// preferenceKey validates scope and normalizes the customer phone used as
+// subject. The business preference has an empty subject.
+func preferenceKey(scope, subject string) (string, error) {
+	switch scope {
+	case domain.PreferenceBusiness:
+		return "", nil
+	case domain.PreferenceCustomer:
+		if p := domain.NormalizePhone(subject); p != "" {
+			return p, nil
+		}
+	}
+	return "", domain.ErrInvalidInput
+}
+
+func validatePreference(p domain.NotificationPreference) error {
+	for _, c := range p.Channels {
+		if !contains(domain.NotifyChannels, c) {
+			return domain.ErrInvalidChannel
+		}
+	}
+	for _, t := range p.EventTypes {
+		if !contains(domain.EventTypes, t) {
+			return domain.ErrInvalidEventType
+		}
+	}
+	if (p.QuietStart == "") != (p.QuietEnd == "") {
+		return domain.ErrInvalidQuietHours
+	}
+	if p.QuietStart != "" {
+		if _, _, err := util.ParseClock(p.QuietStart); err != nil {
+			return err
+		}
+		if _, _, err := util.ParseClock(p.QuietEnd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+type NotificationPreferenceGet struct {
+	prefs ports.NotificationPreferenceRepository
+}
+
+func NewNotificationPreferenceGet(p ports.NotificationPreferenceRepository) *NotificationPreferenceGet {
+	return &NotificationPreferenceGet{prefs: p}
+}
+
+// Exec returns the stored preference or, when none is stored, the default
+// that allows everything with no quiet hours.
+func (u *NotificationPreferenceGet) Exec(scope, subject string) (*domain.NotificationPreference, error) {
+	key, err := preferenceKey(scope, subject)
+	if err != nil {
+		return nil, err
+	}
+	p, err := u.prefs.Get(scope, key)
+	if errors.Is(err, domain.ErrNotFound) {
+		return &domain.NotificationPreference{Scope: scope, Subject: key}, nil
+	}
+	return p, err
+}
+
+type NotificationPreferenceList struct {
+	prefs ports.NotificationPreferenceRepository
+}
+
+func NewNotificationPreferenceList(p ports.NotificationPreferenceRepository) *NotificationPreferenceList {
+	return &NotificationPreferenceList{prefs: p}
+}
+
+func (u *NotificationPreferenceList) Exec(scope string) ([]domain.NotificationPreference, error) {
+	return u.prefs.List(scope)
+}
+
+type NotificationPreferenceSave struct {
+	prefs  ports.NotificationPreferenceRepository
+	logger ports.Logger
+}
+
+func NewNotificationPreferenceSave(p ports.NotificationPreferenceRepository, l ports.Logger) *NotificationPreferenceSave {
+	return &NotificationPreferenceSave{prefs: p, logger: l}
+}
+
+// Exec replaces the preference identified by p.Scope and p.Subject.
+func (u *NotificationPreferenceSave) Exec(p domain.NotificationPreference) (*domain.NotificationPreference, error) {
+	key, err := preferenceKey(p.Scope, p.Subject)
+	if err != nil {
+		return nil, err
+	}
+	p.Subject = key
+	if err := validatePreference(p); err != nil {
+		return nil, err
+	}
+	p.UpdatedAt = time.Now().UTC()
+	if err := u.prefs.Save(p); err != nil {
+		return nil, err
+	}
+	_ = u.logger.Log("notification_preference_saved", p.Scope+" "+p.Subject, p.UpdatedAt)
+	return &p, nil
+}
+
+type NotificationPreferenceDelete struct {
+	prefs  ports.NotificationPreferenceRepository
+	logger ports.Logger
+}
+
+func NewNotificationPreferenceDelete(p ports.NotificationPreferenceRepository, l ports.Logger) *NotificationPreferenceDelete {
+	return &NotificationPreferenceDelete{prefs: p, logger: l}
+}
+
+func (u *NotificationPreferenceDelete) Exec(scope, subject string) error {
+	key, err := preferenceKey(scope, subject)
+	if err != nil {
+		return err
+	}
+	if err := u.prefs.Delete(scope, key); err != nil {
+		return err
+	}
+	_ = u.logger.Log("notification_preference_deleted", scope+" "+key, time.Now().UTC())
+	return nil
+}
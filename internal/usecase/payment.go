@@ -0,0 +1,348 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+)
+
+func paymentEvent(typ string, p domain.Payment) domain.Event {
+	return newEvent(typ, domain.NewPaymentData(p))
+}
+
+// PaymentStart asks the gateway to collect the deposit of a new booking.
+type PaymentStart struct {
+	payments ports.PaymentRepository
+	gateway  ports.PaymentGateway
+	logger   ports.Logger
+	policy   domain.DepositPolicy
+	method   string
+	ttl      time.Duration
+}
+
+// NewPaymentStart collects deposits by policy; method is used when the
+// customer does not pick one, and ttl is how long a payment stays open.
+func NewPaymentStart(p ports.PaymentRepository, g ports.PaymentGateway, l ports.Logger, policy domain.DepositPolicy, method string, ttl time.Duration) *PaymentStart {
+	return &PaymentStart{payments: p, gateway: g, logger: l, policy: policy, method: method, ttl: ttl}
+}
+
+// Deposit returns the amount to collect for a booking total; zero means
+// the booking needs no payment.
+func (u *PaymentStart) Deposit(total int64) int64 {
+	return u.policy.Amount(total)
+}
+
+// Exec records a pending payment for b and creates its intent with the
+// gateway. The payment expires after the TTL or when the booking starts,
+// whichever comes first. A gateway failure is returned as
+// domain.ErrPaymentFailed.
+func (u *PaymentStart) Exec(b domain.Booking, amount int64, choice domain.PaymentChoice) (*domain.Payment, error) {
+	now := time.Now().UTC()
+	p := domain.Payment{
+		BookingID: b.ID,
+		Provider:  u.gateway.Name(),
+		Method:    choice.Method,
+		Bank:      choice.Bank,
+		Amount:    amount,
+		Status:    domain.PaymentPending,
+		ExpiresAt: now.Add(u.ttl),
+		CreatedAt: now,
+	}
+	if p.Method == "" {
+		p.Method = u.method
+	}
+	if b.StartAt.Before(p.ExpiresAt) {
+		p.ExpiresAt = b.StartAt
+	}
+	id, err := u.payments.Create(p)
+	if err != nil {
+		return nil, err
+	}
+	p.ID = id
+	in, err := u.gateway.CreateIntent(p, b)
+	if err != nil {
+		_, _ = u.payments.MarkExpired(id)
+		_ = u.logger.Log("payment_failed", strconv.FormatInt(id, 10)+": "+err.Error(), now)
+		return nil, domain.ErrPaymentFailed.Wrap(err)
+	}
+	if err := u.payments.SetIntent(id, in); err != nil {
+		return nil, err
+	}
+	p.ExternalID, p.QRString, p.VANumber, p.PaymentURL = in.ExternalID, in.QRString, in.VANumber, in.PaymentURL
+	return &p, nil
+}
+
+// PaymentExpire closes payments that were not paid in time and cancels
+// the bookings still waiting for them, freeing their slots.
+type PaymentExpire struct {
+	payments ports.PaymentRepository
+	bookings ports.BookingRepository
+	notifier ports.Notifier
+	logger   ports.Logger
+	waitlist *WaitlistAdvance
+	loc      *time.Location
+}
+
+func NewPaymentExpire(p ports.PaymentRepository, b ports.BookingRepository, n ports.Notifier, l ports.Logger, w *WaitlistAdvance, loc *time.Location) *PaymentExpire {
+	return &PaymentExpire{payments: p, bookings: b, notifier: n, logger: l, waitlist: w, loc: loc}
+}
+
+// Exec expires every pending payment due at now.
+func (u *PaymentExpire) Exec(now time.Time) error {
+	due, err := u.payments.ListExpired(now, 100)
+	if err != nil {
+		return err
+	}
+	for _, p := range due {
+		if err := u.Expire(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Expire marks p expired and cancels its booking if the booking is still
+// awaiting payment. Payments that are no longer pending are left alone, and
+// so is a booking confirmed by a paid callback while this ran.
+func (u *PaymentExpire) Expire(p domain.Payment) error {
+	ok, err := u.payments.MarkExpired(p.ID)
+	if err != nil || !ok {
+		return err
+	}
+	p.Status = domain.PaymentExpired
+	_ = u.notifier.Notify(paymentEvent(domain.EventPaymentExpired, p))
+	_ = u.logger.Log("payment_expired", strconv.FormatInt(p.ID, 10), time.Now().UTC())
+	b, err := u.bookings.GetByID(p.BookingID)
+	if err != nil {
+		return err
+	}
+	if b.Status != domain.StatusAwaitingPayment {
+		return nil
+	}
+	err = transition(u.bookings, u.notifier, u.logger, u.loc, b, domain.StatusCancelled)
+	if errors.Is(err, domain.ErrInvalidTransition) {
+		// A paid callback confirmed the booking in the meantime.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if u.waitlist != nil {
+		_ = u.waitlist.SlotFreed(b.ServiceID, b.StartAt, b.EndAt)
+	}
+	return nil
+}
+
+// PaymentRefund returns deposits to customers.
+type PaymentRefund struct {
+	payments ports.PaymentRepository
+	gateway  ports.PaymentGateway
+	expire   *PaymentExpire
+	notifier ports.Notifier
+	logger   ports.Logger
+}
+
+func NewPaymentRefund(p ports.PaymentRepository, g ports.PaymentGateway, e *PaymentExpire, n ports.Notifier, l ports.Logger) *PaymentRefund {
+	return &PaymentRefund{payments: p, gateway: g, expire: e, notifier: n, logger: l}
+}
+
+// Exec refunds payment id, e.g. to retry a refund the gateway rejected
+// earlier. Only paid payments can be refunded, and the part kept as a
+// cancellation fee stays kept.
+func (u *PaymentRefund) Exec(id int64) (*domain.Payment, error) {
+	p, err := u.payments.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != domain.PaymentPaid || p.Kept >= p.Amount {
+		return nil, domain.ErrInvalidTransition
+	}
+	if err := u.refund(p, p.Amount-p.Kept); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Held returns the total of the paid deposits of a booking.
+func (u *PaymentRefund) Held(bookingID int64) (int64, error) {
+	payments, err := u.payments.ListByBooking(bookingID)
+	if err != nil {
+		return 0, err
+	}
+	var total int64
+	for _, p := range payments {
+		if p.Status == domain.PaymentPaid {
+			total += p.Amount
+		}
+	}
+	return total, nil
+}
+
+// Settle closes the payments of a booking that was just cancelled or
+// marked as a no-show: pending payments expire, and paid deposits are kept
+// up to fee with the rest refunded. The kept part is recorded first, so a
+// refund the gateway rejects stays paid and Exec later refunds only the
+// rest. It stops at the first failure.
+func (u *PaymentRefund) Settle(b domain.Booking, fee int64) error {
+	payments, err := u.payments.ListByBooking(b.ID)
+	if err != nil {
+		return err
+	}
+	var kept int64
+	for _, p := range payments {
+		switch p.Status {
+		case domain.PaymentPending:
+			if err := u.expire.Expire(p); err != nil {
+				return err
+			}
+		case domain.PaymentPaid:
+			keep := min(fee-kept, p.Amount)
+			kept += keep
+			if keep > 0 {
+				if _, err := u.payments.Keep(p.ID, keep); err != nil {
+					return err
+				}
+				p.Kept = keep
+			}
+			if p.Amount > keep {
+				if err := u.refund(&p, p.Amount-keep); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// refund claims p before asking the gateway, so concurrent refunds of the
+// same payment pay out once; the loser gets domain.ErrInvalidTransition. A
+// gateway failure hands the payment back as paid.
+func (u *PaymentRefund) refund(p *domain.Payment, amount int64) error {
+	now := time.Now().UTC()
+	ok, err := u.payments.MarkRefunding(p.ID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrInvalidTransition
+	}
+	if err := u.gateway.Refund(*p, amount); err != nil {
+		_ = u.logger.Log("payment_refund_failed", strconv.FormatInt(p.ID, 10)+": "+err.Error(), now)
+		if rerr := u.payments.ReleaseRefund(p.ID); rerr != nil {
+			return rerr
+		}
+		return domain.ErrPaymentFailed.Wrap(err)
+	}
+	ok, err = u.payments.MarkRefunded(p.ID, amount, now)
+	if err != nil || !ok {
+		return err
+	}
+	p.Status, p.RefundedAmount, p.RefundedAt = domain.PaymentRefunded, amount, &now
+	_ = u.notifier.Notify(paymentEvent(domain.EventPaymentRefunded, *p))
+	_ = u.logger.Log("payment_refunded", strconv.FormatInt(p.ID, 10)+": "+strconv.FormatInt(amount, 10), now)
+	return nil
+}
+
+// PaymentCallback applies a verified gateway notification: a paid payment
+// confirms its booking, and invoices it when the whole total was paid, and
+// an expired one cancels it. Repeated callbacks change nothing.
+type PaymentCallback struct {
+	payments ports.PaymentRepository
+	bookings ports.BookingRepository
+	gateway  ports.PaymentGateway
+	expire   *PaymentExpire
+	refund   *PaymentRefund
+	invoices *InvoiceIssue
+	notifier ports.Notifier
+	logger   ports.Logger
+	loc      *time.Location
+}
+
+func NewPaymentCallback(p ports.PaymentRepository, b ports.BookingRepository, g ports.PaymentGateway, e *PaymentExpire, r *PaymentRefund, ii *InvoiceIssue, n ports.Notifier, l ports.Logger, loc *time.Location) *PaymentCallback {
+	return &PaymentCallback{payments: p, bookings: b, gateway: g, expire: e, refund: r, invoices: ii, notifier: n, logger: l, loc: loc}
+}
+
+func (u *PaymentCallback) Exec(header func(key string) string, body []byte) error {
+	cb, err := u.gateway.ParseCallback(header, body)
+	if err != nil {
+		return err
+	}
+	p, err := u.payments.GetByExternalID(u.gateway.Name(), cb.ExternalID)
+	if err != nil {
+		return err
+	}
+	switch cb.Status {
+	case domain.PaymentPaid:
+		return u.paid(p, cb)
+	case domain.PaymentExpired:
+		return u.expire.Expire(*p)
+	}
+	return nil
+}
+
+func (u *PaymentCallback) paid(p *domain.Payment, cb domain.PaymentCallback) error {
+	now := time.Now().UTC()
+	if !cb.NoAmount && cb.Amount < p.Amount {
+		// Treated as unpaid, including a missing amount; the payment
+		// expires unless the rest arrives.
+		_ = u.logger.Log("payment_amount_mismatch", strconv.FormatInt(p.ID, 10)+": "+strconv.FormatInt(cb.Amount, 10), now)
+		return nil
+	}
+	ok, err := u.payments.MarkPaid(p.ID, now)
+	if err != nil || !ok {
+		return err
+	}
+	p.Status, p.PaidAt = domain.PaymentPaid, &now
+	_ = u.notifier.Notify(paymentEvent(domain.EventPaymentPaid, *p))
+	_ = u.logger.Log("payment_paid", strconv.FormatInt(p.ID, 10), now)
+	b, err := u.bookings.GetByID(p.BookingID)
+	if err != nil {
+		return err
+	}
+	err = transition(u.bookings, u.notifier, u.logger, u.loc, b, domain.StatusConfirmed)
+	if errors.Is(err, domain.ErrInvalidTransition) {
+		// The booking expired or was cancelled before the money arrived.
+		// The callback itself succeeded; a failed refund stays paid for an
+		// admin to retry. Gateway failures are logged by refund already.
+		if err := u.refund.refund(p, p.Amount); err != nil && !errors.Is(err, domain.ErrPaymentFailed) {
+			_ = u.logger.Log("payment_refund_failed", strconv.FormatInt(p.ID, 10)+": "+err.Error(), now)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// Deposits below the total are invoiced once the booking is completed.
+	if _, err := u.invoices.Exec(b.ID); err != nil && !errors.Is(err, domain.ErrInvoiceUnavailable) {
+		_ = u.logger.Log("invoice_failed", strconv.FormatInt(b.ID, 10)+": "+err.Error(), now)
+	}
+	return nil
+}
+
+// PaymentList returns the payments of a booking, oldest first.
+type PaymentList struct {
+	payments ports.PaymentRepository
+	bookings ports.BookingRepository
+	loc      *time.Location
+}
+
+func NewPaymentList(p ports.PaymentRepository, b ports.BookingRepository, loc *time.Location) *PaymentList {
+	return &PaymentList{payments: p, bookings: b, loc: loc}
+}
+
+func (u *PaymentList) Exec(bookingID int64) ([]domain.Payment, error) {
+	if _, err := u.bookings.GetByID(bookingID); err != nil {
+		return nil, err
+	}
+	items, err := u.payments.ListByBooking(bookingID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Localize(u.loc)
+	}
+	return items, nil
+}
@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"errors"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+)
+
+type PriceCalculate struct {
+	services ports.ServiceRepository
+	options  ports.ServiceOptionRepository
+}
+
+func NewPriceCalculate(s ports.ServiceRepository, o ports.ServiceOptionRepository) *PriceCalculate {
+	return &PriceCalculate{services: s, options: o}
+}
+
+// Exec prices a selection for a bookable service and returns the service
+// with the quote. Bad selections are *domain.ValidationError values.
+func (u *PriceCalculate) Exec(serviceID int64, sel domain.OptionSelection) (*domain.Service, domain.PriceQuote, error) {
+	svc, err := u.services.GetByID(serviceID)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && !svc.Bookable()) {
+		return nil, domain.PriceQuote{}, domain.Invalid("service_id", "service_unavailable")
+	}
+	if err != nil {
+		return nil, domain.PriceQuote{}, err
+	}
+	options, err := u.options.ListByService(serviceID)
+	if err != nil {
+		return nil, domain.PriceQuote{}, err
+	}
+	q, err := domain.Quote(*svc, options, sel)
+	if err != nil {
+		return nil, domain.PriceQuote{}, err
+	}
+	return svc, q, nil
+}
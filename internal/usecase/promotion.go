@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+)
+
+// localDay returns the start of the calendar date of d in loc.
+func localDay(d time.Time, loc *time.Location) time.Time {
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+}
+
+// promotionWindow reads p.StartsAt and p.EndsAt as inclusive calendar dates
+// and turns them into instants in the business location: the promotion runs
+// from the start of StartsAt to the end of EndsAt.
+func promotionWindow(p *domain.Promotion, loc *time.Location) error {
+	if p.StartsAt != nil {
+		t := localDay(*p.StartsAt, loc)
+		p.StartsAt = &t
+	}
+	if p.EndsAt != nil {
+		t := localDay(*p.EndsAt, loc).AddDate(0, 0, 1)
+		p.EndsAt = &t
+	}
+	if p.StartsAt != nil && p.EndsAt != nil && !p.EndsAt.After(*p.StartsAt) {
+		return domain.Invalid("ends_on", "before_start")
+	}
+	return nil
+}
+
+type PromotionCreate struct {
+	promos ports.PromotionRepository
+	logger ports.Logger
+	loc    *time.Location
+}
+
+func NewPromotionCreate(p ports.PromotionRepository, l ports.Logger, loc *time.Location) *PromotionCreate {
+	return &PromotionCreate{promos: p, logger: l, loc: loc}
+}
+
+// Exec stores a new promotion. StartsAt and EndsAt are read as calendar
+// dates, see promotionWindow. A code that is taken fails with
+// domain.ErrAlreadyExists.
+func (u *PromotionCreate) Exec(p domain.Promotion) (int64, error) {
+	if err := promotionWindow(&p, u.loc); err != nil {
+		return 0, err
+	}
+	p.Code = domain.NormalizePromoCode(p.Code)
+	p.CreatedAt = time.Now().UTC()
+	id, err := u.promos.Create(p)
+	if err != nil {
+		return 0, err
+	}
+	_ = u.logger.Log("promotion_created", p.Code, p.CreatedAt)
+	return id, nil
+}
+
+type PromotionUpdate struct {
+	promos ports.PromotionRepository
+	logger ports.Logger
+	loc    *time.Location
+}
+
+func NewPromotionUpdate(p ports.PromotionRepository, l ports.Logger, loc *time.Location) *PromotionUpdate {
+	return &PromotionUpdate{promos: p, logger: l, loc: loc}
+}
+
+// Exec replaces every field of promotion id except its creation time.
+// Redemptions already made keep their discount.
+func (u *PromotionUpdate) Exec(id int64, p domain.Promotion) (*domain.Promotion, error) {
+	cur, err := u.promos.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := promotionWindow(&p, u.loc); err != nil {
+		return nil, err
+	}
+	p.ID, p.CreatedAt = id, cur.CreatedAt
+	p.Code = domain.NormalizePromoCode(p.Code)
+	if err := u.promos.Update(p); err != nil {
+		return nil, err
+	}
+	_ = u.logger.Log("promotion_updated", strconv.FormatInt(id, 10)+": "+p.Code, time.Now().UTC())
+	return &p, nil
+}
+
+type PromotionDelete struct {
+	promos ports.PromotionRepository
+	logger ports.Logger
+}
+
+func NewPromotionDelete(p ports.PromotionRepository, l ports.Logger) *PromotionDelete {
+	return &PromotionDelete{promos: p, logger: l}
+}
+
+// Exec removes an unused promotion. Promotions with redemptions fail with
+// domain.ErrInUse; deactivate them instead.
+func (u *PromotionDelete) Exec(id int64) error {
+	if err := u.promos.Delete(id); err != nil {
+		return err
+	}
+	_ = u.logger.Log("promotion_deleted", strconv.FormatInt(id, 10), time.Now().UTC())
+	return nil
+}
+
+type PromotionList struct {
+	promos ports.PromotionRepository
+}
+
+func NewPromotionList(p ports.PromotionRepository) *PromotionList {
+	return &PromotionList{promos: p}
+}
+
+func (u *PromotionList) Exec() ([]domain.Promotion, error) {
+	return u.promos.List()
+}
+
+type PromotionRedemptionList struct {
+	promos ports.PromotionRepository
+}
+
+func NewPromotionRedemptionList(p ports.PromotionRepository) *PromotionRedemptionList {
+	return &PromotionRedemptionList{promos: p}
+}
+
+func (u *PromotionRedemptionList) Exec(id int64) ([]domain.PromotionRedemption, error) {
+	if _, err := u.promos.GetByID(id); err != nil {
+		return nil, err
+	}
+	return u.promos.ListRedemptions(id)
+}
+
+type PromotionReport struct {
+	promos ports.PromotionRepository
+	loc    *time.Location
+}
+
+func NewPromotionReport(p ports.PromotionRepository, loc *time.Location) *PromotionReport {
+	return &PromotionReport{promos: p, loc: loc}
+}
+
+const maxReportDays = 366
+
+// Exec sums redemptions made between the calendar dates from and to,
+// inclusive, in the business location.
+func (u *PromotionReport) Exec(from, to time.Time) ([]domain.PromotionReport, error) {
+	start, end := localDay(from, u.loc), localDay(to, u.loc).AddDate(0, 0, 1)
+	if !end.After(start) || end.Sub(start) > maxReportDays*24*time.Hour {
+		return nil, domain.ErrInvalidRange
+	}
+	return u.promos.Report(start, end)
+}
+
+type PromotionApply struct {
+	promos   ports.PromotionRepository
+	bookings ports.BookingRepository
+}
+
+func NewPromotionApply(p ports.PromotionRepository, b ports.BookingRepository) *PromotionApply {
+	return &PromotionApply{promos: p, bookings: b}
+}
+
+// Exec checks code for a booking of serviceID worth subtotal by the
+// customer with phone, and returns the promotion and the discount it gives.
+// Unusable codes are *domain.ValidationError values on "promo_code".
+func (u *PromotionApply) Exec(code string, serviceID, subtotal int64, phone string) (*domain.Promotion, int64, error) {
+	p, err := u.promos.GetByCode(domain.NormalizePromoCode(code))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, 0, domain.Invalid("promo_code", "promo_not_found")
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	phone = domain.NormalizePhone(phone)
+	usage, err := u.promos.Usage(p.ID, phone)
+	if err != nil {
+		return nil, 0, err
+	}
+	firstVisit := true
+	if p.FirstVisitOnly {
+		n, err := u.bookings.CountByPhone(phone)
+		if err != nil {
+			return nil, 0, err
+		}
+		firstVisit = n == 0
+	}
+	if err := p.Check(time.Now().UTC(), serviceID, subtotal, usage, firstVisit); err != nil {
+		return nil, 0, err
+	}
+	return p, p.Discount(subtotal), nil
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"strconv"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+)
+
+const (
+	reminderBatch     = 100
+	reminderLookahead = time.Hour
+	reminderGrace     = 15 * time.Minute
+	// reminderLease is how long a claimed job may stay in sending before
+	// it is given up as unknown, e.g. after a crash mid-send.
+	reminderLease = 5 * time.Minute
+)
+
+type ReminderDispatch struct {
+	reminders ports.ReminderRepository
+	bookings  ports.BookingRepository
+	notifier  ports.Notifier
+	logger    ports.Logger
+	offsets   []time.Duration
+	loc       *time.Location
+}
+
+func NewReminderDispatch(r ports.ReminderRepository, b ports.BookingRepository, n ports.Notifier, l ports.Logger, offsets []time.Duration, loc *time.Location) *ReminderDispatch {
+	return &ReminderDispatch{reminders: r, bookings: b, notifier: n, logger: l, offsets: offsets, loc: loc}
+}
+
+// Exec enqueues reminder jobs coming up within the lookahead window and sends
+// the ones that are due. Jobs whose run time was missed by more than the
+// grace period (e.g. bookings made after the reminder point) are never
+// enqueued.
+func (u *ReminderDispatch) Exec(now time.Time) error {
+	for _, off := range u.offsets {
+		if _, err := u.reminders.Enqueue(off, now.Add(-reminderGrace), now.Add(reminderLookahead)); err != nil {
+			return err
+		}
+	}
+	abandoned, err := u.reminders.Abandon(now.Add(-reminderLease))
+	if err != nil {
+		return err
+	}
+	for _, id := range abandoned {
+		_ = u.logger.Log("booking_reminder_unknown", strconv.FormatInt(id, 10), now)
+	}
+	jobs, err := u.reminders.Due(now, reminderBatch)
+	if err != nil {
+		return err
+	}
+	for _, j := range jobs {
+		ok, err := u.reminders.Claim(j.ID, now)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		_ = u.reminders.Finish(j.ID, u.send(j, now), time.Now().UTC())
+	}
+	return nil
+}
+
+func (u *ReminderDispatch) send(j domain.ReminderJob, now time.Time) string {
+	b, err := u.bookings.GetByID(j.BookingID)
+	if err != nil {
+		return domain.ReminderFailed
+	}
+	active := b.Status == domain.StatusPending || b.Status == domain.StatusConfirmed
+	if !active || !b.StartAt.Equal(j.StartAt) || !b.StartAt.After(now) {
+		return domain.ReminderSkipped
+	}
+	b.Localize(u.loc)
+	e := newEvent(domain.EventBookingReminder, domain.BookingReminderData{
+		BookingData:         domain.NewBookingData(*b),
+		RemindBeforeMinutes: int(j.Offset / time.Minute),
+	})
+	if err := u.notifier.Notify(e); err != nil {
+		_ = u.logger.Log("booking_reminder_failed", strconv.FormatInt(b.ID, 10)+": "+err.Error(), now)
+		return domain.ReminderFailed
+	}
+	_ = u.logger.Log("booking_reminder_sent", strconv.FormatInt(b.ID, 10)+": "+j.Offset.String(), now)
+	return domain.ReminderSent
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+	"be-golang/internal/util"
+)
+
+const maxCalendarDays = 62
+
+type ScheduleCalendar struct {
+	schedule ports.ScheduleRepository
+}
+
+func NewScheduleCalendar(s ports.ScheduleRepository) *ScheduleCalendar {
+	return &ScheduleCalendar{schedule: s}
+}
+
+func (u *ScheduleCalendar) Exec(from, to time.Time) ([]domain.DaySchedule, error) {
+	from, to = dateOnly(from), dateOnly(to)
+	if to.Before(from) || to.Sub(from) > maxCalendarDays*24*time.Hour {
+		return nil, domain.ErrInvalidRange
+	}
+	return resolveSchedule(u.schedule, from, to)
+}
+
+// resolveSchedule expands the weekly hours, date overrides and closures into
+// one DaySchedule per day. Closures win over overrides, overrides replace the
+// weekly hours of their date. With no weekly hours configured at all the shop
+// is treated as open all day so existing installs keep accepting bookings.
+func resolveSchedule(repo ports.ScheduleRepository, from, to time.Time) ([]domain.DaySchedule, error) {
+	weekly, err := repo.ListWeekly()
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := repo.ListOverrides(from, to)
+	if err != nil {
+		return nil, err
+	}
+	closures, err := repo.ListClosures(from, to)
+	if err != nil {
+		return nil, err
+	}
+	var out []domain.DaySchedule
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		day := domain.DaySchedule{Date: d}
+		closed := false
+		for _, c := range closures {
+			if !d.Before(dateOnly(c.StartDate)) && !d.After(dateOnly(c.EndDate)) {
+				closed, day.Reason = true, c.Reason
+				break
+			}
+		}
+		if !closed {
+			for _, o := range overrides {
+				if dateOnly(o.Date).Equal(d) {
+					day.Intervals = append(day.Intervals, domain.TimeRange{Opens: o.Opens, Closes: o.Closes})
+				}
+			}
+			if day.Intervals == nil {
+				for _, w := range weekly {
+					if w.Weekday == int(d.Weekday()) {
+						day.Intervals = append(day.Intervals, domain.TimeRange{Opens: w.Opens, Closes: w.Closes})
+					}
+				}
+			}
+			if day.Intervals == nil && len(weekly) == 0 {
+				day.Intervals = []domain.TimeRange{{Opens: "00:00", Closes: "24:00"}}
+			}
+			day.Open = len(day.Intervals) > 0
+		}
+		out = append(out, day)
+	}
+	return out, nil
+}
+
+// checkOpen reports domain.ErrClosed unless [start, end) fits inside one of
+// the opening intervals of its local calendar day.
+func checkOpen(repo ports.ScheduleRepository, start, end time.Time, loc *time.Location) error {
+	if repo == nil {
+		return nil
+	}
+	d := util.CalendarDate(start, loc)
+	if !util.CalendarDate(end.Add(-time.Minute), loc).Equal(d) {
+		return domain.ErrClosed
+	}
+	days, err := resolveSchedule(repo, d, d)
+	if err != nil {
+		return err
+	}
+	from := start.In(loc).Format("15:04")
+	until := end.In(loc).Format("15:04")
+	if until == "00:00" && end.After(start) {
+		until = "24:00"
+	}
+	for _, r := range days[0].Intervals {
+		if r.Opens <= from && until <= r.Closes {
+			return nil
+		}
+	}
+	return domain.ErrClosed
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"io"
+	"strconv"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+	"be-golang/internal/util"
+)
+
+type ScheduleHolidayImport struct {
+	schedule ports.ScheduleRepository
+	logger   ports.Logger
+}
+
+func NewScheduleHolidayImport(s ports.ScheduleRepository, l ports.Logger) *ScheduleHolidayImport {
+	return &ScheduleHolidayImport{schedule: s, logger: l}
+}
+
+// Exec imports every VEVENT as a closure. Events are keyed by their UID so
+// re-importing the same calendar updates instead of duplicating.
+func (u *ScheduleHolidayImport) Exec(r io.Reader) (int, error) {
+	events, err := util.ParseICalEvents(r)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, e := range events {
+		c := domain.Closure{
+			StartDate:   dateOnly(e.Start),
+			EndDate:     dateOnly(e.End),
+			Reason:      e.Summary,
+			ExternalUID: e.UID,
+		}
+		if e.AllDay && c.EndDate.After(c.StartDate) {
+			c.EndDate = c.EndDate.AddDate(0, 0, -1)
+		}
+		if c.ExternalUID == "" {
+			_, err = u.schedule.CreateClosure(c)
+		} else {
+			_, err = u.schedule.UpsertClosureByUID(c)
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+	_ = u.logger.Log("holidays_imported", strconv.Itoa(n), time.Now().UTC())
+	return n, nil
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+)
+
+func validRange(opens, closes string) bool {
+	o, err := time.Parse("15:04", opens)
+	if err != nil {
+		return false
+	}
+	if closes == "24:00" {
+		return true
+	}
+	c, err := time.Parse("15:04", closes)
+	if err != nil {
+		return false
+	}
+	return o.Before(c)
+}
+
+type ScheduleWeeklyList struct {
+	schedule ports.ScheduleRepository
+}
+
+func NewScheduleWeeklyList(s ports.ScheduleRepository) *ScheduleWeeklyList {
+	return &ScheduleWeeklyList{schedule: s}
+}
+
+func (u *ScheduleWeeklyList) Exec() ([]domain.OpeningInterval, error) {
+	return u.schedule.ListWeekly()
+}
+
+type ScheduleWeeklyCreate struct {
+	schedule ports.ScheduleRepository
+}
+
+func NewScheduleWeeklyCreate(s ports.ScheduleRepository) *ScheduleWeeklyCreate {
+	return &ScheduleWeeklyCreate{schedule: s}
+}
+
+func (u *ScheduleWeeklyCreate) Exec(i domain.OpeningInterval) (int64, error) {
+	if i.Weekday < 0 || i.Weekday > 6 || !validRange(i.Opens, i.Closes) {
+		return 0, domain.ErrInvalidInput
+	}
+	return u.schedule.CreateWeekly(i)
+}
+
+type ScheduleWeeklyDelete struct {
+	schedule ports.ScheduleRepository
+}
+
+func NewScheduleWeeklyDelete(s ports.ScheduleRepository) *ScheduleWeeklyDelete {
+	return &ScheduleWeeklyDelete{schedule: s}
+}
+
+func (u *ScheduleWeeklyDelete) Exec(id int64) error {
+	return u.schedule.DeleteWeekly(id)
+}
+
+type ScheduleOverrideList struct {
+	schedule ports.ScheduleRepository
+}
+
+func NewScheduleOverrideList(s ports.ScheduleRepository) *ScheduleOverrideList {
+	return &ScheduleOverrideList{schedule: s}
+}
+
+func (u *ScheduleOverrideList) Exec(from, to time.Time) ([]domain.ScheduleOverride, error) {
+	return u.schedule.ListOverrides(from, to)
+}
+
+type ScheduleOverrideCreate struct {
+	schedule ports.ScheduleRepository
+}
+
+func NewScheduleOverrideCreate(s ports.ScheduleRepository) *ScheduleOverrideCreate {
+	return &ScheduleOverrideCreate{schedule: s}
+}
+
+func (u *ScheduleOverrideCreate) Exec(o domain.ScheduleOverride) (int64, error) {
+	if !validRange(o.Opens, o.Closes) {
+		return 0, domain.ErrInvalidInput
+	}
+	return u.schedule.CreateOverride(o)
+}
+
+type ScheduleOverrideDelete struct {
+	schedule ports.ScheduleRepository
+}
+
+func NewScheduleOverrideDelete(s ports.ScheduleRepository) *ScheduleOverrideDelete {
+	return &ScheduleOverrideDelete{schedule: s}
+}
+
+func (u *ScheduleOverrideDelete) Exec(id int64) error {
+	return u.schedule.DeleteOverride(id)
+}
+
+type ScheduleClosureList struct {
+	schedule ports.ScheduleRepository
+}
+
+func NewScheduleClosureList(s ports.ScheduleRepository) *ScheduleClosureList {
+	return &ScheduleClosureList{schedule: s}
+}
+
+func (u *ScheduleClosureList) Exec(from, to time.Time) ([]domain.Closure, error) {
+	return u.schedule.ListClosures(from, to)
+}
+
+type ScheduleClosureCreate struct {
+	schedule ports.ScheduleRepository
+}
+
+func NewScheduleClosureCreate(s ports.ScheduleRepository) *ScheduleClosureCreate {
+	return &ScheduleClosureCreate{schedule: s}
+}
+
+func (u *ScheduleClosureCreate) Exec(c domain.Closure) (int64, error) {
+	if c.EndDate.IsZero() {
+		c.EndDate = c.StartDate
+	}
+	if c.EndDate.Before(c.StartDate) {
+		return 0, domain.ErrInvalidInput
+	}
+	return u.schedule.CreateClosure(c)
+}
+
+type ScheduleClosureDelete struct {
+	schedule ports.ScheduleRepository
+}
+
+func NewScheduleClosureDelete(s ports.ScheduleRepository) *ScheduleClosureDelete {
+	return &ScheduleClosureDelete{schedule: s}
+}
+
+func (u *ScheduleClosureDelete) Exec(id int64) error {
+	return u.schedule.DeleteClosure(id)
+}
@@ -1,32 +1,156 @@
 package usecase
 
 import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
 	"be-golang/internal/domain"
 	"be-golang/internal/ports"
+	"be-golang/internal/util"
 )
 
 type ServiceCreate struct {
-	services ports.ServiceRepository
+	services   ports.ServiceRepository
+	categories ports.CategoryRepository
 }
 
-func NewServiceCreate(s ports.ServiceRepository) *ServiceCreate {
-	return &ServiceCreate{services: s}
+func NewServiceCreate(s ports.ServiceRepository, c ports.CategoryRepository) *ServiceCreate {
+	return &ServiceCreate{services: s, categories: c}
 }
 
+const defaultServiceDuration = 60
+
 func (u *ServiceCreate) Exec(s domain.Service) (int64, error) {
+	if s.DurationMinutes <= 0 {
+		s.DurationMinutes = defaultServiceDuration
+	}
+	if err := checkCategory(u.categories, s.CategoryID); err != nil {
+		return 0, err
+	}
+	s.Tags = domain.NormalizeTags(s.Tags)
 	return u.services.Create(s)
 }
 
-type ServiceDelete struct {
+// checkCategory rejects a category ID that does not exist. A nil ID means
+// uncategorised.
+func checkCategory(categories ports.CategoryRepository, id *int64) error {
+	if id == nil {
+		return nil
+	}
+	if _, err := categories.GetByID(*id); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.Invalid("category_id", "not_found")
+		}
+		return err
+	}
+	return nil
+}
+
+// ServiceUpdateInput holds the fields to change; nil fields are kept. A
+// CategoryID of 0 removes the category.
+type ServiceUpdateInput struct {
+	Name            *string
+	Description     *string
+	Price           *int64
+	DurationMinutes *int
+	IsActive        *bool
+	CategoryID      *int64
+	SortOrder       *int
+	Tags            []string
+}
+
+type ServiceUpdate struct {
+	services   ports.ServiceRepository
+	categories ports.CategoryRepository
+	logger     ports.Logger
+}
+
+func NewServiceUpdate(s ports.ServiceRepository, c ports.CategoryRepository, l ports.Logger) *ServiceUpdate {
+	return &ServiceUpdate{services: s, categories: c, logger: l}
+}
+
+// Exec applies the set fields. Existing bookings keep the name and price
+// they were made with.
+func (u *ServiceUpdate) Exec(id int64, in ServiceUpdateInput) (*domain.Service, error) {
+	s, err := u.services.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if in.Name != nil {
+		s.Name = *in.Name
+	}
+	if in.Description != nil {
+		s.Description = *in.Description
+	}
+	if in.Price != nil {
+		s.Price = *in.Price
+	}
+	if in.DurationMinutes != nil {
+		s.DurationMinutes = *in.DurationMinutes
+	}
+	if in.IsActive != nil {
+		s.IsActive = *in.IsActive
+	}
+	if in.CategoryID != nil {
+		s.CategoryID = in.CategoryID
+		if *in.CategoryID == 0 {
+			s.CategoryID = nil
+		}
+		if err := checkCategory(u.categories, s.CategoryID); err != nil {
+			return nil, err
+		}
+	}
+	if in.SortOrder != nil {
+		s.SortOrder = *in.SortOrder
+	}
+	if in.Tags != nil {
+		s.Tags = domain.NormalizeTags(in.Tags)
+	}
+	if err := u.services.Update(*s); err != nil {
+		return nil, err
+	}
+	_ = u.logger.Log("service_updated", strconv.FormatInt(id, 10)+": "+s.Name, time.Now().UTC())
+	return s, nil
+}
+
+type ServiceArchive struct {
 	services ports.ServiceRepository
+	logger   ports.Logger
 }
 
-func NewServiceDelete(s ports.ServiceRepository) *ServiceDelete {
-	return &ServiceDelete{services: s}
+func NewServiceArchive(s ports.ServiceRepository, l ports.Logger) *ServiceArchive {
+	return &ServiceArchive{services: s, logger: l}
 }
 
-func (u *ServiceDelete) Exec(id int64) error {
-	return u.services.Delete(id)
+// Exec hides the service from the catalog and new bookings. Its bookings
+// are left untouched.
+func (u *ServiceArchive) Exec(id int64) error {
+	now := time.Now().UTC()
+	if err := u.services.Archive(id, now); err != nil {
+		return err
+	}
+	_ = u.logger.Log("service_archived", strconv.FormatInt(id, 10), now)
+	return nil
+}
+
+type ServiceRestore struct {
+	services ports.ServiceRepository
+	logger   ports.Logger
+}
+
+func NewServiceRestore(s ports.ServiceRepository, l ports.Logger) *ServiceRestore {
+	return &ServiceRestore{services: s, logger: l}
+}
+
+// Exec brings an archived service back with its previous active flag.
+func (u *ServiceRestore) Exec(id int64) (*domain.Service, error) {
+	if err := u.services.Restore(id); err != nil {
+		return nil, err
+	}
+	_ = u.logger.Log("service_restored", strconv.FormatInt(id, 10), time.Now().UTC())
+	return u.services.GetByID(id)
 }
 
 type ServiceListActive struct {
@@ -37,6 +161,117 @@ func NewServiceListActive(s ports.ServiceRepository) *ServiceListActive {
 	return &ServiceListActive{services: s}
 }
 
-func (u *ServiceListActive) Exec() ([]domain.Service, error) {
-	return u.services.ListActive()
+func (u *ServiceListActive) Exec(f domain.ServiceFilter) ([]domain.Service, error) {
+	return u.services.ListActive(f)
+}
+
+type ServiceList struct {
+	services ports.ServiceRepository
+}
+
+func NewServiceList(s ports.ServiceRepository) *ServiceList {
+	return &ServiceList{services: s}
+}
+
+func (u *ServiceList) Exec(f domain.ServiceFilter) ([]domain.Service, error) {
+	return u.services.List(f)
+}
+
+type ServiceReorder struct {
+	services ports.ServiceRepository
+	logger   ports.Logger
+}
+
+func NewServiceReorder(s ports.ServiceRepository, l ports.Logger) *ServiceReorder {
+	return &ServiceReorder{services: s, logger: l}
+}
+
+// Exec sets the manual display order within each category to the order of
+// ids. Services left out keep their current position.
+func (u *ServiceReorder) Exec(ids []int64) error {
+	if err := u.services.Reorder(ids); err != nil {
+		return err
+	}
+	_ = u.logger.Log("services_reordered", strconv.Itoa(len(ids))+" services", time.Now().UTC())
+	return nil
+}
+
+// imageExtensions lists the accepted image types, detected from the file
+// content rather than the client's Content-Type.
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+type ServiceSetImage struct {
+	services ports.ServiceRepository
+	blobs    ports.BlobStore
+	logger   ports.Logger
+}
+
+func NewServiceSetImage(s ports.ServiceRepository, b ports.BlobStore, l ports.Logger) *ServiceSetImage {
+	return &ServiceSetImage{services: s, blobs: b, logger: l}
+}
+
+// Exec stores data as the service image, replacing any previous one. Each
+// upload gets a new key so cached copies of the old image are not reused.
+func (u *ServiceSetImage) Exec(id int64, data []byte) (*domain.Service, error) {
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, domain.Invalid("image", "invalid_image")
+	}
+	s, err := u.services.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	suffix, err := util.RandomToken(8)
+	if err != nil {
+		return nil, err
+	}
+	key := "services/" + strconv.FormatInt(id, 10) + "-" + suffix + ext
+	url, err := u.blobs.Put(key, contentType, data)
+	if err != nil {
+		return nil, err
+	}
+	old := s.ImageKey
+	s.ImageKey, s.ImageURL = key, url
+	if err := u.services.Update(*s); err != nil {
+		_ = u.blobs.Delete(key)
+		return nil, err
+	}
+	if old != "" {
+		_ = u.blobs.Delete(old)
+	}
+	_ = u.logger.Log("service_image_updated", strconv.FormatInt(id, 10)+": "+key, time.Now().UTC())
+	return s, nil
+}
+
+type ServiceRemoveImage struct {
+	services ports.ServiceRepository
+	blobs    ports.BlobStore
+	logger   ports.Logger
+}
+
+func NewServiceRemoveImage(s ports.ServiceRepository, b ports.BlobStore, l ports.Logger) *ServiceRemoveImage {
+	return &ServiceRemoveImage{services: s, blobs: b, logger: l}
+}
+
+func (u *ServiceRemoveImage) Exec(id int64) error {
+	s, err := u.services.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if s.ImageKey == "" {
+		return nil
+	}
+	old := s.ImageKey
+	s.ImageKey, s.ImageURL = "", ""
+	if err := u.services.Update(*s); err != nil {
+		return err
+	}
+	_ = u.blobs.Delete(old)
+	_ = u.logger.Log("service_image_removed", strconv.FormatInt(id, 10), time.Now().UTC())
+	return nil
 }
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"strconv"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+)
+
+type ServiceOptionCreate struct {
+	services ports.ServiceRepository
+	options  ports.ServiceOptionRepository
+	logger   ports.Logger
+}
+
+func NewServiceOptionCreate(s ports.ServiceRepository, o ports.ServiceOptionRepository, l ports.Logger) *ServiceOptionCreate {
+	return &ServiceOptionCreate{services: s, options: o, logger: l}
+}
+
+// Exec adds a variant or add-on to an existing service.
+func (u *ServiceOptionCreate) Exec(o domain.ServiceOption) (int64, error) {
+	if _, err := u.services.GetByID(o.ServiceID); err != nil {
+		return 0, err
+	}
+	id, err := u.options.Create(o)
+	if err != nil {
+		return 0, err
+	}
+	_ = u.logger.Log("service_"+o.Kind+"_created", strconv.FormatInt(o.ServiceID, 10)+": "+o.Name, time.Now().UTC())
+	return id, nil
+}
+
+// ServiceOptionUpdateInput holds the fields to change; nil fields are kept.
+type ServiceOptionUpdateInput struct {
+	Name            *string
+	Price           *int64
+	DurationMinutes *int
+	SortOrder       *int
+	IsActive        *bool
+}
+
+type ServiceOptionUpdate struct {
+	options ports.ServiceOptionRepository
+	logger  ports.Logger
+}
+
+func NewServiceOptionUpdate(o ports.ServiceOptionRepository, l ports.Logger) *ServiceOptionUpdate {
+	return &ServiceOptionUpdate{options: o, logger: l}
+}
+
+// Exec changes option id of the given service and kind. Options of other
+// services or kinds are reported as not found. Existing bookings keep the
+// prices they were made with.
+func (u *ServiceOptionUpdate) Exec(serviceID int64, kind string, id int64, in ServiceOptionUpdateInput) (*domain.ServiceOption, error) {
+	o, err := getOption(u.options, serviceID, kind, id)
+	if err != nil {
+		return nil, err
+	}
+	if in.Name != nil {
+		o.Name = *in.Name
+	}
+	if in.Price != nil {
+		o.Price = *in.Price
+	}
+	if in.DurationMinutes != nil {
+		o.DurationMinutes = *in.DurationMinutes
+	}
+	if in.SortOrder != nil {
+		o.SortOrder = *in.SortOrder
+	}
+	if in.IsActive != nil {
+		o.IsActive = *in.IsActive
+	}
+	if err := u.options.Update(*o); err != nil {
+		return nil, err
+	}
+	_ = u.logger.Log("service_"+kind+"_updated", strconv.FormatInt(id, 10)+": "+o.Name, time.Now().UTC())
+	return o, nil
+}
+
+type ServiceOptionDelete struct {
+	options ports.ServiceOptionRepository
+	logger  ports.Logger
+}
+
+func NewServiceOptionDelete(o ports.ServiceOptionRepository, l ports.Logger) *ServiceOptionDelete {
+	return &ServiceOptionDelete{options: o, logger: l}
+}
+
+// Exec removes the option. Bookings that used it keep their line items.
+func (u *ServiceOptionDelete) Exec(serviceID int64, kind string, id int64) error {
+	if _, err := getOption(u.options, serviceID, kind, id); err != nil {
+		return err
+	}
+	if err := u.options.Delete(id); err != nil {
+		return err
+	}
+	_ = u.logger.Log("service_"+kind+"_deleted", strconv.FormatInt(id, 10), time.Now().UTC())
+	return nil
+}
+
+func getOption(options ports.ServiceOptionRepository, serviceID int64, kind string, id int64) (*domain.ServiceOption, error) {
+	o, err := options.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if o.ServiceID != serviceID || o.Kind != kind {
+		return nil, domain.ErrNotFound
+	}
+	return o, nil
+}
+
+type ServiceOptionList struct {
+	services ports.ServiceRepository
+	options  ports.ServiceOptionRepository
+}
+
+func NewServiceOptionList(s ports.ServiceRepository, o ports.ServiceOptionRepository) *ServiceOptionList {
+	return &ServiceOptionList{services: s, options: o}
+}
+
+// Exec returns the variants and add-ons of a service. With activeOnly set,
+// as for the public catalog, the service must be bookable and inactive
+// options are left out.
+func (u *ServiceOptionList) Exec(serviceID int64, activeOnly bool) (variants, addons []domain.ServiceOption, err error) {
+	svc, err := u.services.GetByID(serviceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if activeOnly && !svc.Bookable() {
+		return nil, nil, domain.ErrNotFound
+	}
+	options, err := u.options.ListByService(serviceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	variants, addons = []domain.ServiceOption{}, []domain.ServiceOption{}
+	for _, o := range options {
+		if activeOnly && !o.IsActive {
+			continue
+		}
+		if o.Kind == domain.OptionVariant {
+			variants = append(variants, o)
+		} else {
+			addons = append(addons, o)
+		}
+	}
+	return variants, addons, nil
+}
@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+	"be-golang/internal/util"
+)
+
+type WaitlistJoin struct {
+	waitlist ports.WaitlistRepository
+	services ports.ServiceRepository
+	logger   ports.Logger
+	loc      *time.Location
+}
+
+func NewWaitlistJoin(w ports.WaitlistRepository, s ports.ServiceRepository, l ports.Logger, loc *time.Location) *WaitlistJoin {
+	return &WaitlistJoin{waitlist: w, services: s, logger: l, loc: loc}
+}
+
+func (u *WaitlistJoin) Exec(e domain.WaitlistEntry) (int64, error) {
+	if e.CustomerName == "" || e.CustomerPhone == "" {
+		return 0, domain.ErrInvalidInput
+	}
+	if _, _, err := util.ParseClock(e.WindowStart); err != nil {
+		return 0, err
+	}
+	if _, _, err := util.ParseClock(e.WindowEnd); err != nil {
+		return 0, err
+	}
+	if e.WindowEnd <= e.WindowStart {
+		return 0, domain.ErrInvalidInput
+	}
+	now := time.Now()
+	e.Date = util.CalendarDate(e.Date, time.UTC)
+	if e.Date.Before(util.CalendarDate(now, u.loc)) {
+		return 0, domain.ErrInvalidInput
+	}
+	svc, err := u.services.GetByID(e.ServiceID)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && !svc.Bookable()) {
+		return 0, domain.ErrInvalidService
+	}
+	if err != nil {
+		return 0, err
+	}
+	e.Status = domain.WaitlistWaiting
+	e.CreatedAt = now.UTC()
+	id, err := u.waitlist.Create(e)
+	if err != nil {
+		return 0, err
+	}
+	_ = u.logger.Log("waitlist_joined", e.CustomerName, e.CreatedAt)
+	return id, nil
+}
+
+type WaitlistList struct {
+	waitlist ports.WaitlistRepository
+}
+
+func NewWaitlistList(w ports.WaitlistRepository) *WaitlistList {
+	return &WaitlistList{waitlist: w}
+}
+
+func (u *WaitlistList) Exec(date time.Time) ([]domain.WaitlistEntry, error) {
+	return u.waitlist.ListByDate(util.CalendarDate(date, time.UTC))
+}
+
+// WaitlistAdvance hands freed slots to the waitlist. The first eligible entry
+// gets a time-limited offer; offers that run out are expired and the slot
+// moves on to the next entry.
+type WaitlistAdvance struct {
+	waitlist ports.WaitlistRepository
+	bookings ports.BookingRepository
+	notifier ports.Notifier
+	logger   ports.Logger
+	capacity int
+	offerTTL time.Duration
+	loc      *time.Location
+}
+
+func NewWaitlistAdvance(w ports.WaitlistRepository, b ports.BookingRepository, n ports.Notifier, l ports.Logger, capacity int, offerTTL time.Duration, loc *time.Location) *WaitlistAdvance {
+	return &WaitlistAdvance{waitlist: w, bookings: b, notifier: n, logger: l, capacity: capacity, offerTTL: offerTTL, loc: loc}
+}
+
+func (u *WaitlistAdvance) SlotFreed(serviceID int64, start, end time.Time) error {
+	if !start.After(time.Now()) {
+		return nil
+	}
+	if u.capacity > 0 {
+		taken, err := u.bookings.CountOverlapping(start, end, 0)
+		if err != nil {
+			return err
+		}
+		if taken >= u.capacity {
+			return nil
+		}
+	}
+	local := start.In(u.loc)
+	e, err := u.waitlist.NextWaiting(serviceID, util.CalendarDate(local, u.loc), local.Format("15:04"))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	token, err := util.RandomToken(16)
+	if err != nil {
+		return err
+	}
+	expires := time.Now().UTC().Add(u.offerTTL)
+	ok, err := u.waitlist.MarkOffered(e.ID, start, end, expires, token)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// Another slot was offered to this entry meanwhile; try the next one.
+		return u.SlotFreed(serviceID, start, end)
+	}
+	_ = u.notifier.Notify(newEvent(domain.EventWaitlistOffer, domain.WaitlistOfferData{
+		WaitlistID:    e.ID,
+		CustomerName:  e.CustomerName,
+		CustomerPhone: e.CustomerPhone,
+		ServiceID:     e.ServiceID,
+		StartAt:       local,
+		EndAt:         end.In(u.loc),
+		ExpiresAt:     expires.In(u.loc),
+		ClaimToken:    token,
+	}))
+	_ = u.logger.Log("waitlist_offered", strconv.FormatInt(e.ID, 10)+": "+local.Format(time.RFC3339), time.Now().UTC())
+	return nil
+}
+
+func (u *WaitlistAdvance) ExpireOffers(now time.Time) error {
+	items, err := u.waitlist.ListExpiredOffers(now)
+	if err != nil {
+		return err
+	}
+	for _, e := range items {
+		if err := u.waitlist.MarkExpired(e.ID); err != nil {
+			return err
+		}
+		_ = u.logger.Log("waitlist_offer_expired", strconv.FormatInt(e.ID, 10), now.UTC())
+		if err := u.SlotFreed(e.ServiceID, *e.OfferStartAt, *e.OfferEndAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type WaitlistClaim struct {
+	waitlist ports.WaitlistRepository
+	notifier ports.Notifier
+	logger   ports.Logger
+	capacity int
+	loc      *time.Location
+}
+
+func NewWaitlistClaim(w ports.WaitlistRepository, n ports.Notifier, l ports.Logger, capacity int, loc *time.Location) *WaitlistClaim {
+	return &WaitlistClaim{waitlist: w, notifier: n, logger: l, capacity: capacity, loc: loc}
+}
+
+func (u *WaitlistClaim) Exec(id int64, token string) (int64, error) {
+	e, err := u.waitlist.GetByID(id)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now().UTC()
+	if e.Status != domain.WaitlistOffered || e.ClaimToken == "" || e.ClaimToken != token || !e.OfferExpiresAt.After(now) {
+		return 0, domain.ErrOfferUnavailable
+	}
+	b := domain.Booking{
+		CustomerName:  e.CustomerName,
+		CustomerPhone: e.CustomerPhone,
+		ServiceID:     e.ServiceID,
+		StartAt:       *e.OfferStartAt,
+		EndAt:         *e.OfferEndAt,
+		Status:        domain.StatusPending,
+		CreatedAt:     now,
+	}
+	b.ID, err = u.waitlist.Claim(e.ID, token, b, u.capacity, now)
+	if err != nil {
+		return 0, err
+	}
+	b.Localize(u.loc)
+	_ = u.notifier.Notify(bookingEvent(domain.EventBookingCreated, b))
+	_ = u.logger.Log("waitlist_claimed", strconv.FormatInt(e.ID, 10)+": booking "+strconv.FormatInt(b.ID, 10), now)
+	return b.ID, nil
+}
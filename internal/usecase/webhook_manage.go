@@ -0,0 +1,187 @@
+package usecase
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/ports"
+	"be-golang/internal/util"
+)
+
+const webhookDeliveryLimit = 100
+
+func validateWebhook(s domain.WebhookSubscriber) error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return domain.ErrInvalidURL
+	}
+	for _, t := range s.EventTypes {
+		known := t == "*"
+		for _, k := range domain.EventTypes {
+			known = known || t == k
+		}
+		if !known {
+			return domain.ErrInvalidEventType
+		}
+	}
+	return nil
+}
+
+type WebhookList struct {
+	webhooks ports.WebhookRepository
+}
+
+func NewWebhookList(w ports.WebhookRepository) *WebhookList {
+	return &WebhookList{webhooks: w}
+}
+
+func (u *WebhookList) Exec() ([]domain.WebhookSubscriber, error) {
+	return u.webhooks.List()
+}
+
+type WebhookCreate struct {
+	webhooks ports.WebhookRepository
+	logger   ports.Logger
+}
+
+func NewWebhookCreate(w ports.WebhookRepository, l ports.Logger) *WebhookCreate {
+	return &WebhookCreate{webhooks: w, logger: l}
+}
+
+// Exec registers a subscriber. A signing secret is generated when none is
+// given; the stored subscriber is returned with its secret so the caller can
+// configure the receiving side. Listings do not show secrets.
+func (u *WebhookCreate) Exec(s domain.WebhookSubscriber) (*domain.WebhookSubscriber, error) {
+	if err := validateWebhook(s); err != nil {
+		return nil, err
+	}
+	if s.Secret == "" {
+		secret, err := util.RandomToken(32)
+		if err != nil {
+			return nil, err
+		}
+		s.Secret = secret
+	}
+	s.PreviousSecret = ""
+	s.CreatedAt = time.Now().UTC()
+	id, err := u.webhooks.Create(s)
+	if err != nil {
+		return nil, err
+	}
+	s.ID = id
+	_ = u.logger.Log("webhook_created", s.URL, s.CreatedAt)
+	return &s, nil
+}
+
+type WebhookUpdateInput struct {
+	URL          *string
+	EventTypes   *[]string
+	Active       *bool
+	Headers      *map[string]string
+	RotateSecret bool
+}
+
+type WebhookUpdate struct {
+	webhooks ports.WebhookRepository
+	logger   ports.Logger
+}
+
+func NewWebhookUpdate(w ports.WebhookRepository, l ports.Logger) *WebhookUpdate {
+	return &WebhookUpdate{webhooks: w, logger: l}
+}
+
+// Exec applies the set fields. RotateSecret generates a new secret and keeps
+// the current one as the previous secret, so deliveries are signed with both
+// until the next rotation.
+func (u *WebhookUpdate) Exec(id int64, in WebhookUpdateInput) (*domain.WebhookSubscriber, error) {
+	s, err := u.webhooks.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if in.URL != nil {
+		s.URL = *in.URL
+	}
+	if in.EventTypes != nil {
+		s.EventTypes = *in.EventTypes
+	}
+	if in.Active != nil {
+		s.Active = *in.Active
+	}
+	if in.Headers != nil {
+		s.Headers = *in.Headers
+	}
+	if in.RotateSecret {
+		secret, err := util.RandomToken(32)
+		if err != nil {
+			return nil, err
+		}
+		s.PreviousSecret, s.Secret = s.Secret, secret
+	}
+	if err := validateWebhook(*s); err != nil {
+		return nil, err
+	}
+	if err := u.webhooks.Update(*s); err != nil {
+		return nil, err
+	}
+	_ = u.logger.Log("webhook_updated", strconv.FormatInt(id, 10), time.Now().UTC())
+	return s, nil
+}
+
+type WebhookDelete struct {
+	webhooks ports.WebhookRepository
+	logger   ports.Logger
+}
+
+func NewWebhookDelete(w ports.WebhookRepository, l ports.Logger) *WebhookDelete {
+	return &WebhookDelete{webhooks: w, logger: l}
+}
+
+func (u *WebhookDelete) Exec(id int64) error {
+	if err := u.webhooks.Delete(id); err != nil {
+		return err
+	}
+	_ = u.logger.Log("webhook_deleted", strconv.FormatInt(id, 10), time.Now().UTC())
+	return nil
+}
+
+type WebhookDeliveries struct {
+	webhooks ports.WebhookRepository
+}
+
+func NewWebhookDeliveries(w ports.WebhookRepository) *WebhookDeliveries {
+	return &WebhookDeliveries{webhooks: w}
+}
+
+func (u *WebhookDeliveries) Exec(id int64) ([]domain.WebhookDelivery, error) {
+	if _, err := u.webhooks.GetByID(id); err != nil {
+		return nil, err
+	}
+	return u.webhooks.ListDeliveries(id, webhookDeliveryLimit)
+}
+
+type WebhookTest struct {
+	webhooks ports.WebhookRepository
+	sender   ports.WebhookSender
+}
+
+func NewWebhookTest(w ports.WebhookRepository, s ports.WebhookSender) *WebhookTest {
+	return &WebhookTest{webhooks: w, sender: s}
+}
+
+// Exec sends a webhook_test event to the subscriber even if it is inactive or
+// filters the type out, and returns the logged delivery. A failed delivery
+// is returned along with domain.ErrWebhookFailed.
+func (u *WebhookTest) Exec(id int64) (domain.WebhookDelivery, error) {
+	s, err := u.webhooks.GetByID(id)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	e := newEvent(domain.EventWebhookTest, domain.WebhookTestData{SubscriberID: s.ID, Message: "Test event"})
+	d, err := u.sender.Deliver(*s, e)
+	if err != nil {
+		return d, domain.ErrWebhookFailed.Wrap(err)
+	}
+	return d, nil
+}
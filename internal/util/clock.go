@@ -0,0 +1,52 @@
+package util
+
+import (
+	"time"
+
+	"be-golang/internal/domain"
+)
+
+var ErrInvalidClock = domain.NewError(domain.KindInvalid, "invalid_time")
+
+// ParseClock accepts a strict 24-hour "HH:MM" wall clock time.
+func ParseClock(s string) (int, int, error) {
+	if len(s) != 5 || s[2] != ':' {
+		return 0, 0, ErrInvalidClock
+	}
+	for _, i := range []int{0, 1, 3, 4} {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, 0, ErrInvalidClock
+		}
+	}
+	h := int(s[0]-'0')*10 + int(s[1]-'0')
+	m := int(s[3]-'0')*10 + int(s[4]-'0')
+	if h > 23 || m > 59 {
+		return 0, 0, ErrInvalidClock
+	}
+	return h, m, nil
+}
+
+// AtClock returns the instant of the given calendar date and wall clock time
+// in loc. Only the year, month and day of date are used.
+func AtClock(date time.Time, clock string, loc *time.Location) (time.Time, error) {
+	h, m, err := ParseClock(clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), h, m, 0, 0, loc), nil
+}
+
+// DayBounds returns the start of the day containing t in loc and the start of
+// the following day.
+func DayBounds(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	l := t.In(loc)
+	start := time.Date(l.Year(), l.Month(), l.Day(), 0, 0, 0, 0, loc)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// CalendarDate returns the date of t in loc as a UTC midnight value, the form
+// used for DATE columns and schedule lookups.
+func CalendarDate(t time.Time, loc *time.Location) time.Time {
+	l := t.In(loc)
+	return time.Date(l.Year(), l.Month(), l.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,256 @@
+package util
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"be-golang/internal/domain"
+)
+
+var ErrInvalidICal = domain.NewError(domain.KindInvalid, "invalid_ical")
+
+type ICalEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	// Sequence, Status and Stamp are only written, never parsed. A zero
+	// Stamp is written as the current time.
+	Sequence int
+	Status   string
+	Stamp    time.Time
+}
+
+// ParseICalEvents reads the VEVENT blocks of an iCalendar (RFC 5545) stream.
+// Only the properties needed for closures are kept. For all-day events End is
+// the exclusive DTEND as written in the file.
+func ParseICalEvents(r io.Reader) ([]ICalEvent, error) {
+	lines, err := unfoldICal(r)
+	if err != nil {
+		return nil, err
+	}
+	var out []ICalEvent
+	var cur *ICalEvent
+	for _, line := range lines {
+		name, params, value := splitICalLine(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			cur = &ICalEvent{}
+		case name == "END" && value == "VEVENT":
+			if cur == nil {
+				return nil, ErrInvalidICal
+			}
+			if cur.Start.IsZero() {
+				return nil, ErrInvalidICal
+			}
+			if cur.End.IsZero() {
+				if cur.AllDay {
+					cur.End = cur.Start.AddDate(0, 0, 1)
+				} else {
+					cur.End = cur.Start
+				}
+			}
+			out = append(out, *cur)
+			cur = nil
+		case cur == nil:
+			continue
+		case name == "UID":
+			cur.UID = value
+		case name == "SUMMARY":
+			cur.Summary = unescapeICalText(value)
+		case name == "DTSTART", name == "DTEND":
+			t, allDay, err := parseICalTime(params, value)
+			if err != nil {
+				return nil, ErrInvalidICal.Wrap(err)
+			}
+			if name == "DTSTART" {
+				cur.Start = t
+				cur.AllDay = allDay
+			} else {
+				cur.End = t
+			}
+		}
+	}
+	return out, nil
+}
+
+func unfoldICal(r io.Reader) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	var lines []string
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, sc.Err()
+}
+
+func splitICalLine(line string) (string, map[string]string, string) {
+	i := strings.Index(line, ":")
+	if i < 0 {
+		return strings.ToUpper(line), nil, ""
+	}
+	head, value := line[:i], line[i+1:]
+	parts := strings.Split(head, ";")
+	params := map[string]string{}
+	for _, p := range parts[1:] {
+		if k, v, ok := strings.Cut(p, "="); ok {
+			params[strings.ToUpper(k)] = v
+		}
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+func parseICalTime(params map[string]string, value string) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == 8 {
+		t, err := time.Parse("20060102", value)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, false, err
+	}
+	loc := time.UTC
+	if tz := params["TZID"]; tz != "" {
+		if l, err := time.LoadLocation(tz); err == nil {
+			loc = l
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+func unescapeICalText(v string) string {
+	r := strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return r.Replace(v)
+}
+
+// ICalendar is a VCALENDAR to be written by WriteICalendar. Method is
+// written when set (e.g. "REQUEST" for invitations). Name becomes the
+// display name calendar apps show for a subscribed feed. With a Location
+// other than UTC, timed events are written as local times in that zone;
+// otherwise they are written in UTC.
+type ICalendar struct {
+	ProdID   string
+	Method   string
+	Name     string
+	Location *time.Location
+	Events   []ICalEvent
+}
+
+func WriteICalendar(w io.Writer, cal ICalendar) error {
+	bw := bufio.NewWriter(w)
+	put := func(line string) {
+		writeICalLine(bw, line)
+	}
+	tzid := ""
+	if cal.Location != nil && cal.Location != time.UTC {
+		tzid = cal.Location.String()
+	}
+	put("BEGIN:VCALENDAR")
+	put("VERSION:2.0")
+	put("PRODID:" + cal.ProdID)
+	put("CALSCALE:GREGORIAN")
+	if cal.Method != "" {
+		put("METHOD:" + cal.Method)
+	}
+	if cal.Name != "" {
+		put("X-WR-CALNAME:" + escapeICalText(cal.Name))
+	}
+	if tzid != "" {
+		put("X-WR-TIMEZONE:" + tzid)
+		at := time.Now()
+		if len(cal.Events) > 0 {
+			at = cal.Events[0].Start
+		}
+		writeVTimezone(put, cal.Location, at)
+	}
+	for _, e := range cal.Events {
+		stamp := e.Stamp
+		if stamp.IsZero() {
+			stamp = time.Now()
+		}
+		put("BEGIN:VEVENT")
+		put("UID:" + e.UID)
+		put("DTSTAMP:" + stamp.UTC().Format("20060102T150405Z"))
+		switch {
+		case e.AllDay:
+			put("DTSTART;VALUE=DATE:" + e.Start.Format("20060102"))
+			put("DTEND;VALUE=DATE:" + e.End.Format("20060102"))
+		case tzid != "":
+			put("DTSTART;TZID=" + tzid + ":" + e.Start.In(cal.Location).Format("20060102T150405"))
+			put("DTEND;TZID=" + tzid + ":" + e.End.In(cal.Location).Format("20060102T150405"))
+		default:
+			put("DTSTART:" + e.Start.UTC().Format("20060102T150405Z"))
+			put("DTEND:" + e.End.UTC().Format("20060102T150405Z"))
+		}
+		put("SEQUENCE:" + strconv.Itoa(e.Sequence))
+		if e.Status != "" {
+			put("STATUS:" + e.Status)
+		}
+		put("SUMMARY:" + escapeICalText(e.Summary))
+		if e.Description != "" {
+			put("DESCRIPTION:" + escapeICalText(e.Description))
+		}
+		put("END:VEVENT")
+	}
+	put("END:VCALENDAR")
+	return bw.Flush()
+}
+
+// writeVTimezone describes loc by the single offset in effect at at. That is
+// exact for zones without daylight saving time, such as the Indonesian ones.
+func writeVTimezone(put func(string), loc *time.Location, at time.Time) {
+	name, offset := at.In(loc).Zone()
+	sign := "+"
+	if offset < 0 {
+		sign, offset = "-", -offset
+	}
+	off := fmt.Sprintf("%s%02d%02d", sign, offset/3600, offset%3600/60)
+	put("BEGIN:VTIMEZONE")
+	put("TZID:" + loc.String())
+	put("BEGIN:STANDARD")
+	put("DTSTART:19700101T000000")
+	put("TZOFFSETFROM:" + off)
+	put("TZOFFSETTO:" + off)
+	put("TZNAME:" + name)
+	put("END:STANDARD")
+	put("END:VTIMEZONE")
+}
+
+// writeICalLine folds line at 75 octets without splitting UTF-8 sequences
+// and terminates it with CRLF.
+func writeICalLine(w *bufio.Writer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+		line = line[cut:]
+		// continuation lines start with a space that counts towards the limit
+		limit = 74
+	}
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
+
+func escapeICalText(v string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(v)
+}
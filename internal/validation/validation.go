@@ -0,0 +1,148 @@
+// Package validation checks request input field by field and collects every
+// failure into a single domain.ValidationError.
+package validation
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"be-golang/internal/domain"
+	"be-golang/internal/util"
+)
+
+// Validator accumulates field errors. The zero value is ready to use.
+type Validator struct {
+	errs []domain.FieldError
+}
+
+// Add records a failed field. Only the first failure per field is kept, so
+// rules can be chained without repeating messages.
+func (v *Validator) Add(field, code string, params map[string]any) {
+	for _, e := range v.errs {
+		if e.Field == field {
+			return
+		}
+	}
+	v.errs = append(v.errs, domain.FieldError{Field: field, Code: code, Params: params})
+}
+
+// Err returns the collected failures, or nil if every rule passed.
+func (v *Validator) Err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return &domain.ValidationError{Fields: v.errs}
+}
+
+// Required fails when s is empty or only whitespace.
+func (v *Validator) Required(field, s string) bool {
+	if strings.TrimSpace(s) == "" {
+		v.Add(field, "required", nil)
+		return false
+	}
+	return true
+}
+
+// Name requires a trimmed length between domain.MinNameLength and
+// domain.MaxNameLength characters.
+func (v *Validator) Name(field, s string) {
+	if !v.Required(field, s) {
+		return
+	}
+	n := utf8.RuneCountInString(strings.TrimSpace(s))
+	switch {
+	case n < domain.MinNameLength:
+		v.Add(field, "too_short", map[string]any{"min": domain.MinNameLength})
+	case n > domain.MaxNameLength:
+		v.Add(field, "too_long", map[string]any{"max": domain.MaxNameLength})
+	}
+}
+
+// Phone accepts digits with an optional leading "+" and the usual
+// separators (spaces, dashes, dots, parentheses), e.g. "0812-3456-7890" or
+// "+62 812 3456 7890".
+func (v *Validator) Phone(field, s string) {
+	if !v.Required(field, s) {
+		return
+	}
+	digits := 0
+	for i, r := range strings.TrimSpace(s) {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '+' && i == 0, r == ' ', r == '-', r == '.', r == '(', r == ')':
+		default:
+			v.Add(field, "invalid_phone", nil)
+			return
+		}
+	}
+	if digits < domain.MinPhoneDigits || digits > domain.MaxPhoneDigits {
+		v.Add(field, "invalid_phone", nil)
+	}
+}
+
+// Date parses a "YYYY-MM-DD" calendar date.
+func (v *Validator) Date(field, s string) time.Time {
+	if !v.Required(field, s) {
+		return time.Time{}
+	}
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		v.Add(field, "invalid_date", nil)
+	}
+	return d
+}
+
+// Clock requires a 24-hour "HH:MM" time.
+func (v *Validator) Clock(field, s string) {
+	if !v.Required(field, s) {
+		return
+	}
+	if _, _, err := util.ParseClock(s); err != nil {
+		v.Add(field, "invalid_time", nil)
+	}
+}
+
+// ID requires a positive identifier.
+func (v *Validator) ID(field string, id int64) {
+	if id <= 0 {
+		v.Add(field, "required", nil)
+	}
+}
+
+// Range requires min <= n <= max.
+func (v *Validator) Range(field string, n, min, max int64) {
+	if n < min || n > max {
+		v.Add(field, "out_of_range", map[string]any{"min": min, "max": max})
+	}
+}
+
+// MaxLength limits s to max characters. Empty values are allowed.
+func (v *Validator) MaxLength(field, s string, max int) {
+	if utf8.RuneCountInString(s) > max {
+		v.Add(field, "too_long", map[string]any{"max": max})
+	}
+}
+
+// Count limits a list to max items.
+func (v *Validator) Count(field string, n, max int) {
+	if n > max {
+		v.Add(field, "too_many", map[string]any{"max": max})
+	}
+}
+
+// PromoCode allows domain.MinPromoCodeLength to domain.MaxPromoCodeLength
+// upper-case letters, digits, "-" and "_".
+func (v *Validator) PromoCode(field, s string) {
+	n := len(s)
+	ok := n >= domain.MinPromoCodeLength && n <= domain.MaxPromoCodeLength
+	for _, r := range s {
+		if !(r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			ok = false
+		}
+	}
+	if !ok {
+		v.Add(field, "invalid_code", map[string]any{"min": domain.MinPromoCodeLength, "max": domain.MaxPromoCodeLength})
+	}
+}
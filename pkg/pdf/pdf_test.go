@@ -0,0 +1,163 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parse checks the cross-reference table and trailer of a written document
+// and returns its objects by number.
+func parse(t *testing.T, data []byte) map[int]string {
+	t.Helper()
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) {
+		t.Fatalf("missing header: %q", data[:min(len(data), 16)])
+	}
+	if !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatal("missing end-of-file marker")
+	}
+	m := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(data)
+	if m == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(data[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", xref)
+	}
+	lines := strings.Split(string(data[xref:]), "\n")
+	var first, count int
+	if _, err := fmt.Sscanf(lines[1], "%d %d", &first, &count); err != nil || first != 0 {
+		t.Fatalf("bad xref subsection %q", lines[1])
+	}
+	if !strings.Contains(string(data[xref:]), fmt.Sprintf("/Size %d ", count)) {
+		t.Errorf("trailer /Size does not match %d xref entries", count)
+	}
+	objects := map[int]string{}
+	for n := 1; n < count; n++ {
+		entry := lines[2+n]
+		if len(entry) != 19 || !strings.HasSuffix(entry, " n ") {
+			t.Fatalf("bad xref entry %d: %q", n, entry)
+		}
+		off, _ := strconv.Atoi(entry[:10])
+		head := fmt.Sprintf("%d 0 obj\n", n)
+		if !bytes.HasPrefix(data[off:], []byte(head)) {
+			t.Fatalf("xref entry %d points at %q", n, data[off:min(len(data), off+20)])
+		}
+		end := bytes.Index(data[off:], []byte("\nendobj\n"))
+		if end < 0 {
+			t.Fatalf("object %d is not closed", n)
+		}
+		objects[n] = string(data[off+len(head) : off+end])
+	}
+	return objects
+}
+
+// content inflates a content stream object and checks its /Length.
+func content(t *testing.T, obj string) string {
+	t.Helper()
+	m := regexp.MustCompile(`(?s)^<< /Length (\d+) /Filter /FlateDecode >>\nstream\n(.*)\nendstream$`).FindStringSubmatch(obj)
+	if m == nil {
+		t.Fatalf("not a content stream: %.60q", obj)
+	}
+	if n, _ := strconv.Atoi(m[1]); n != len(m[2]) {
+		t.Fatalf("/Length %d, stream has %d bytes", n, len(m[2]))
+	}
+	r, err := zlib.NewReader(strings.NewReader(m[2]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestWrite(t *testing.T) {
+	doc := New("Invoice (draft)")
+	doc.Author = "Salon Café"
+	doc.Created = time.Date(2026, 5, 10, 3, 4, 5, 0, time.UTC)
+	p := doc.AddPage()
+	p.Text(50, 70, HelveticaBold, 18, "Salon Café")
+	p.TextRight(545, 100, Helvetica, 10, `Total (incl. tax) \ Rp150.000`)
+	p.Line(50, 110, 545, 110, 0.5)
+	p.FillRect(50, 120, 495, 18, 0.9)
+	doc.AddPage().Text(50, 70, Helvetica, 10, "Page 2 ✓")
+
+	data, err := doc.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects := parse(t, data)
+	if len(objects) != 9 {
+		t.Fatalf("got %d objects, want 9", len(objects))
+	}
+	if objects[1] != "<< /Type /Catalog /Pages 2 0 R >>" {
+		t.Errorf("catalog = %q", objects[1])
+	}
+	if objects[2] != "<< /Type /Pages /Kids [6 0 R 8 0 R] /Count 2 >>" {
+		t.Errorf("page tree = %q", objects[2])
+	}
+	if want := `/Title (Invoice \(draft\)) /Author (Salon Caf` + "\xe9" + `) /Producer (be-golang) /CreationDate (D:20260510030405Z)`; !strings.Contains(objects[3], want) {
+		t.Errorf("info = %q", objects[3])
+	}
+	for _, n := range []int{6, 8} {
+		want := fmt.Sprintf("/Parent 2 0 R /MediaBox [0 0 595.28 841.89] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents %d 0 R", n+1)
+		if !strings.Contains(objects[n], want) {
+			t.Errorf("page %d = %q", n, objects[n])
+		}
+	}
+
+	first := content(t, objects[7])
+	for _, want := range []string{
+		"BT /F2 18 Tf 50 771.89 Td (Salon Caf\xe9) Tj ET",
+		`(Total \(incl. tax\) \\ Rp150.000) Tj`,
+		"0.5 w 50 731.89 m 545 731.89 l S",
+		"q 0.9 g 50 703.89 495 18 re f Q",
+	} {
+		if !strings.Contains(first, want) {
+			t.Errorf("page 1 content lacks %q:\n%s", want, first)
+		}
+	}
+	if second := content(t, objects[9]); !strings.Contains(second, "(Page 2 ?) Tj") {
+		t.Errorf("page 2 content = %q", second)
+	}
+}
+
+func TestWriteEmptyDocument(t *testing.T) {
+	data, err := New("").Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects := parse(t, data)
+	if !strings.Contains(objects[2], "/Count 1") {
+		t.Errorf("page tree = %q", objects[2])
+	}
+	if c := content(t, objects[7]); c != "" {
+		t.Errorf("blank page has content %q", c)
+	}
+}
+
+func TestTextRightEndsAtX(t *testing.T) {
+	p := &Page{}
+	p.TextRight(300, 100, Helvetica, 10, "Rp1.000")
+	// "Rp1.000" is 722+556+556+278+556+556+556 = 3780/1000 em wide.
+	if got := p.buf.String(); !strings.Contains(got, " 262.2 741.89 Td ") {
+		t.Errorf("TextRight = %q", got)
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	if got := TextWidth(Helvetica, 10, "Rp1.000"); got != 37.8 {
+		t.Errorf("Helvetica width = %v, want 37.8", got)
+	}
+	if got := TextWidth(HelveticaBold, 10, "Total"); got != 23.89 {
+		t.Errorf("Helvetica-Bold width = %v, want 23.89", got)
+	}
+}
@@ -0,0 +1,136 @@
+// Package webhooksig signs and verifies webhook deliveries.
+//
+// A delivery carries two headers: X-Webhook-Timestamp with the Unix time of
+// sending, and X-Webhook-Signature with one or more "v1=<hex>" entries
+// separated by commas. Each entry is HMAC-SHA256 over "<timestamp>.<body>"
+// with one active secret, so receivers keep working while a secret is being
+// rotated.
+package webhooksig
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderTimestamp  = "X-Webhook-Timestamp"
+	HeaderSignature  = "X-Webhook-Signature"
+	DefaultTolerance = 5 * time.Minute
+	MaxSecrets       = 2
+	scheme           = "v1"
+)
+
+var (
+	ErrMissingHeaders     = errors.New("webhooksig: missing signature headers")
+	ErrInvalidTimestamp   = errors.New("webhooksig: invalid timestamp")
+	ErrTimestampTolerance = errors.New("webhooksig: timestamp outside tolerance")
+	ErrNoSecrets          = errors.New("webhooksig: no secrets configured")
+	ErrSignatureMismatch  = errors.New("webhooksig: signature mismatch")
+)
+
+// Sign returns the hex HMAC-SHA256 of "<ts>.<body>" under secret.
+func Sign(secret string, ts int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(ts, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignatureHeader builds the X-Webhook-Signature value for up to MaxSecrets
+// non-empty secrets.
+func SignatureHeader(secrets []string, ts int64, body []byte) string {
+	var parts []string
+	for _, s := range activeSecrets(secrets) {
+		parts = append(parts, scheme+"="+Sign(s, ts, body))
+	}
+	return strings.Join(parts, ",")
+}
+
+// SignRequest sets both signature headers on req for body. It does nothing
+// when no secret is configured.
+func SignRequest(req *http.Request, secrets []string, body []byte, now time.Time) {
+	if len(activeSecrets(secrets)) == 0 {
+		return
+	}
+	ts := now.Unix()
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderSignature, SignatureHeader(secrets, ts, body))
+}
+
+// Verify checks that one of the signatures in sigHeader matches one of the
+// secrets and that the timestamp is within tolerance of now. A tolerance of
+// zero uses DefaultTolerance.
+func Verify(secrets []string, tsHeader, sigHeader string, body []byte, tolerance time.Duration, now time.Time) error {
+	active := activeSecrets(secrets)
+	if len(active) == 0 {
+		return ErrNoSecrets
+	}
+	if tsHeader == "" || sigHeader == "" {
+		return ErrMissingHeaders
+	}
+	ts, err := strconv.ParseInt(tsHeader, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	if d := now.Sub(time.Unix(ts, 0)); d > tolerance || d < -tolerance {
+		return ErrTimestampTolerance
+	}
+	for _, part := range strings.Split(sigHeader, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || k != scheme {
+			continue
+		}
+		got, err := hex.DecodeString(v)
+		if err != nil {
+			continue
+		}
+		for _, s := range active {
+			want, _ := hex.DecodeString(Sign(s, ts, body))
+			if hmac.Equal(got, want) {
+				return nil
+			}
+		}
+	}
+	return ErrSignatureMismatch
+}
+
+// VerifyRequest reads and verifies the body of r. The body is restored so
+// later handlers can read it again.
+func VerifyRequest(r *http.Request, secrets []string, tolerance time.Duration) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	err = Verify(secrets, r.Header.Get(HeaderTimestamp), r.Header.Get(HeaderSignature), body, tolerance, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+func activeSecrets(secrets []string) []string {
+	var out []string
+	for _, s := range secrets {
+		if s != "" {
+			out = append(out, s)
+		}
+		if len(out) == MaxSecrets {
+			break
+		}
+	}
+	return out
+}
@@ -0,0 +1,127 @@
+package webhooksig
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	body = []byte(`{"id":"evt-1"}`)
+	ts   = int64(1700000000)
+	now  = time.Unix(ts, 0)
+)
+
+func TestSignKnownVector(t *testing.T) {
+	const want = "5056f09710e0bebdbcd623bb1a7714db4eac94f18745b31b96dd55a69f444e14"
+	if got := Sign("whsec_test", ts, body); got != want {
+		t.Errorf("Sign = %s, want %s", got, want)
+	}
+}
+
+func TestSignatureHeader(t *testing.T) {
+	tests := []struct {
+		secrets []string
+		want    int
+	}{
+		{nil, 0},
+		{[]string{""}, 0},
+		{[]string{"a"}, 1},
+		{[]string{"", "a"}, 1},
+		{[]string{"a", "b"}, 2},
+		{[]string{"a", "b", "c"}, MaxSecrets},
+	}
+	for _, tt := range tests {
+		h := SignatureHeader(tt.secrets, ts, body)
+		var n int
+		if h != "" {
+			n = len(strings.Split(h, ","))
+		}
+		if n != tt.want {
+			t.Errorf("SignatureHeader(%q) = %q, want %d entries", tt.secrets, h, tt.want)
+		}
+		for _, part := range strings.Split(h, ",") {
+			if h != "" && !strings.HasPrefix(part, "v1=") {
+				t.Errorf("entry %q lacks the v1 scheme", part)
+			}
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	tsHeader := strconv.FormatInt(ts, 10)
+	rotating := SignatureHeader([]string{"new", "old"}, ts, body)
+	tests := []struct {
+		name      string
+		secrets   []string
+		tsHeader  string
+		sigHeader string
+		body      []byte
+		now       time.Time
+		want      error
+	}{
+		{"valid", []string{"whsec_test"}, tsHeader, "v1=" + Sign("whsec_test", ts, body), body, now, nil},
+		{"receiver has old secret during rotation", []string{"old"}, tsHeader, rotating, body, now, nil},
+		{"receiver has new secret during rotation", []string{"new"}, tsHeader, rotating, body, now, nil},
+		{"unknown scheme and junk are skipped", []string{"a"}, tsHeader, "v0=00, v1=zz ,v1=" + Sign("a", ts, body), body, now, nil},
+		{"edge of tolerance", []string{"a"}, tsHeader, "v1=" + Sign("a", ts, body), body, now.Add(DefaultTolerance), nil},
+		{"wrong secret", []string{"b"}, tsHeader, "v1=" + Sign("a", ts, body), body, now, ErrSignatureMismatch},
+		{"tampered body", []string{"a"}, tsHeader, "v1=" + Sign("a", ts, body), []byte(`{"id":"evt-2"}`), now, ErrSignatureMismatch},
+		{"replayed timestamp", []string{"a"}, "1700000001", "v1=" + Sign("a", ts, body), body, now, ErrSignatureMismatch},
+		{"too old", []string{"a"}, tsHeader, "v1=" + Sign("a", ts, body), body, now.Add(DefaultTolerance + time.Second), ErrTimestampTolerance},
+		{"from the future", []string{"a"}, tsHeader, "v1=" + Sign("a", ts, body), body, now.Add(-DefaultTolerance - time.Second), ErrTimestampTolerance},
+		{"bad timestamp", []string{"a"}, "yesterday", "v1=" + Sign("a", ts, body), body, now, ErrInvalidTimestamp},
+		{"missing signature", []string{"a"}, tsHeader, "", body, now, ErrMissingHeaders},
+		{"missing timestamp", []string{"a"}, "", "v1=" + Sign("a", ts, body), body, now, ErrMissingHeaders},
+		{"no secrets", []string{""}, tsHeader, "v1=" + Sign("", ts, body), body, now, ErrNoSecrets},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secrets, tt.tsHeader, tt.sigHeader, tt.body, 0, tt.now)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyCustomTolerance(t *testing.T) {
+	sig := "v1=" + Sign("a", ts, body)
+	late := now.Add(2 * time.Minute)
+	if err := Verify([]string{"a"}, strconv.FormatInt(ts, 10), sig, body, time.Minute, late); !errors.Is(err, ErrTimestampTolerance) {
+		t.Errorf("Verify = %v, want %v", err, ErrTimestampTolerance)
+	}
+}
+
+func TestSignAndVerifyRequest(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "https://example.com/hook", bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	SignRequest(req, []string{"a", "b"}, body, time.Now())
+	if req.Header.Get(HeaderTimestamp) == "" || req.Header.Get(HeaderSignature) == "" {
+		t.Fatalf("headers not set: %v", req.Header)
+	}
+	got, err := VerifyRequest(req, []string{"b"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, body) {
+		t.Errorf("body = %s, want %s", got, body)
+	}
+	again, _ := io.ReadAll(req.Body)
+	if !bytes.Equal(again, body) {
+		t.Errorf("body not restored: %s", again)
+	}
+
+	unsigned, _ := http.NewRequest(http.MethodPost, "https://example.com/hook", bytes.NewReader(body))
+	SignRequest(unsigned, []string{""}, body, time.Now())
+	if len(unsigned.Header) != 0 {
+		t.Errorf("signed without a secret: %v", unsigned.Header)
+	}
+}